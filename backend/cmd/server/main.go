@@ -1,100 +1,242 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"appdirect-ai-workshop/internal/config"
 	"appdirect-ai-workshop/internal/handlers"
+	"appdirect-ai-workshop/internal/lifecycle"
+	"appdirect-ai-workshop/internal/logging"
+	"appdirect-ai-workshop/internal/metrics"
 	"appdirect-ai-workshop/internal/middleware"
 	"appdirect-ai-workshop/internal/services"
-
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"appdirect-ai-workshop/internal/tracing"
 )
 
 func main() {
-	// Load environment variables from project root
-	// Try current directory first, then parent directory
-	if err := godotenv.Load(); err != nil {
-		if err := godotenv.Load("../.env"); err != nil {
-			log.Println("No .env file found, using environment variables")
-		}
+	// Configuration is loaded and validated once; nothing below reads the
+	// environment directly
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	logger := logging.Setup(cfg.LogLevel)
+	if cfg.MetricsPublic {
+		logger.Warn("METRICS_PUBLIC is set; /metrics is served to anyone")
+	}
+
+	// Tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracesExporter)
+	if err != nil {
+		logger.Error("Failed to initialize tracing", "error", err)
+		os.Exit(1)
 	}
 
 	// Initialize Firestore service
-	firestoreService, err := services.NewFirestoreService()
+	firestoreService, err := services.NewFirestoreService(cfg.Firestore)
 	if err != nil {
-		log.Fatalf("Failed to initialize Firestore: %v", err)
+		logger.Error("Failed to initialize Firestore", "error", err)
+		os.Exit(1)
 	}
 
-	// Initialize handlers
-	attendeeHandler := handlers.NewAttendeeHandler(firestoreService)
-	speakerHandler := handlers.NewSpeakerHandler(firestoreService)
-	sessionHandler := handlers.NewSessionHandler(firestoreService)
-	adminHandler := handlers.NewAdminHandler(firestoreService)
+	// Background workers are stopped after the HTTP server has drained
+	workers := lifecycle.NewWorkers()
+
+	// Metrics
+	appMetrics := metrics.New()
+	firestoreService.SetMetrics(appMetrics)
+
+	// Live updates. The change feed also keeps the attendee search index
+	// and gauge current.
+	eventHub := services.NewEventHub(cfg.Live.MaxClients, 500)
+	attendeeIndex := services.NewAttendeeIndex()
+	changeFeed := services.NewChangeFeed(firestoreService, eventHub, attendeeIndex, appMetrics)
+	workers.Go("change-feed", changeFeed.Run)
+
+	// Outbound webhooks, delivered from a queue shared by every instance
+	webhookService := services.NewWebhookService(firestoreService, cfg.Webhooks.AllowInsecure)
+	workers.Go("webhook-deliveries", webhookService.Run)
+
+	// Domain events are written to the outbox with the change they describe
+	// and dispatched to these subscribers. Subscriber names are recorded
+	// against handled events, so they must not be renamed.
+	outbox := services.NewOutbox(firestoreService)
+	outbox.Subscribe("audit-log", services.AuditLog)
+	outbox.Subscribe("webhooks", webhookService.HandleEvent)
+	workers.Go("outbox-dispatcher", outbox.Run)
+
+	// Rate limiting. The store also remembers solved challenges.
+	var rateLimitStore middleware.RateLimitStore = middleware.NewMemoryRateLimitStore()
+	if cfg.RateLimit.Store == "firestore" {
+		rateLimitStore = services.NewFirestoreRateLimitStore(firestoreService)
+	}
 
-	// Setup router
-	router := gin.Default()
+	// Initialize handlers
+	attendeeHandler := handlers.NewAttendeeHandler(firestoreService, newBotGuard(cfg, rateLimitStore), outbox, attendeeIndex, appMetrics)
+	speakerHandler := handlers.NewSpeakerHandler(firestoreService, outbox)
+	sessionHandler := handlers.NewSessionHandler(firestoreService, outbox, cfg.Event.Location())
+	questionHandler := handlers.NewQuestionHandler(firestoreService)
+	designationHandler := handlers.NewDesignationHandler(firestoreService, outbox)
+	venueHandler := handlers.NewVenueHandler(firestoreService, outbox)
+	trackHandler := handlers.NewTrackHandler(firestoreService, outbox)
+	statsService := services.NewStatsService(firestoreService, cfg.Stats.CacheTTL, cfg.Event.Location())
+	adminSessions := middleware.NewAdminSessions(cfg.Admin)
+	adminHandler := handlers.NewAdminHandler(firestoreService, statsService, cfg.Admin, adminSessions)
+	privacyService := services.NewPrivacyService(firestoreService, services.DefaultPersonalData)
+	magicLinks := newMagicLinks(cfg)
+	// Magic links are mailed in the background so the response does not
+	// reveal whether the address is registered
+	mailQueue := services.NewMailQueue(newMailer(cfg, logger), 100, 30*time.Second)
+	workers.Go("mail", mailQueue.Run)
+	selfServiceHandler := handlers.NewSelfServiceHandler(firestoreService, magicLinks, mailQueue, privacyService, outbox, appMetrics)
+	feedbackHandler := handlers.NewFeedbackHandler(firestoreService, magicLinks, cfg.Feedback)
+	attendanceHandler := handlers.NewAttendanceHandler(firestoreService, magicLinks)
+	qaHandler := handlers.NewQAHandler(firestoreService, magicLinks, cfg.QA)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	eventHandler := handlers.NewEventHandler(eventHub)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, cfg.Webhooks.AllowInsecure)
+	outboxHandler := handlers.NewOutboxHandler(outbox)
+
+	// The configuration is validated once at startup and cannot change, so
+	// only dependencies are checked
+	healthHandler := handlers.NewHealthHandler(3*time.Second,
+		handlers.HealthCheck{Name: "firestore", Check: firestoreService.Ping},
+	)
+
+	router := newRouter(cfg, logger, routeDeps{
+		attendees:      attendeeHandler,
+		speakers:       speakerHandler,
+		sessions:       sessionHandler,
+		questions:      questionHandler,
+		designations:   designationHandler,
+		venues:         venueHandler,
+		tracks:         trackHandler,
+		admin:          adminHandler,
+		selfService:    selfServiceHandler,
+		feedback:       feedbackHandler,
+		attendance:     attendanceHandler,
+		qa:             qaHandler,
+		privacy:        privacyHandler,
+		health:         healthHandler,
+		events:         eventHandler,
+		webhooks:       webhookHandler,
+		outbox:         outboxHandler,
+		metrics:        appMetrics,
+		rateLimitStore: rateLimitStore,
+		adminSessions:  adminSessions,
+	})
 
-	// CORS configuration
-	corsOrigin := os.Getenv("CORS_ORIGIN")
-	if corsOrigin == "" {
-		corsOrigin = "http://localhost:5173"
+	// Start server
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
 	}
+	// Live event streams never go idle, so Shutdown would wait them out
+	server.RegisterOnShutdown(eventHub.Close)
 
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{corsOrigin},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-	}))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Public routes
-	api := router.Group("/api")
-	{
-		// Attendees
-		api.GET("/attendees", attendeeHandler.GetAttendees)
-		api.GET("/attendees/count", attendeeHandler.GetCount)
-		api.POST("/attendees", attendeeHandler.CreateAttendee)
-
-		// Speakers (public read)
-		api.GET("/speakers", speakerHandler.GetSpeakers)
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server starting", "port", cfg.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	exitCode := 0
+	select {
+	case err := <-serverErr:
+		logger.Error("Failed to start server", "error", err)
+		exitCode = 1
+	case <-ctx.Done():
+		logger.Info("Shutdown signal received, draining requests")
+	}
+	healthHandler.Drain()
 
-		// Sessions (public read)
-		api.GET("/sessions", sessionHandler.GetSessions)
+	// Cloud Run allows 10 seconds between SIGTERM and SIGKILL
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
 
-		// Admin login
-		api.POST("/admin/login", adminHandler.Login)
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("HTTP server did not drain in time", "error", err)
+		exitCode = 1
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		logger.Error("Background workers did not stop in time", "error", err)
+		exitCode = 1
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Failed to flush traces", "error", err)
+	}
+	if err := firestoreService.Close(); err != nil {
+		logger.Error("Failed to close Firestore client", "error", err)
 	}
 
-	// Protected admin routes
-	admin := api.Group("/admin")
-	admin.Use(middleware.AdminAuth())
-	{
-		admin.GET("/stats", adminHandler.GetStats)
+	logger.Info("Server stopped")
+	os.Exit(exitCode)
+}
 
-		// Speaker management
-		admin.POST("/speakers", speakerHandler.CreateSpeaker)
-		admin.PUT("/speakers/:id", speakerHandler.UpdateSpeaker)
-		admin.DELETE("/speakers/:id", speakerHandler.DeleteSpeaker)
+// newMagicLinks returns nil, disabling attendee self-service, unless a
+// magic-link secret is configured.
+func newMagicLinks(cfg *config.Config) *services.MagicLinks {
+	if cfg.SelfService.Secret == "" {
+		return nil
+	}
+	return services.NewMagicLinks([]byte(cfg.SelfService.Secret), cfg.SelfService.TTL, cfg.SelfService.BaseURL)
+}
 
-		// Session management
-		admin.POST("/sessions", sessionHandler.CreateSession)
-		admin.PUT("/sessions/:id", sessionHandler.UpdateSession)
-		admin.DELETE("/sessions/:id", sessionHandler.DeleteSession)
+// newMailer sends email through SMTP when a host is configured and logs it
+// otherwise.
+func newMailer(cfg *config.Config, logger *slog.Logger) services.Mailer {
+	if cfg.Mail.SMTPHost == "" {
+		if cfg.SelfService.Secret != "" {
+			logger.Warn("SMTP_HOST is not set; magic links are logged instead of emailed")
+		}
+		return services.LogMailer{}
 	}
+	return &services.SMTPMailer{
+		Host:     cfg.Mail.SMTPHost,
+		Port:     cfg.Mail.SMTPPort,
+		Username: cfg.Mail.SMTPUsername,
+		Password: cfg.Mail.SMTPPassword,
+		From:     cfg.Mail.From,
+	}
+}
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+// newBotGuard configures bot protection for the public registration form.
+// The honeypot check is always on; proof-of-work and the submission timing
+// check need a challenge secret, and an external CAPTCHA needs a CAPTCHA
+// secret.
+func newBotGuard(cfg *config.Config, claims services.TokenClaims) *services.BotGuard {
+	guard := &services.BotGuard{MinFillTime: cfg.Challenge.MinFillTime}
+
+	if cfg.Challenge.Secret != "" {
+		guard.PoW = services.NewProofOfWork([]byte(cfg.Challenge.Secret), cfg.Challenge.Difficulty, 30*time.Minute, claims)
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if cfg.Captcha.Secret != "" {
+		guard.Captcha = &services.SiteVerifyCaptcha{
+			VerifyURL: cfg.Captcha.VerifyURL,
+			Secret:    cfg.Captcha.Secret,
+			SiteKey:   cfg.Captcha.SiteKey,
+		}
 	}
-}
 
+	return guard
+}
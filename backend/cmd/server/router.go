@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"appdirect-ai-workshop/internal/apidocs"
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/config"
+	"appdirect-ai-workshop/internal/handlers"
+	"appdirect-ai-workshop/internal/metrics"
+	"appdirect-ai-workshop/internal/middleware"
+	"appdirect-ai-workshop/internal/tracing"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// routeDeps holds the handlers and shared services the router is built from.
+type routeDeps struct {
+	attendees    *handlers.AttendeeHandler
+	speakers     *handlers.SpeakerHandler
+	sessions     *handlers.SessionHandler
+	questions    *handlers.QuestionHandler
+	designations *handlers.DesignationHandler
+	venues       *handlers.VenueHandler
+	tracks       *handlers.TrackHandler
+	admin        *handlers.AdminHandler
+	selfService  *handlers.SelfServiceHandler
+	feedback     *handlers.FeedbackHandler
+	attendance   *handlers.AttendanceHandler
+	qa           *handlers.QAHandler
+	privacy      *handlers.PrivacyHandler
+	health       *handlers.HealthHandler
+	events       *handlers.EventHandler
+	webhooks     *handlers.WebhookHandler
+	outbox       *handlers.OutboxHandler
+
+	metrics        *metrics.Metrics
+	rateLimitStore middleware.RateLimitStore
+	adminSessions  *middleware.AdminSessions
+}
+
+// newRouter registers every route. Routes added here must also be described
+// in internal/apidocs/openapi.yaml; TestRoutesDocumented enforces it.
+func newRouter(cfg *config.Config, logger *slog.Logger, deps routeDeps) *gin.Engine {
+	router := gin.New()
+
+	// Per-IP rate limits and the login lockout key on c.ClientIP(), so only
+	// configured proxies may set it. config.Validate rejects bad entries.
+	if err := router.SetTrustedProxies(cfg.ClientIP.TrustedProxies); err != nil {
+		panic(err)
+	}
+	switch cfg.ClientIP.TrustedPlatform {
+	case "appengine":
+		router.TrustedPlatform = gin.PlatformGoogleAppEngine
+	case "cloudflare":
+		router.TrustedPlatform = gin.PlatformCloudflare
+	}
+
+	// Health probes are registered before any middleware so they bypass
+	// CORS, auth, rate limiting and request logging.
+	router.GET("/healthz", deps.health.Liveness)
+	router.GET("/readyz", deps.health.Readiness)
+
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	router.Use(middleware.RequestID(logger, cfg.Firestore.ProjectID))
+	router.Use(middleware.RequestLogger())
+	router.Use(deps.metrics.Middleware())
+	router.Use(gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		// The request logger records the panic with the request's fields
+		apierror.Abort(c, apierror.Internal(fmt.Errorf("panic: %v", recovered)))
+	}))
+	router.NoRoute(func(c *gin.Context) {
+		apierror.Abort(c, apierror.NotFound("Route not found"))
+	})
+
+	// CORS configuration
+	router.Use(cors.New(cors.Config{
+		AllowOrigins:     cfg.CORSOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.RequestIDHeader, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader},
+		AllowCredentials: true,
+	}))
+
+	// Prometheus metrics, only served with a token or when explicitly public
+	router.GET("/metrics", deps.metrics.Handler(cfg.MetricsToken, cfg.MetricsPublic))
+
+	// Rate limiting
+	store := deps.rateLimitStore
+	publicLimit := middleware.RateLimit(store,
+		middleware.RateLimitRule{Name: "public-ip", Limit: middleware.PerMinute(120, 60), Key: middleware.ClientIPKey},
+	)
+	registrationLimit := middleware.RateLimit(store,
+		middleware.RateLimitRule{Name: "register-ip", Limit: middleware.PerMinute(5, 10), Key: middleware.ClientIPKey},
+		middleware.RateLimitRule{Name: "register-email", Limit: middleware.PerMinute(1, 3), Key: middleware.JSONFieldKey("email")},
+	)
+	// No global login bucket: anyone could drain it and lock the admin out
+	loginLimit := middleware.RateLimit(store,
+		middleware.RateLimitRule{Name: "login-ip", Limit: middleware.PerMinute(10, 10), Key: middleware.ClientIPKey},
+	)
+	magicLinkLimit := middleware.RateLimit(store,
+		middleware.RateLimitRule{Name: "magic-link-ip", Limit: middleware.PerMinute(5, 10), Key: middleware.ClientIPKey},
+		middleware.RateLimitRule{Name: "magic-link-email", Limit: middleware.PerMinute(1, 3), Key: middleware.JSONFieldKey("email")},
+	)
+	askLimit := middleware.RateLimit(store,
+		middleware.RateLimitRule{Name: "qa-ask", Limit: middleware.PerMinute(cfg.QA.QuestionsPerMinute, cfg.QA.QuestionsPerMinute), Key: middleware.ContextKey(handlers.AttendeeIDKey)},
+	)
+	voteLimit := middleware.RateLimit(store,
+		middleware.RateLimitRule{Name: "qa-vote", Limit: middleware.PerMinute(cfg.QA.VotesPerMinute, cfg.QA.VotesPerMinute), Key: middleware.ContextKey(handlers.AttendeeIDKey)},
+	)
+	loginLockout := middleware.LoginLockout(store, middleware.DefaultLockoutPolicy, middleware.ClientIPKey)
+
+	// Public routes
+	api := router.Group("/api")
+	api.Use(publicLimit)
+	{
+		// API documentation
+		api.GET("/openapi.json", apidocs.SpecHandler())
+		api.GET("/docs", apidocs.UIHandler())
+
+		// Attendees
+		api.GET("/attendees/form", deps.questions.GetForm)
+		api.GET("/attendees/public", deps.attendees.GetPublicAttendees)
+		api.GET("/attendees/count", deps.attendees.GetCount)
+		api.GET("/attendees/challenge", deps.attendees.GetChallenge)
+		api.POST("/attendees", registrationLimit, deps.attendees.CreateAttendee)
+
+		// Attendee self-service, authenticated by a magic-link token
+		api.POST("/attendees/magic-link", magicLinkLimit, deps.selfService.RequestMagicLink)
+		api.GET("/attendees/me", deps.selfService.GetRegistration)
+		api.PUT("/attendees/me", deps.selfService.UpdateRegistration)
+		api.DELETE("/attendees/me", deps.selfService.CancelRegistration)
+		api.GET("/attendees/me/export", deps.selfService.ExportData)
+		api.POST("/attendees/me/erasure", deps.selfService.RequestErasure)
+		api.GET("/attendees/me/feedback", deps.feedback.GetMyFeedback)
+		api.PUT("/attendees/me/feedback/:sessionId", deps.feedback.SubmitFeedback)
+		api.GET("/attendees/me/attendance", deps.attendance.GetMyAttendance)
+
+		// Designations offered on the registration form
+		api.GET("/designations", deps.designations.GetOptions)
+
+		// Speakers (public read)
+		api.GET("/speakers", deps.speakers.GetSpeakers)
+
+		// Feedback on a speaker's sessions, authenticated by a speaker link
+		api.GET("/speakers/me/feedback", deps.feedback.GetSpeakerFeedback)
+		api.GET("/speakers/me/feedback/export", deps.feedback.ExportSpeakerFeedback)
+
+		// Sessions (public read)
+		api.GET("/sessions", deps.sessions.GetSessions)
+		api.GET("/agenda", deps.sessions.GetAgenda)
+
+		// Live Q&A; asking and voting need a magic-link token and are
+		// limited per attendee
+		api.GET("/sessions/:id/questions", deps.qa.GetQuestions)
+		api.POST("/sessions/:id/questions", deps.qa.Authenticate, askLimit, deps.qa.AskQuestion)
+		api.PUT("/sessions/:id/questions/:questionId/vote", deps.qa.Authenticate, voteLimit, deps.qa.Vote)
+		api.DELETE("/sessions/:id/questions/:questionId/vote", deps.qa.Authenticate, voteLimit, deps.qa.Unvote)
+
+		// Venues, rooms and tracks (public read)
+		api.GET("/venues", deps.venues.GetVenues)
+		api.GET("/tracks", deps.tracks.GetTracks)
+
+		// Live count and agenda updates
+		api.GET("/events", deps.events.Stream)
+
+		// Admin login
+		api.POST("/admin/login", loginLimit, loginLockout, deps.admin.Login)
+	}
+
+	// Protected admin routes
+	admin := api.Group("/admin")
+	admin.Use(middleware.AdminAuth(deps.adminSessions))
+	{
+		admin.GET("/stats", deps.admin.GetStats)
+		admin.GET("/events", deps.events.AdminStream)
+		admin.GET("/attendees", deps.attendees.GetAttendees)
+		admin.GET("/attendees/search", deps.attendees.SearchAttendees)
+		admin.POST("/attendees/:id/check-in", deps.attendees.CheckIn)
+		admin.POST("/attendees/backfill-emails", deps.attendees.BackfillEmails)
+
+		// Data export and erasure
+		admin.GET("/privacy/requests", deps.privacy.GetRequests)
+		admin.POST("/privacy/export", deps.privacy.Export)
+		admin.POST("/privacy/erasure", deps.privacy.Erase)
+
+		// Speaker management
+		admin.POST("/speakers", deps.speakers.CreateSpeaker)
+		admin.PUT("/speakers/:id", deps.speakers.UpdateSpeaker)
+		admin.DELETE("/speakers/:id", deps.speakers.DeleteSpeaker)
+		admin.POST("/speakers/:id/feedback-link", deps.feedback.CreateSpeakerLink)
+
+		// Session management
+		admin.POST("/sessions", deps.sessions.CreateSession)
+		admin.PUT("/sessions/:id", deps.sessions.UpdateSession)
+		admin.DELETE("/sessions/:id", deps.sessions.DeleteSession)
+
+		// Session check-in, which feedback requires
+		admin.GET("/sessions/:id/attendance", deps.attendance.GetAttendance)
+		admin.POST("/sessions/:id/attendance/:attendeeId", deps.attendance.CheckIn)
+		admin.DELETE("/sessions/:id/attendance/:attendeeId", deps.attendance.RemoveAttendance)
+
+		// Live Q&A moderation
+		admin.GET("/sessions/:id/questions", deps.qa.GetModerationQueue)
+		admin.PUT("/sessions/:id/questions/:questionId", deps.qa.ModerateQuestion)
+
+		// Session feedback
+		admin.GET("/feedback", deps.feedback.GetFeedback)
+		admin.GET("/feedback/export", deps.feedback.ExportFeedback)
+
+		// Venues, rooms and tracks
+		admin.POST("/venues", deps.venues.CreateVenue)
+		admin.PUT("/venues/:id", deps.venues.UpdateVenue)
+		admin.DELETE("/venues/:id", deps.venues.DeleteVenue)
+		admin.POST("/rooms", deps.venues.CreateRoom)
+		admin.PUT("/rooms/:id", deps.venues.UpdateRoom)
+		admin.DELETE("/rooms/:id", deps.venues.DeleteRoom)
+		admin.POST("/tracks", deps.tracks.CreateTrack)
+		admin.PUT("/tracks/:id", deps.tracks.UpdateTrack)
+		admin.DELETE("/tracks/:id", deps.tracks.DeleteTrack)
+
+		// Designation taxonomy
+		admin.GET("/designations", deps.designations.GetDesignations)
+		admin.POST("/designations", deps.designations.CreateDesignation)
+		admin.PUT("/designations/:id", deps.designations.UpdateDesignation)
+		admin.DELETE("/designations/:id", deps.designations.DeleteDesignation)
+		admin.POST("/designations/backfill", deps.designations.BackfillAttendees)
+
+		// Custom registration questions
+		admin.GET("/questions", deps.questions.GetQuestions)
+		admin.POST("/questions", deps.questions.CreateQuestion)
+		admin.PUT("/questions/:id", deps.questions.UpdateQuestion)
+		admin.DELETE("/questions/:id", deps.questions.DeleteQuestion)
+
+		// Outbound webhooks
+		admin.GET("/webhooks", deps.webhooks.GetWebhooks)
+		admin.POST("/webhooks", deps.webhooks.CreateWebhook)
+		admin.PUT("/webhooks/:id", deps.webhooks.UpdateWebhook)
+		admin.DELETE("/webhooks/:id", deps.webhooks.DeleteWebhook)
+		admin.POST("/webhooks/:id/test", deps.webhooks.TestWebhook)
+		admin.GET("/webhooks/:id/deliveries", deps.webhooks.GetDeliveries)
+		admin.POST("/webhooks/:id/deliveries/:deliveryId/replay", deps.webhooks.ReplayDelivery)
+
+		// Domain events that subscribers failed to handle
+		admin.GET("/dead-letters", deps.outbox.GetDeadLetters)
+		admin.POST("/dead-letters/:id/redrive", deps.outbox.RedriveDeadLetter)
+	}
+
+	return router
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/apidocs"
+	"appdirect-ai-workshop/internal/config"
+	"appdirect-ai-workshop/internal/handlers"
+	"appdirect-ai-workshop/internal/metrics"
+	"appdirect-ai-workshop/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRouter builds the production router around handlers without a
+// datastore; it is only used to inspect and exercise routing.
+func testRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Firestore.ProjectID = "test-project"
+	cfg.Admin.Password = "testpassword"
+	return testRouterWith(t, cfg)
+}
+
+func testRouterWith(t *testing.T, cfg *config.Config) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	adminSessions := middleware.NewAdminSessions(cfg.Admin)
+	return newRouter(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), routeDeps{
+		attendees:      handlers.NewAttendeeHandler(nil, nil, nil, nil, nil),
+		speakers:       handlers.NewSpeakerHandler(nil, nil),
+		sessions:       handlers.NewSessionHandler(nil, nil, time.UTC),
+		questions:      handlers.NewQuestionHandler(nil),
+		designations:   handlers.NewDesignationHandler(nil, nil),
+		venues:         handlers.NewVenueHandler(nil, nil),
+		tracks:         handlers.NewTrackHandler(nil, nil),
+		admin:          handlers.NewAdminHandler(nil, nil, cfg.Admin, adminSessions),
+		selfService:    handlers.NewSelfServiceHandler(nil, nil, nil, nil, nil, nil),
+		feedback:       handlers.NewFeedbackHandler(nil, nil, cfg.Feedback),
+		attendance:     handlers.NewAttendanceHandler(nil, nil),
+		qa:             handlers.NewQAHandler(nil, nil, cfg.QA),
+		privacy:        handlers.NewPrivacyHandler(nil),
+		health:         handlers.NewHealthHandler(time.Second),
+		events:         handlers.NewEventHandler(nil),
+		webhooks:       handlers.NewWebhookHandler(nil, false),
+		outbox:         handlers.NewOutboxHandler(nil),
+		metrics:        metrics.New(),
+		rateLimitStore: middleware.NewMemoryRateLimitStore(),
+		adminSessions:  adminSessions,
+	})
+}
+
+type openAPISpec struct {
+	OpenAPI string                                `json:"openapi"`
+	Paths   map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+func loadSpec(t *testing.T) openAPISpec {
+	t.Helper()
+	raw, err := apidocs.Spec()
+	require.NoError(t, err)
+
+	var spec openAPISpec
+	require.NoError(t, json.Unmarshal(raw, &spec))
+	return spec
+}
+
+var pathParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// openAPIPath converts a gin route such as /speakers/:id to /speakers/{id}.
+func openAPIPath(route string) string {
+	return pathParam.ReplaceAllString(route, "{$1}")
+}
+
+func TestRoutesDocumented(t *testing.T) {
+	spec := loadSpec(t)
+	routes := testRouter(t).Routes()
+	require.NotEmpty(t, routes)
+
+	registered := make(map[string]bool)
+	for _, route := range routes {
+		path := openAPIPath(route.Path)
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+
+		_, ok := spec.Paths[path][method]
+		assert.True(t, ok, "%s %s is registered but missing from internal/apidocs/openapi.yaml", route.Method, path)
+	}
+
+	methods := map[string]bool{"get": true, "put": true, "post": true, "delete": true, "patch": true, "head": true, "options": true}
+	for path, item := range spec.Paths {
+		for method := range item {
+			if !methods[method] {
+				continue // path-level parameters, summary, ...
+			}
+			assert.True(t, registered[method+" "+path], "%s %s is documented but not registered", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestOpenAPIEndpoints(t *testing.T) {
+	router := testRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var spec openAPISpec
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.True(t, strings.HasPrefix(spec.OpenAPI, "3."))
+	assert.Contains(t, spec.Paths, "/api/attendees")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "/api/openapi.json")
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	router := testRouter(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"unknown route", http.MethodGet, "/api/nope", http.StatusNotFound, "not_found"},
+		{"admin without session", http.MethodGet, "/api/admin/stats", http.StatusUnauthorized, "unauthorized"},
+		{"attendee listing is admin only", http.MethodGet, "/api/admin/attendees", http.StatusUnauthorized, "unauthorized"},
+		{"no public full listing", http.MethodGet, "/api/attendees", http.StatusNotFound, "not_found"},
+		{"invalid login body", http.MethodPost, "/api/admin/login", http.StatusBadRequest, "invalid_request"},
+		{"self-service disabled", http.MethodGet, "/api/attendees/me", http.StatusNotFound, "not_found"},
+		{"privacy tooling is admin only", http.MethodPost, "/api/admin/privacy/erasure", http.StatusUnauthorized, "unauthorized"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			var body struct {
+				Error     string `json:"error"`
+				Code      string `json:"code"`
+				RequestID string `json:"requestId"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantCode, body.Code)
+			assert.NotEmpty(t, body.Error)
+			assert.Equal(t, w.Header().Get(middleware.RequestIDHeader), body.RequestID)
+		})
+	}
+}
+
+func TestLoginLimit_IgnoresForwardedFor(t *testing.T) {
+	router := testRouter(t)
+
+	// Ten attempts fill the per-IP bucket; a forged X-Forwarded-For must not
+	// open a fresh one
+	codes := make([]int, 11)
+	for i := range codes {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/login", strings.NewReader(`{}`))
+		req.RemoteAddr = "203.0.113.7:4000"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		codes[i] = w.Code
+	}
+	assert.Equal(t, http.StatusBadRequest, codes[9])
+	assert.Equal(t, http.StatusTooManyRequests, codes[10])
+}
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	cfg := config.Default()
+	cfg.Firestore.ProjectID = "test-project"
+	cfg.Admin.Password = "testpassword"
+	cfg.ClientIP.TrustedProxies = []string{"169.254.0.0/16"}
+	router := testRouterWith(t, cfg)
+
+	// Behind a trusted proxy, each forwarded client gets its own bucket
+	for i := 0; i < 11; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/login", strings.NewReader(`{}`))
+		req.RemoteAddr = "169.254.1.1:4000"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
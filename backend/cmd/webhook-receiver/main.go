@@ -0,0 +1,60 @@
+// Command webhook-receiver is a local endpoint for trying out webhooks. It
+// checks each delivery's signature and prints it:
+//
+//	go run ./cmd/webhook-receiver -secret whsec_...
+//
+// then add a webhook for http://localhost:9000/ and send a test event.
+// Use -fail to answer 500 and watch the retries.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"appdirect-ai-workshop/internal/services"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "address to listen on")
+	secret := flag.String("secret", "", "webhook signing secret; signatures are not checked when empty")
+	fail := flag.Bool("fail", false, "answer every delivery with 500")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		verdict := "unchecked"
+		if *secret != "" {
+			verdict = "valid"
+			if err := services.VerifyWebhookSignature(*secret, r.Header.Get(services.WebhookSignatureHeader), body, time.Now(), 5*time.Minute); err != nil {
+				verdict = "INVALID"
+			}
+		}
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") != nil {
+			pretty.Write(body)
+		}
+		log.Printf("%s %s (signature %s)\n%s", r.Header.Get(services.WebhookEventHeader), r.Header.Get(services.WebhookIDHeader), verdict, pretty.String())
+
+		switch {
+		case verdict == "INVALID":
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+		case *fail:
+			http.Error(w, "failing on purpose", http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	log.Printf("Listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
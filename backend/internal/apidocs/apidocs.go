@@ -0,0 +1,50 @@
+// Package apidocs serves the OpenAPI specification of the HTTP API and an
+// interactive documentation page for it. The specification is maintained by
+// hand in openapi.yaml and served as JSON.
+package apidocs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"appdirect-ai-workshop/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+//go:embed docs.html
+var docsHTML []byte
+
+// Spec returns the OpenAPI specification as JSON.
+var Spec = sync.OnceValues(func() ([]byte, error) {
+	var spec map[string]any
+	if err := yaml.Unmarshal(specYAML, &spec); err != nil {
+		return nil, err
+	}
+	return json.Marshal(spec)
+})
+
+// SpecHandler serves the specification at /api/openapi.json.
+func SpecHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		spec, err := Spec()
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	}
+}
+
+// UIHandler serves a Swagger UI page that renders the specification.
+func UIHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", docsHTML)
+	}
+}
@@ -0,0 +1,263 @@
+// Package apierror defines the error envelope every API response uses:
+//
+//	{"error": "Validation failed", "code": "validation_failed",
+//	 "details": [{"field": "email", "code": "email", "message": "must be a valid email address"}],
+//	 "requestId": "3f1c..."}
+//
+// "error" stays a human-readable string so existing clients keep working;
+// "code" is stable and meant for branching. Internal errors never reach the
+// client: they are attached to the gin context, where the request logger
+// records them, and replaced by a generic message.
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"appdirect-ai-workshop/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Codes returned in the "code" field.
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeValidationFailed   = "validation_failed"
+	CodeVerificationFailed = "verification_failed"
+	CodeUnauthorized       = "unauthorized"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodePreconditionFailed = "precondition_failed"
+	CodeRateLimited        = "rate_limited"
+	CodeUnavailable        = "unavailable"
+	CodeTimeout            = "timeout"
+	CodeCanceled           = "canceled"
+	CodeInternal           = "internal"
+)
+
+// StatusClientClosedRequest is logged when the client went away before the
+// response was ready; nobody receives it.
+const StatusClientClosedRequest = 499
+
+// Retry hints for transient datastore failures.
+const (
+	unavailableRetryAfter = 5 * time.Second
+	timeoutRetryAfter     = 2 * time.Second
+)
+
+// FieldError describes why one request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is an API error with the HTTP status it is served with.
+type Error struct {
+	Status    int          `json:"-"`
+	Message   string       `json:"error"`
+	Code      string       `json:"code"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+
+	// RetryAfter, when set, is sent as the Retry-After header and in
+	// whole seconds as retryAfter.
+	RetryAfter        time.Duration `json:"-"`
+	RetryAfterSeconds int           `json:"retryAfter,omitempty"`
+
+	cause error
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %v", e.Code, e.cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error { return e.cause }
+
+// New returns an error with the given status, code and client-facing message.
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Wrap returns an error like New that also records cause for the logs.
+func Wrap(cause error, status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message, cause: cause}
+}
+
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeInvalidRequest, message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+// Internal hides cause behind a generic message.
+func Internal(cause error) *Error {
+	return Wrap(cause, http.StatusInternalServerError, CodeInternal, "Internal server error")
+}
+
+// WithRetryAfter sets the retry hint and returns e.
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	e.RetryAfter = d
+	return e
+}
+
+// From converts any error to an API error. Errors that already are *Error
+// pass through; Firestore NotFound, AlreadyExists and FailedPrecondition
+// become 404, 409 and 412; an unavailable datastore or an expired deadline
+// become 503 and 504 with a retry hint; everything else is internal.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return timeout(err)
+	case errors.Is(err, context.Canceled):
+		return canceled(err)
+	}
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		return timeout(err)
+	case codes.Canceled:
+		return canceled(err)
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return Wrap(err, http.StatusServiceUnavailable, CodeUnavailable, "Service temporarily unavailable, please retry").WithRetryAfter(unavailableRetryAfter)
+	case codes.NotFound:
+		return Wrap(err, http.StatusNotFound, CodeNotFound, "Resource not found")
+	case codes.AlreadyExists:
+		return Wrap(err, http.StatusConflict, CodeConflict, "Resource already exists")
+	case codes.FailedPrecondition:
+		return Wrap(err, http.StatusPreconditionFailed, CodePreconditionFailed, "Precondition failed")
+	}
+	return Internal(err)
+}
+
+func timeout(err error) *Error {
+	return Wrap(err, http.StatusGatewayTimeout, CodeTimeout, "The request timed out, please retry").WithRetryAfter(timeoutRetryAfter)
+}
+
+func canceled(err error) *Error {
+	return Wrap(err, StatusClientClosedRequest, CodeCanceled, "Request canceled")
+}
+
+// InvalidBody converts an error from gin's ShouldBind* into a 400 with one
+// detail per rejected field. Validator and JSON decoder messages are
+// rewritten so Go type and struct names never reach the client.
+func InvalidBody(err error) *Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		apiErr := Wrap(err, http.StatusBadRequest, CodeValidationFailed, "Validation failed")
+		for _, fe := range validationErrs {
+			apiErr.Details = append(apiErr.Details, FieldError{
+				Field:   fe.Field(),
+				Code:    fe.Tag(),
+				Message: validationMessage(fe),
+			})
+		}
+		return apiErr
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		apiErr := Wrap(err, http.StatusBadRequest, CodeValidationFailed, "Validation failed")
+		apiErr.Details = []FieldError{{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: "must be a " + jsonType(typeErr.Type),
+		}}
+		return apiErr
+	}
+
+	if errors.Is(err, io.EOF) {
+		return Wrap(err, http.StatusBadRequest, CodeInvalidRequest, "Request body is required")
+	}
+	return Wrap(err, http.StatusBadRequest, CodeInvalidRequest, "Request body is not valid JSON")
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "max":
+		return "must be at most " + fe.Param() + " characters"
+	case "min":
+		return "must be at least " + fe.Param() + " characters"
+	case "oneof":
+		return "must be one of " + fe.Param()
+	default:
+		return "is invalid"
+	}
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "number"
+	}
+}
+
+// Abort writes err as the response and stops the handler chain. The
+// underlying cause, if any, is attached to the context for logging.
+func Abort(c *gin.Context, err error) {
+	apiErr := From(err)
+	if apiErr.cause != nil {
+		c.Error(apiErr.cause)
+	}
+
+	body := *apiErr
+	body.RequestID = logging.RequestID(c.Request.Context())
+	if body.RetryAfter > 0 {
+		body.RetryAfterSeconds = int(math.Ceil(body.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(body.RetryAfterSeconds))
+	}
+	c.AbortWithStatusJSON(body.Status, body)
+}
+
+func init() {
+	// Report JSON field names rather than Go struct field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
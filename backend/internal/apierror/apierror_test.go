@@ -0,0 +1,127 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"appdirect-ai-workshop/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"not found", status.Error(codes.NotFound, "projects/p/databases/(default)/documents/x not found"), http.StatusNotFound, CodeNotFound},
+		{"already exists", status.Error(codes.AlreadyExists, "exists"), http.StatusConflict, CodeConflict},
+		{"failed precondition", status.Error(codes.FailedPrecondition, "index missing"), http.StatusPreconditionFailed, CodePreconditionFailed},
+		{"other grpc", status.Error(codes.PermissionDenied, "denied"), http.StatusInternalServerError, CodeInternal},
+		{"plain", errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+		{"api error", BadRequest("nope"), http.StatusBadRequest, CodeInvalidRequest},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), http.StatusServiceUnavailable, CodeUnavailable},
+		{"grpc deadline", status.Error(codes.DeadlineExceeded, "deadline"), http.StatusGatewayTimeout, CodeTimeout},
+		{"context deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, CodeTimeout},
+		{"client gone", context.Canceled, StatusClientClosedRequest, CodeCanceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := From(tt.err)
+			assert.Equal(t, tt.wantStatus, apiErr.Status)
+			assert.Equal(t, tt.wantCode, apiErr.Code)
+		})
+	}
+}
+
+type testRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+	Age   int    `json:"age"`
+}
+
+func bind(t *testing.T, body string) error {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	var req testRequest
+	err := c.ShouldBindJSON(&req)
+	require.Error(t, err)
+	return err
+}
+
+func TestInvalidBody(t *testing.T) {
+	apiErr := InvalidBody(bind(t, `{"email": "not-an-email"}`))
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+	assert.Equal(t, CodeValidationFailed, apiErr.Code)
+	assert.Equal(t, []FieldError{
+		{Field: "name", Code: "required", Message: "is required"},
+		{Field: "email", Code: "email", Message: "must be a valid email address"},
+	}, apiErr.Details)
+
+	apiErr = InvalidBody(bind(t, `{"name": "a", "email": "a@b.co", "age": "old"}`))
+	assert.Equal(t, CodeValidationFailed, apiErr.Code)
+	assert.Equal(t, []FieldError{{Field: "age", Code: "type", Message: "must be a number"}}, apiErr.Details)
+
+	apiErr = InvalidBody(bind(t, `{"name": `))
+	assert.Equal(t, CodeInvalidRequest, apiErr.Code)
+	assert.Empty(t, apiErr.Details)
+
+	apiErr = InvalidBody(bind(t, ``))
+	assert.Equal(t, "Request body is required", apiErr.Message)
+}
+
+func TestAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), "req-1"))
+
+	cause := errors.New("rpc error: code = Unavailable desc = connection refused to 10.0.0.1")
+	Abort(c, cause)
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "10.0.0.1", "internal details must not leak")
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{
+		"error":     "Internal server error",
+		"code":      CodeInternal,
+		"requestId": "req-1",
+	}, body)
+
+	require.Len(t, c.Errors, 1)
+	assert.ErrorIs(t, c.Errors[0].Err, cause, "the cause is kept for the request log")
+}
+
+func TestAbort_RetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	Abort(c, status.Error(codes.Unavailable, "backend down"))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"retryAfter":5`)
+	assert.NotContains(t, w.Body.String(), "backend down")
+}
@@ -0,0 +1,509 @@
+// Package config loads and validates the server configuration once at
+// startup. Values come from, in increasing order of precedence: built-in
+// defaults, an optional YAML file named by CONFIG_FILE, and environment
+// variables (including those from a .env file).
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // the runtime image has no zoneinfo
+
+	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Port            string        `yaml:"port"`
+	CORSOrigins     []string      `yaml:"corsOrigins"`
+	LogLevel        string        `yaml:"logLevel"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+
+	Event     EventConfig     `yaml:"event"`
+	Firestore FirestoreConfig `yaml:"firestore"`
+	Admin     AdminConfig     `yaml:"admin"`
+	RateLimit RateLimitConfig `yaml:"rateLimit"`
+	ClientIP  ClientIPConfig  `yaml:"clientIp"`
+	Challenge ChallengeConfig `yaml:"challenge"`
+	Captcha   CaptchaConfig   `yaml:"captcha"`
+
+	SelfService SelfServiceConfig `yaml:"selfService"`
+	Mail        MailConfig        `yaml:"mail"`
+	Stats       StatsConfig       `yaml:"stats"`
+	Live        LiveConfig        `yaml:"live"`
+	Feedback    FeedbackConfig    `yaml:"feedback"`
+	QA          QAConfig          `yaml:"qa"`
+	Webhooks    WebhooksConfig    `yaml:"webhooks"`
+
+	MetricsToken string `yaml:"metricsToken"`
+	// MetricsPublic serves /metrics without a token. Without either,
+	// /metrics is not served.
+	MetricsPublic  bool   `yaml:"metricsPublic"`
+	TracesExporter string `yaml:"tracesExporter"`
+}
+
+type EventConfig struct {
+	// TimeZone is the IANA name of the zone the event runs in, used for
+	// daily and hourly stats.
+	TimeZone string `yaml:"timeZone"`
+}
+
+// Location returns the event's time zone, or UTC if it cannot be loaded.
+func (e EventConfig) Location() *time.Location {
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+type FirestoreConfig struct {
+	ProjectID       string `yaml:"projectId"`
+	SubdocID        string `yaml:"subdocId"`
+	CredentialsFile string `yaml:"credentialsFile"`
+
+	// ReadTimeout bounds a single read or query, including iterating over
+	// all its results; WriteTimeout bounds a write or transaction.
+	ReadTimeout  time.Duration `yaml:"readTimeout"`
+	WriteTimeout time.Duration `yaml:"writeTimeout"`
+}
+
+type AdminConfig struct {
+	// Password is a plain-text fallback for development; prefer PasswordHash.
+	Password     string `yaml:"password"`
+	PasswordHash string `yaml:"passwordHash"`
+	// SessionSecret signs admin sessions. Without it, the signing key is
+	// derived from the password settings.
+	SessionSecret string        `yaml:"sessionSecret"`
+	SessionTTL    time.Duration `yaml:"sessionTtl"`
+}
+
+type RateLimitConfig struct {
+	Store string `yaml:"store"`
+}
+
+// ClientIPConfig says which proxies may report the client's address. Per-IP
+// rate limits and the login lockout key on that address, so headers from
+// anyone else are ignored.
+type ClientIPConfig struct {
+	// TrustedProxies are the addresses or CIDR ranges of proxies whose
+	// X-Forwarded-For is believed. Empty trusts none and uses the address
+	// of the connection.
+	TrustedProxies []string `yaml:"trustedProxies"`
+	// TrustedPlatform reads the address from the header a hosting platform
+	// sets instead: appengine or cloudflare.
+	TrustedPlatform string `yaml:"trustedPlatform"`
+}
+
+type ChallengeConfig struct {
+	Secret      string        `yaml:"secret"`
+	Difficulty  int           `yaml:"difficulty"`
+	MinFillTime time.Duration `yaml:"minFillTime"`
+}
+
+type CaptchaConfig struct {
+	Secret    string `yaml:"secret"`
+	SiteKey   string `yaml:"siteKey"`
+	VerifyURL string `yaml:"verifyUrl"`
+}
+
+// SelfServiceConfig enables attendee self-service through emailed magic
+// links. It is off while Secret is empty.
+type SelfServiceConfig struct {
+	Secret string        `yaml:"secret"`
+	TTL    time.Duration `yaml:"ttl"`
+	// BaseURL is the public address of the frontend the links point at.
+	BaseURL string `yaml:"baseUrl"`
+}
+
+// MailConfig configures outgoing email. Without an SMTP host, email is
+// written to the log instead.
+type MailConfig struct {
+	SMTPHost     string `yaml:"smtpHost"`
+	SMTPPort     string `yaml:"smtpPort"`
+	SMTPUsername string `yaml:"smtpUsername"`
+	SMTPPassword string `yaml:"smtpPassword"`
+	From         string `yaml:"from"`
+}
+
+type StatsConfig struct {
+	// CacheTTL is how long admin stats are served from the same read of the
+	// attendee records.
+	CacheTTL time.Duration `yaml:"cacheTtl"`
+}
+
+type LiveConfig struct {
+	// MaxClients caps the live event streams one instance serves at once.
+	MaxClients int `yaml:"maxClients"`
+}
+
+type FeedbackConfig struct {
+	// EditWindow is how long after submitting session feedback an attendee
+	// may still change it. Zero makes feedback final once submitted.
+	EditWindow time.Duration `yaml:"editWindow"`
+	// SpeakerLinkTTL is how long the links speakers use to read their
+	// feedback stay valid.
+	SpeakerLinkTTL time.Duration `yaml:"speakerLinkTtl"`
+}
+
+type QAConfig struct {
+	// Premoderate holds new questions until a moderator approves them.
+	Premoderate bool `yaml:"premoderate"`
+	// MaxLength is the most characters a question may have.
+	MaxLength int `yaml:"maxLength"`
+	// QuestionsPerMinute and VotesPerMinute limit each attendee, with
+	// bursts of the same size.
+	QuestionsPerMinute int `yaml:"questionsPerMinute"`
+	VotesPerMinute     int `yaml:"votesPerMinute"`
+}
+
+type WebhooksConfig struct {
+	// AllowInsecure lets webhooks use plain http and reach loopback and
+	// private addresses, for trying them against a local receiver. Never
+	// set it in production: admins could then reach internal services and
+	// the metadata server.
+	AllowInsecure bool `yaml:"allowInsecure"`
+}
+
+// Default returns the configuration used when nothing is set.
+func Default() *Config {
+	return &Config{
+		Port:            "8080",
+		CORSOrigins:     []string{"http://localhost:5173"},
+		LogLevel:        "info",
+		ShutdownTimeout: 9 * time.Second,
+		Event:           EventConfig{TimeZone: "UTC"},
+		Firestore:       FirestoreConfig{SubdocID: "workshop", ReadTimeout: 5 * time.Second, WriteTimeout: 10 * time.Second},
+		Admin:           AdminConfig{SessionTTL: 12 * time.Hour},
+		RateLimit:       RateLimitConfig{Store: "memory"},
+		Challenge:       ChallengeConfig{Difficulty: 18, MinFillTime: 3 * time.Second},
+		Captcha:         CaptchaConfig{VerifyURL: "https://challenges.cloudflare.com/turnstile/v0/siteverify"},
+		SelfService:     SelfServiceConfig{TTL: 24 * time.Hour},
+		Mail:            MailConfig{SMTPPort: "587"},
+		Stats:           StatsConfig{CacheTTL: time.Minute},
+		Live:            LiveConfig{MaxClients: 1000},
+		Feedback:        FeedbackConfig{EditWindow: 24 * time.Hour, SpeakerLinkTTL: 30 * 24 * time.Hour},
+		QA:              QAConfig{MaxLength: 300, QuestionsPerMinute: 2, VotesPerMinute: 30},
+		TracesExporter:  "none",
+	}
+}
+
+// Load reads .env (from the working directory or its parent), the optional
+// YAML file and the environment, then validates the result.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		godotenv.Load("../.env")
+	}
+
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv overrides fields with any environment variables that are set.
+func (c *Config) loadEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	str := func(name string, dst *string) {
+		if v, ok := lookup(name); ok && v != "" {
+			*dst = v
+		}
+	}
+	integer := func(name string, dst *int) {
+		if v, ok := lookup(name); ok && v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", name, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	boolean := func(name string, dst *bool) {
+		if v, ok := lookup(name); ok && v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not true or false", name, v))
+				return
+			}
+			*dst = b
+		}
+	}
+	duration := func(name string, dst *time.Duration) {
+		if v, ok := lookup(name); ok && v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration (e.g. 10s)", name, v))
+				return
+			}
+			*dst = d
+		}
+	}
+
+	str("PORT", &c.Port)
+	if v, ok := lookup("CORS_ORIGIN"); ok && v != "" {
+		c.CORSOrigins = splitList(v)
+	}
+	str("LOG_LEVEL", &c.LogLevel)
+	duration("SHUTDOWN_TIMEOUT", &c.ShutdownTimeout)
+
+	str("EVENT_TIMEZONE", &c.Event.TimeZone)
+
+	str("FIRESTORE_PROJECT_ID", &c.Firestore.ProjectID)
+	str("FIRESTORE_SUBDOC_ID", &c.Firestore.SubdocID)
+	str("GOOGLE_APPLICATION_CREDENTIALS", &c.Firestore.CredentialsFile)
+	duration("FIRESTORE_READ_TIMEOUT", &c.Firestore.ReadTimeout)
+	duration("FIRESTORE_WRITE_TIMEOUT", &c.Firestore.WriteTimeout)
+
+	str("ADMIN_PASSWORD", &c.Admin.Password)
+	str("ADMIN_PASSWORD_HASH", &c.Admin.PasswordHash)
+	str("ADMIN_SESSION_SECRET", &c.Admin.SessionSecret)
+	duration("ADMIN_SESSION_TTL", &c.Admin.SessionTTL)
+
+	str("RATE_LIMIT_STORE", &c.RateLimit.Store)
+	if v, ok := lookup("TRUSTED_PROXIES"); ok && v != "" {
+		c.ClientIP.TrustedProxies = splitList(v)
+	}
+	str("TRUSTED_PLATFORM", &c.ClientIP.TrustedPlatform)
+
+	str("CHALLENGE_SECRET", &c.Challenge.Secret)
+	integer("CHALLENGE_DIFFICULTY", &c.Challenge.Difficulty)
+	duration("CHALLENGE_MIN_FILL_TIME", &c.Challenge.MinFillTime)
+
+	str("CAPTCHA_SECRET", &c.Captcha.Secret)
+	str("CAPTCHA_SITE_KEY", &c.Captcha.SiteKey)
+	str("CAPTCHA_VERIFY_URL", &c.Captcha.VerifyURL)
+
+	str("MAGIC_LINK_SECRET", &c.SelfService.Secret)
+	duration("MAGIC_LINK_TTL", &c.SelfService.TTL)
+	str("PUBLIC_BASE_URL", &c.SelfService.BaseURL)
+
+	str("SMTP_HOST", &c.Mail.SMTPHost)
+	str("SMTP_PORT", &c.Mail.SMTPPort)
+	str("SMTP_USERNAME", &c.Mail.SMTPUsername)
+	str("SMTP_PASSWORD", &c.Mail.SMTPPassword)
+	str("MAIL_FROM", &c.Mail.From)
+
+	duration("STATS_CACHE_TTL", &c.Stats.CacheTTL)
+
+	integer("LIVE_MAX_CLIENTS", &c.Live.MaxClients)
+
+	duration("FEEDBACK_EDIT_WINDOW", &c.Feedback.EditWindow)
+	duration("SPEAKER_LINK_TTL", &c.Feedback.SpeakerLinkTTL)
+
+	boolean("QA_PREMODERATE", &c.QA.Premoderate)
+	integer("QA_MAX_LENGTH", &c.QA.MaxLength)
+	integer("QA_QUESTIONS_PER_MINUTE", &c.QA.QuestionsPerMinute)
+	integer("QA_VOTES_PER_MINUTE", &c.QA.VotesPerMinute)
+
+	boolean("WEBHOOK_ALLOW_INSECURE", &c.Webhooks.AllowInsecure)
+
+	str("METRICS_TOKEN", &c.MetricsToken)
+	boolean("METRICS_PUBLIC", &c.MetricsPublic)
+	str("OTEL_TRACES_EXPORTER", &c.TracesExporter)
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid or missing setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		add("PORT: %q is not a valid port", c.Port)
+	}
+	if len(c.CORSOrigins) == 0 {
+		add("CORS_ORIGIN: at least one origin is required")
+	}
+	for _, origin := range c.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			add("CORS_ORIGIN: %q must be * or a scheme and host such as https://example.com", origin)
+		}
+	}
+	switch strings.ToLower(c.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		add("LOG_LEVEL: %q must be debug, info, warn or error", c.LogLevel)
+	}
+	if c.ShutdownTimeout <= 0 {
+		add("SHUTDOWN_TIMEOUT: must be positive")
+	}
+
+	if _, err := time.LoadLocation(c.Event.TimeZone); err != nil || c.Event.TimeZone == "" {
+		add("EVENT_TIMEZONE: %q is not an IANA time zone such as Asia/Kolkata", c.Event.TimeZone)
+	}
+
+	if c.Firestore.ProjectID == "" {
+		add("FIRESTORE_PROJECT_ID: required")
+	}
+	if c.Firestore.SubdocID == "" {
+		add("FIRESTORE_SUBDOC_ID: required")
+	}
+	if c.Firestore.ReadTimeout <= 0 {
+		add("FIRESTORE_READ_TIMEOUT: must be positive")
+	}
+	if c.Firestore.WriteTimeout <= 0 {
+		add("FIRESTORE_WRITE_TIMEOUT: must be positive")
+	}
+
+	if err := c.Admin.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch c.RateLimit.Store {
+	case "memory", "firestore":
+	default:
+		add("RATE_LIMIT_STORE: %q must be memory or firestore", c.RateLimit.Store)
+	}
+
+	for _, proxy := range c.ClientIP.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			add("TRUSTED_PROXIES: %q is not an IP address or CIDR range", proxy)
+		}
+	}
+	switch c.ClientIP.TrustedPlatform {
+	case "", "appengine", "cloudflare":
+	default:
+		add("TRUSTED_PLATFORM: %q must be appengine or cloudflare", c.ClientIP.TrustedPlatform)
+	}
+
+	if c.Challenge.Difficulty < 0 || c.Challenge.Difficulty > 32 {
+		add("CHALLENGE_DIFFICULTY: %d must be between 0 and 32", c.Challenge.Difficulty)
+	}
+	if c.Challenge.Secret != "" && len(c.Challenge.Secret) < 16 {
+		add("CHALLENGE_SECRET: must be at least 16 characters")
+	}
+	if c.Captcha.Secret != "" {
+		if c.Captcha.SiteKey == "" {
+			add("CAPTCHA_SITE_KEY: required when CAPTCHA_SECRET is set")
+		}
+		if u, err := url.Parse(c.Captcha.VerifyURL); err != nil || u.Scheme != "https" {
+			add("CAPTCHA_VERIFY_URL: %q must be an https URL", c.Captcha.VerifyURL)
+		}
+	}
+
+	if c.SelfService.Secret != "" {
+		if len(c.SelfService.Secret) < 16 {
+			add("MAGIC_LINK_SECRET: must be at least 16 characters")
+		}
+		if c.SelfService.TTL <= 0 {
+			add("MAGIC_LINK_TTL: must be positive")
+		}
+		if u, err := url.Parse(c.SelfService.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("PUBLIC_BASE_URL: %q must be an absolute URL when MAGIC_LINK_SECRET is set", c.SelfService.BaseURL)
+		}
+	}
+	if c.Mail.SMTPHost != "" {
+		if c.Mail.From == "" {
+			add("MAIL_FROM: required when SMTP_HOST is set")
+		}
+		if port, err := strconv.Atoi(c.Mail.SMTPPort); err != nil || port < 1 || port > 65535 {
+			add("SMTP_PORT: %q is not a valid port", c.Mail.SMTPPort)
+		}
+	}
+
+	if c.Stats.CacheTTL < 0 {
+		add("STATS_CACHE_TTL: must not be negative")
+	}
+
+	if c.Live.MaxClients < 1 {
+		add("LIVE_MAX_CLIENTS: must be at least 1")
+	}
+
+	if c.Feedback.EditWindow < 0 {
+		add("FEEDBACK_EDIT_WINDOW: must not be negative")
+	}
+	if c.Feedback.SpeakerLinkTTL <= 0 {
+		add("SPEAKER_LINK_TTL: must be positive")
+	}
+
+	if c.QA.MaxLength < 1 || c.QA.MaxLength > 2000 {
+		add("QA_MAX_LENGTH: must be between 1 and 2000")
+	}
+	if c.QA.QuestionsPerMinute < 1 {
+		add("QA_QUESTIONS_PER_MINUTE: must be at least 1")
+	}
+	if c.QA.VotesPerMinute < 1 {
+		add("QA_VOTES_PER_MINUTE: must be at least 1")
+	}
+
+	if c.MetricsPublic && c.MetricsToken != "" {
+		add("METRICS_PUBLIC: cannot be combined with METRICS_TOKEN")
+	}
+
+	switch c.TracesExporter {
+	case "", "none", "stdout", "otlp":
+	default:
+		add("OTEL_TRACES_EXPORTER: %q must be none, stdout or otlp", c.TracesExporter)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Validate checks that an admin password is configured and usable, and that
+// sessions can be signed.
+func (a AdminConfig) Validate() error {
+	if a.SessionSecret != "" && len(a.SessionSecret) < 16 {
+		return errors.New("ADMIN_SESSION_SECRET: must be at least 16 characters")
+	}
+	if a.SessionTTL <= 0 {
+		return errors.New("ADMIN_SESSION_TTL: must be positive")
+	}
+	if a.PasswordHash != "" {
+		if _, err := bcrypt.Cost([]byte(a.PasswordHash)); err != nil {
+			return fmt.Errorf("ADMIN_PASSWORD_HASH: not a bcrypt hash: %v", err)
+		}
+		return nil
+	}
+	if a.Password == "" {
+		return errors.New("ADMIN_PASSWORD or ADMIN_PASSWORD_HASH: required")
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
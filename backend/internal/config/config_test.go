@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func validConfig() *Config {
+	cfg := Default()
+	cfg.Firestore.ProjectID = "test-project"
+	cfg.Admin.Password = "secret"
+	return cfg
+}
+
+func TestLoadEnv(t *testing.T) {
+	cfg := Default()
+	err := cfg.loadEnv(env(map[string]string{
+		"PORT":                   "9090",
+		"CORS_ORIGIN":            "https://a.example.com, https://b.example.com,",
+		"FIRESTORE_PROJECT_ID":   "proj",
+		"ADMIN_PASSWORD":         "pw",
+		"CHALLENGE_DIFFICULTY":   "20",
+		"SHUTDOWN_TIMEOUT":       "5s",
+		"FIRESTORE_READ_TIMEOUT": "2s",
+		"LOG_LEVEL":              "",
+		"EVENT_TIMEZONE":         "Asia/Kolkata",
+		"QA_PREMODERATE":         "true",
+		"QA_MAX_LENGTH":          "200",
+		"TRUSTED_PROXIES":        "169.254.0.0/16, 10.0.0.1",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "9090", cfg.Port)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORSOrigins)
+	assert.Equal(t, "proj", cfg.Firestore.ProjectID)
+	assert.Equal(t, "workshop", cfg.Firestore.SubdocID)
+	assert.Equal(t, 20, cfg.Challenge.Difficulty)
+	assert.Equal(t, 5*time.Second, cfg.ShutdownTimeout)
+	assert.Equal(t, 2*time.Second, cfg.Firestore.ReadTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Firestore.WriteTimeout)
+	assert.Equal(t, "info", cfg.LogLevel, "empty variables keep the default")
+	assert.Equal(t, "Asia/Kolkata", cfg.Event.Location().String())
+	assert.True(t, cfg.QA.Premoderate)
+	assert.Equal(t, 200, cfg.QA.MaxLength)
+	assert.Equal(t, []string{"169.254.0.0/16", "10.0.0.1"}, cfg.ClientIP.TrustedProxies)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoadEnv_Malformed(t *testing.T) {
+	cfg := Default()
+	err := cfg.loadEnv(env(map[string]string{
+		"CHALLENGE_DIFFICULTY": "hard",
+		"SHUTDOWN_TIMEOUT":     "9",
+		"QA_PREMODERATE":       "sometimes",
+	}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CHALLENGE_DIFFICULTY")
+	assert.Contains(t, err.Error(), "SHUTDOWN_TIMEOUT")
+	assert.Contains(t, err.Error(), "QA_PREMODERATE")
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+port: "3000"
+corsOrigins:
+  - https://workshop.example.com
+firestore:
+  projectId: from-file
+rateLimit:
+  store: firestore
+shutdownTimeout: 4s
+`), 0o600))
+
+	cfg := Default()
+	require.NoError(t, cfg.loadFile(path))
+	require.NoError(t, cfg.loadEnv(env(map[string]string{"PORT": "4000"})))
+
+	assert.Equal(t, "4000", cfg.Port, "environment overrides the file")
+	assert.Equal(t, []string{"https://workshop.example.com"}, cfg.CORSOrigins)
+	assert.Equal(t, "from-file", cfg.Firestore.ProjectID)
+	assert.Equal(t, "workshop", cfg.Firestore.SubdocID, "unset keys keep the default")
+	assert.Equal(t, "firestore", cfg.RateLimit.Store)
+	assert.Equal(t, 4*time.Second, cfg.ShutdownTimeout)
+
+	assert.Error(t, Default().loadFile(filepath.Join(t.TempDir(), "missing.yaml")))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"missing project", func(c *Config) { c.Firestore.ProjectID = "" }, "FIRESTORE_PROJECT_ID"},
+		{"missing admin password", func(c *Config) { c.Admin.Password = "" }, "ADMIN_PASSWORD"},
+		{"bad password hash", func(c *Config) { c.Admin.PasswordHash = "plain" }, "ADMIN_PASSWORD_HASH"},
+		{"short session secret", func(c *Config) { c.Admin.SessionSecret = "short" }, "ADMIN_SESSION_SECRET"},
+		{"no session ttl", func(c *Config) { c.Admin.SessionTTL = 0 }, "ADMIN_SESSION_TTL"},
+		{"bad port", func(c *Config) { c.Port = "http" }, "PORT"},
+		{"no origins", func(c *Config) { c.CORSOrigins = nil }, "CORS_ORIGIN"},
+		{"origin with path", func(c *Config) { c.CORSOrigins = []string{"https://example.com/app"} }, "CORS_ORIGIN"},
+		{"origin without scheme", func(c *Config) { c.CORSOrigins = []string{"example.com"} }, "CORS_ORIGIN"},
+		{"bad log level", func(c *Config) { c.LogLevel = "verbose" }, "LOG_LEVEL"},
+		{"bad rate limit store", func(c *Config) { c.RateLimit.Store = "redis" }, "RATE_LIMIT_STORE"},
+		{"bad trusted proxy", func(c *Config) { c.ClientIP.TrustedProxies = []string{"proxy.internal"} }, "TRUSTED_PROXIES"},
+		{"unknown platform", func(c *Config) { c.ClientIP.TrustedPlatform = "heroku" }, "TRUSTED_PLATFORM"},
+		{"short challenge secret", func(c *Config) { c.Challenge.Secret = "short" }, "CHALLENGE_SECRET"},
+		{"difficulty too high", func(c *Config) { c.Challenge.Difficulty = 64 }, "CHALLENGE_DIFFICULTY"},
+		{"captcha without site key", func(c *Config) { c.Captcha.Secret = "s" }, "CAPTCHA_SITE_KEY"},
+		{"public metrics with token", func(c *Config) { c.MetricsPublic, c.MetricsToken = true, "secret" }, "METRICS_PUBLIC"},
+		{"bad exporter", func(c *Config) { c.TracesExporter = "zipkin" }, "OTEL_TRACES_EXPORTER"},
+		{"zero shutdown timeout", func(c *Config) { c.ShutdownTimeout = 0 }, "SHUTDOWN_TIMEOUT"},
+		{"short magic link secret", func(c *Config) {
+			c.SelfService = SelfServiceConfig{Secret: "short", TTL: time.Hour, BaseURL: "https://example.com"}
+		}, "MAGIC_LINK_SECRET"},
+		{"magic links without base url", func(c *Config) {
+			c.SelfService = SelfServiceConfig{Secret: "0123456789abcdef", TTL: time.Hour}
+		}, "PUBLIC_BASE_URL"},
+		{"smtp without sender", func(c *Config) { c.Mail.SMTPHost = "smtp.example.com" }, "MAIL_FROM"},
+		{"zero read timeout", func(c *Config) { c.Firestore.ReadTimeout = 0 }, "FIRESTORE_READ_TIMEOUT"},
+		{"unknown time zone", func(c *Config) { c.Event.TimeZone = "Mars/Olympus" }, "EVENT_TIMEZONE"},
+		{"negative stats cache ttl", func(c *Config) { c.Stats.CacheTTL = -time.Second }, "STATS_CACHE_TTL"},
+		{"no live clients", func(c *Config) { c.Live.MaxClients = 0 }, "LIVE_MAX_CLIENTS"},
+		{"negative feedback edit window", func(c *Config) { c.Feedback.EditWindow = -time.Hour }, "FEEDBACK_EDIT_WINDOW"},
+		{"no speaker link ttl", func(c *Config) { c.Feedback.SpeakerLinkTTL = 0 }, "SPEAKER_LINK_TTL"},
+		{"question length too long", func(c *Config) { c.QA.MaxLength = 5000 }, "QA_MAX_LENGTH"},
+		{"no questions per minute", func(c *Config) { c.QA.QuestionsPerMinute = 0 }, "QA_QUESTIONS_PER_MINUTE"},
+		{"no votes per minute", func(c *Config) { c.QA.VotesPerMinute = 0 }, "QA_VOTES_PER_MINUTE"},
+		{"negative write timeout", func(c *Config) { c.Firestore.WriteTimeout = -time.Second }, "FIRESTORE_WRITE_TIMEOUT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	t.Run("reports every problem", func(t *testing.T) {
+		cfg := Default()
+		cfg.Port = ""
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "PORT")
+		assert.Contains(t, err.Error(), "FIRESTORE_PROJECT_ID")
+		assert.Contains(t, err.Error(), "ADMIN_PASSWORD")
+	})
+}
@@ -1,23 +1,27 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
+	"time"
 
-	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/config"
 	"appdirect-ai-workshop/internal/middleware"
+	"appdirect-ai-workshop/internal/models"
 	"appdirect-ai-workshop/internal/services"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/api/iterator"
 )
 
 type AdminHandler struct {
 	firestore *services.FirestoreService
+	stats     *services.StatsService
+	admin     config.AdminConfig
+	sessions  *middleware.AdminSessions
 }
 
-func NewAdminHandler(firestore *services.FirestoreService) *AdminHandler {
-	return &AdminHandler{firestore: firestore}
+func NewAdminHandler(firestore *services.FirestoreService, stats *services.StatsService, admin config.AdminConfig, sessions *middleware.AdminSessions) *AdminHandler {
+	return &AdminHandler{firestore: firestore, stats: stats, admin: admin, sessions: sessions}
 }
 
 type LoginRequest struct {
@@ -27,57 +31,105 @@ type LoginRequest struct {
 func (h *AdminHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.InvalidBody(err))
 		return
 	}
 
-	if !middleware.VerifyPassword(req.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
+	if !middleware.VerifyPassword(h.admin, req.Password) {
+		apierror.Abort(c, apierror.Unauthorized("Invalid password"))
 		return
 	}
 
-	// Set session cookie
-	c.SetCookie("admin_session", "authenticated", 3600*24, "/", "", false, true)
+	token, err := h.sessions.Issue()
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetCookie(middleware.AdminSessionCookie, token, int(h.sessions.TTL().Seconds()), "/", "", secure, true)
 	c.JSON(http.StatusOK, gin.H{"message": "Login successful"})
 }
 
+// GetStats reports designation, answer, funnel and timeline stats for the
+// registrations matching the query: from and to are inclusive dates in the
+// event time zone, designation may be repeated, and interval is hour or
+// day. Stats are computed from the StatsService snapshot.
 func (h *AdminHandler) GetStats(c *gin.Context) {
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("attendees")
-
-	// Count by designation
-	designationMap := make(map[string]int)
-	iter := collection.Documents(ctx)
-	defer iter.Stop()
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
+	ctx := c.Request.Context()
+	loc := h.stats.Location()
+
+	filter, interval, err := statsQuery(c, loc)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	attendees, generatedAt, err := h.stats.Attendees(ctx)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	timeline, err := models.NewTimeline(attendees, filter, interval, loc)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+
+	matched := filter.Apply(attendees)
+	var answers []map[string]any
+	for _, attendee := range matched {
+		if !attendee.Cancelled() {
+			answers = append(answers, attendee.Answers)
 		}
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+
+	questions, err := loadQuestions(ctx, h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats":       models.NewDesignationStats(matched),
+		"questions":   models.NewQuestionStats(questions, answers),
+		"funnel":      models.NewFunnel(matched),
+		"timeline":    timeline,
+		"generatedAt": generatedAt.UTC(),
+	})
+}
+
+// statsQuery reads the stats query parameters. The to date is inclusive.
+func statsQuery(c *gin.Context, loc *time.Location) (models.StatsFilter, string, error) {
+	filter := models.StatsFilter{Designations: c.QueryArray("designation")}
+	interval := c.DefaultQuery("interval", models.IntervalDay)
+
+	var details []apierror.FieldError
+	date := func(param string, dst *time.Time, days int) {
+		v := c.Query(param)
+		if v == "" {
 			return
 		}
-
-		var attendee models.Attendee
-		if err := doc.DataTo(&attendee); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			details = append(details, apierror.FieldError{Field: param, Code: "date", Message: "must be a date such as 2026-03-14"})
 			return
 		}
-
-		designationMap[attendee.Designation]++
+		*dst = d.AddDate(0, 0, days)
 	}
+	date("from", &filter.From, 0)
+	date("to", &filter.To, 1)
 
-	// Convert to slice
-	var stats []models.DesignationStats
-	for designation, count := range designationMap {
-		stats = append(stats, models.DesignationStats{
-			Designation: designation,
-			Count:       count,
-		})
+	if interval != models.IntervalHour && interval != models.IntervalDay {
+		details = append(details, apierror.FieldError{Field: "interval", Code: "oneof", Message: "must be one of: hour day"})
 	}
-
-	c.JSON(http.StatusOK, gin.H{"stats": stats})
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		details = append(details, apierror.FieldError{Field: "to", Code: "gtefield", Message: "must not be before from"})
+	}
+	if len(details) > 0 {
+		apiErr := apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Validation failed")
+		apiErr.Details = details
+		return filter, interval, apiErr
+	}
+	return filter, interval, nil
 }
-
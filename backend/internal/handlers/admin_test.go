@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
+	"time"
 
+	"appdirect-ai-workshop/internal/config"
+	"appdirect-ai-workshop/internal/middleware"
 	"appdirect-ai-workshop/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -17,15 +19,10 @@ import (
 func TestAdminHandler_Login(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	// Set a test password
-	os.Setenv("ADMIN_PASSWORD", "testpassword")
-	defer os.Unsetenv("ADMIN_PASSWORD")
-
-	mockService, err := services.NewFirestoreService()
-	if err != nil {
-		t.Skipf("Skipping test: Firestore not available: %v", err)
-	}
-	handler := NewAdminHandler(mockService)
+	// Login never touches Firestore
+	admin := config.AdminConfig{Password: "testpassword", SessionTTL: time.Hour}
+	sessions := middleware.NewAdminSessions(admin)
+	handler := NewAdminHandler(nil, nil, admin, sessions)
 
 	tests := []struct {
 		name           string
@@ -66,7 +63,60 @@ func TestAdminHandler_Login(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			// A successful login sets a session the admin routes accept
+			var session *http.Cookie
+			for _, cookie := range w.Result().Cookies() {
+				if cookie.Name == middleware.AdminSessionCookie {
+					session = cookie
+				}
+			}
+			if tt.expectedStatus != http.StatusOK {
+				assert.Nil(t, session)
+				return
+			}
+			if assert.NotNil(t, session) {
+				assert.True(t, session.HttpOnly)
+				assert.NoError(t, sessions.Verify(session.Value))
+			}
 		})
 	}
 }
 
+func TestAdminHandler_GetStats_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Queries are rejected before the attendee records are read
+	handler := NewAdminHandler(nil, services.NewStatsService(nil, time.Minute, time.UTC), config.AdminConfig{}, nil)
+	router := gin.New()
+	router.GET("/api/admin/stats", handler.GetStats)
+
+	tests := []struct {
+		query string
+		field string
+	}{
+		{"from=01-03-2026", "from"},
+		{"to=tomorrow", "to"},
+		{"from=2026-03-05&to=2026-03-01", "to"},
+		{"interval=week", "interval"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/admin/stats?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var body struct {
+				Details []struct {
+					Field string `json:"field"`
+				} `json:"details"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			if assert.Len(t, body.Details, 1) {
+				assert.Equal(t, tt.field, body.Details[0].Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const attendanceCollection = "sessionAttendance"
+
+// AttendanceHandler records which attendees were at which sessions. Admins
+// check attendees in; attendees can see the sessions they were checked in
+// to.
+type AttendanceHandler struct {
+	firestore *services.FirestoreService
+	links     *services.MagicLinks
+	now       func() time.Time
+}
+
+// NewAttendanceHandler creates an AttendanceHandler. links may be nil, which
+// disables the attendee's own view.
+func NewAttendanceHandler(firestore *services.FirestoreService, links *services.MagicLinks) *AttendanceHandler {
+	return &AttendanceHandler{firestore: firestore, links: links, now: time.Now}
+}
+
+// GetAttendance lists the attendees checked in to a session, earliest
+// first. Admin only.
+func (h *AttendanceHandler) GetAttendance(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+	if _, err := loadSession(ctx, h.firestore, sessionID); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	attendance := []models.SessionAttendance{}
+	query := h.firestore.GetCollection(attendanceCollection).Where("sessionId", "==", sessionID)
+	err := h.firestore.Documents(ctx, attendanceCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		var a models.SessionAttendance
+		if err := doc.DataTo(&a); err != nil {
+			return err
+		}
+		a.ID = doc.Ref.ID
+		attendance = append(attendance, a)
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	ids := make([]string, len(attendance))
+	for i, a := range attendance {
+		ids[i] = a.AttendeeID
+	}
+	docs, err := h.firestore.GetAll(ctx, "attendees", ids)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	names := make(map[string]string, len(docs))
+	for _, doc := range docs {
+		names[doc.Ref.ID], _ = doc.Data()["name"].(string)
+	}
+	for i := range attendance {
+		attendance[i].Name = names[attendance[i].AttendeeID]
+	}
+
+	sort.Slice(attendance, func(i, j int) bool { return attendance[i].CheckedInAt.Before(attendance[j].CheckedInAt) })
+	c.JSON(http.StatusOK, attendance)
+}
+
+// CheckIn records that the attendee is at the session. Checking in twice
+// keeps the first time. Admin only.
+func (h *AttendanceHandler) CheckIn(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+	attendeeID := c.Param("attendeeId")
+
+	if _, err := loadSession(ctx, h.firestore, sessionID); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	attendee, err := loadAttendee(ctx, h.firestore, attendeeID)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if attendee.Cancelled() {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "The registration is cancelled"))
+		return
+	}
+
+	id := models.AttendanceID(sessionID, attendeeID)
+	attendance := models.SessionAttendance{SessionID: sessionID, AttendeeID: attendeeID, CheckedInAt: h.now()}
+	code := http.StatusCreated
+	err = h.firestore.Create(ctx, attendanceCollection, id, attendance)
+	if status.Code(err) == codes.AlreadyExists {
+		code = http.StatusOK
+		var doc *firestore.DocumentSnapshot
+		if doc, err = h.firestore.Get(ctx, attendanceCollection, id); err == nil {
+			err = doc.DataTo(&attendance)
+		}
+	}
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	attendance.ID = id
+	attendance.Name = attendee.Name
+	c.JSON(code, attendance)
+}
+
+// RemoveAttendance undoes a check-in made by mistake. Feedback the attendee
+// already gave is kept. Admin only.
+func (h *AttendanceHandler) RemoveAttendance(c *gin.Context) {
+	id := models.AttendanceID(c.Param("id"), c.Param("attendeeId"))
+	if err := h.firestore.Delete(c.Request.Context(), attendanceCollection, id); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Check-in removed"})
+}
+
+// GetMyAttendance lists the sessions the attendee the link was issued for
+// was checked in to.
+func (h *AttendanceHandler) GetMyAttendance(c *gin.Context) {
+	if h.links == nil {
+		apierror.Abort(c, apierror.NotFound("Self-service is not enabled"))
+		return
+	}
+	attendeeID, ok := verifyLink(c, h.links)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := loadAttendee(ctx, h.firestore, attendeeID); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	attendance := []models.SessionAttendance{}
+	query := h.firestore.GetCollection(attendanceCollection).Where("attendeeId", "==", attendeeID)
+	err := h.firestore.Documents(ctx, attendanceCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		var a models.SessionAttendance
+		if err := doc.DataTo(&a); err != nil {
+			return err
+		}
+		a.ID = doc.Ref.ID
+		attendance = append(attendance, a)
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
+
+// attended reports whether the attendee was checked in to the session.
+func attended(ctx context.Context, fs *services.FirestoreService, sessionID, attendeeID string) (bool, error) {
+	_, err := fs.Get(ctx, attendanceCollection, models.AttendanceID(sessionID, attendeeID))
+	if err == nil {
+		return true, nil
+	}
+	if apierror.From(err).Code == apierror.CodeNotFound {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttendanceHandler_GetMyAttendance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	links := services.NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com")
+
+	tests := []struct {
+		name   string
+		links  *services.MagicLinks
+		header string
+		status int
+	}{
+		{"self-service disabled", nil, "", http.StatusNotFound},
+		{"no token", links, "", http.StatusUnauthorized},
+		{"invalid token", links, "Bearer nope", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/api/attendees/me/attendance", NewAttendanceHandler(nil, tt.links).GetMyAttendance)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/attendees/me/attendance", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -1,108 +1,411 @@
 package handlers
 
 import (
-	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/logging"
+	"appdirect-ai-workshop/internal/metrics"
 	"appdirect-ai-workshop/internal/models"
 	"appdirect-ai-workshop/internal/services"
 
+	"cloud.google.com/go/firestore"
 	"github.com/gin-gonic/gin"
-	"google.golang.org/api/iterator"
 )
 
 type AttendeeHandler struct {
 	firestore *services.FirestoreService
+	challenge services.ChallengeVerifier
+	outbox    *services.Outbox
+	index     *services.AttendeeIndex
+	metrics   *metrics.Metrics
 }
 
-func NewAttendeeHandler(firestore *services.FirestoreService) *AttendeeHandler {
-	return &AttendeeHandler{firestore: firestore}
+// NewAttendeeHandler creates an AttendeeHandler. challenge may be nil to
+// accept registrations without bot protection, index may be nil to search
+// Firestore directly, and metrics may be nil to disable instrumentation.
+func NewAttendeeHandler(firestore *services.FirestoreService, challenge services.ChallengeVerifier, outbox *services.Outbox, index *services.AttendeeIndex, metrics *metrics.Metrics) *AttendeeHandler {
+	return &AttendeeHandler{firestore: firestore, challenge: challenge, outbox: outbox, index: index, metrics: metrics}
 }
 
+// Limits on attendee-provided text, shared by registration and self-service.
+const (
+	maxNameLength        = 200
+	maxDesignationLength = 100
+	maxSearchLength      = 100
+)
+
 type CreateAttendeeRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Email       string `json:"email" binding:"required,email"`
-	Designation string `json:"designation" binding:"required"`
+	Name        string `json:"name" binding:"required,max=200"`
+	Email       string `json:"email" binding:"required,email,max=254"`
+	Designation string `json:"designation" binding:"required,max=100"`
+
+	// ListPublicly opts in to the public attendee list
+	ListPublicly bool `json:"listPublicly"`
+
+	// Answers to the custom questions from GET /api/attendees/form, keyed
+	// by question key
+	Answers map[string]any `json:"answers,omitempty"`
+
+	// Bot protection
+	ChallengeToken    string `json:"challengeToken,omitempty"`
+	ChallengeSolution string `json:"challengeSolution,omitempty"`
+	CaptchaToken      string `json:"captchaToken,omitempty"`
+	Website           string `json:"website,omitempty"` // honeypot, hidden from humans
 }
 
+// GetAttendees lists every attendee with their personal data. Admin only.
 func (h *AttendeeHandler) GetAttendees(c *gin.Context) {
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("attendees")
+	ctx := c.Request.Context()
 
 	var attendees []models.Attendee
-	iter := collection.Documents(ctx)
-	defer iter.Stop()
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
+	err := h.firestore.All(ctx, "attendees", func(doc *firestore.DocumentSnapshot) error {
+		var attendee models.Attendee
+		if err := doc.DataTo(&attendee); err != nil {
+			return err
 		}
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		attendee.ID = doc.Ref.ID
+		attendees = append(attendees, attendee)
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attendees)
+}
+
+// SearchAttendees finds attendees by name or email, best matches first. q
+// is matched ignoring case and accents, by word prefix or substring;
+// designation may be repeated; limit defaults to 20. Admin only.
+func (h *AttendeeHandler) SearchAttendees(c *gin.Context) {
+	query, err := searchQuery(c)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	index := h.index
+	if index == nil || !index.Ready() {
+		// The change feed has not loaded the index yet
+		if index, err = h.loadIndex(c); err != nil {
+			apierror.Abort(c, err)
 			return
 		}
+	}
 
+	results, total := index.Search(query)
+	c.JSON(http.StatusOK, models.AttendeeSearchResult{Results: results, Total: total})
+}
+
+// loadIndex builds a one-off search index from every attendee.
+func (h *AttendeeHandler) loadIndex(c *gin.Context) (*services.AttendeeIndex, error) {
+	index := services.NewAttendeeIndex()
+	err := h.firestore.All(c.Request.Context(), "attendees", func(doc *firestore.DocumentSnapshot) error {
 		var attendee models.Attendee
 		if err := doc.DataTo(&attendee); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+			return err
 		}
 		attendee.ID = doc.Ref.ID
-		attendees = append(attendees, attendee)
+		index.Put(attendee)
+		return nil
+	})
+	return index, err
+}
+
+// searchQuery reads and validates the search parameters.
+func searchQuery(c *gin.Context) (services.AttendeeQuery, error) {
+	query := services.AttendeeQuery{
+		Text:         c.Query("q"),
+		Designations: c.QueryArray("designation"),
+		Limit:        services.DefaultSearchLimit,
 	}
 
-	c.JSON(http.StatusOK, attendees)
+	var details []apierror.FieldError
+	if utf8.RuneCountInString(query.Text) > maxSearchLength {
+		details = append(details, apierror.FieldError{Field: "q", Code: "max", Message: fmt.Sprintf("must be at most %d characters", maxSearchLength)})
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > services.MaxSearchLimit {
+			details = append(details, apierror.FieldError{Field: "limit", Code: "range", Message: fmt.Sprintf("must be a number from 1 to %d", services.MaxSearchLimit)})
+		}
+		query.Limit = limit
+	}
+	if len(details) > 0 {
+		apiErr := apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Validation failed")
+		apiErr.Details = details
+		return query, apiErr
+	}
+	return query, nil
 }
 
-func (h *AttendeeHandler) GetCount(c *gin.Context) {
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("attendees")
+// CheckIn records that the attendee arrived at the event. Checking in twice
+// keeps the first time. Admin only.
+func (h *AttendeeHandler) CheckIn(c *gin.Context) {
+	id := c.Param("id")
+	ctx := c.Request.Context()
 
-	count := 0
-	iter := collection.Documents(ctx)
-	defer iter.Stop()
+	doc, err := h.firestore.Get(ctx, "attendees", id)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	var attendee models.Attendee
+	if err := doc.DataTo(&attendee); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	attendee.ID = doc.Ref.ID
 
-	for {
-		_, err := iter.Next()
-		if err == iterator.Done {
-			break
+	if attendee.Cancelled() {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "The registration is cancelled"))
+		return
+	}
+	if !attendee.CheckedIn() {
+		now := time.Now()
+		if err := h.firestore.Update(ctx, "attendees", id, []firestore.Update{{Path: "checkedInAt", Value: now}}); err != nil {
+			apierror.Abort(c, err)
+			return
 		}
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		attendee.CheckedInAt = &now
+	}
+
+	c.JSON(http.StatusOK, attendee)
+}
+
+// EmailBackfill reports what BackfillEmails changed.
+type EmailBackfill struct {
+	Scanned int `json:"scanned"`
+	Updated int `json:"updated"`
+}
+
+// BackfillEmails stores the normalized address on registrations made before
+// it was kept, so lookups by address find them. Running it again is safe.
+func (h *AttendeeHandler) BackfillEmails(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var result EmailBackfill
+	changes := make(map[string]string)
+	err := h.firestore.All(ctx, "attendees", func(doc *firestore.DocumentSnapshot) error {
+		result.Scanned++
+		data := doc.Data()
+		email, _ := data["email"].(string)
+		stored, _ := data["emailNormalized"].(string)
+		if normalized := services.NormalizeEmail(email); normalized != stored {
+			changes[doc.Ref.ID] = normalized
+		}
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	for id, normalized := range changes {
+		if err := h.firestore.Update(ctx, "attendees", id, []firestore.Update{{Path: "emailNormalized", Value: normalized}}); err != nil {
+			apierror.Abort(c, err)
 			return
 		}
-		count++
+		result.Updated++
 	}
 
+	logging.FromContext(ctx).Info("Attendee emails backfilled", "scanned", result.Scanned, "updated", result.Updated)
+	c.JSON(http.StatusOK, result)
+}
+
+// GetPublicAttendees lists the first name and designation of attendees who
+// opted in to being listed.
+func (h *AttendeeHandler) GetPublicAttendees(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := h.firestore.GetCollection("attendees").Where("listPublicly", "==", true)
+	var registered []models.Attendee
+	err := h.firestore.Documents(ctx, "attendees", query, func(doc *firestore.DocumentSnapshot) error {
+		var attendee models.Attendee
+		if err := doc.DataTo(&attendee); err != nil {
+			return err
+		}
+		if attendee.Cancelled() {
+			return nil
+		}
+		registered = append(registered, attendee)
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].RegisteredAt.Before(registered[j].RegisteredAt)
+	})
+	attendees := make([]models.PublicAttendee, 0, len(registered))
+	for _, attendee := range registered {
+		attendees = append(attendees, models.NewPublicAttendee(attendee))
+	}
+
+	c.JSON(http.StatusOK, attendees)
+}
+
+func (h *AttendeeHandler) GetCount(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	count := 0
+	err := h.firestore.All(ctx, "attendees", func(doc *firestore.DocumentSnapshot) error {
+		if !isCancelled(doc) {
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	h.metrics.SetAttendees(count)
 	c.JSON(http.StatusOK, models.AttendeeCount{Count: count})
 }
 
+// GetChallenge issues a bot protection challenge for the registration form.
+func (h *AttendeeHandler) GetChallenge(c *gin.Context) {
+	if h.challenge == nil {
+		c.JSON(http.StatusOK, gin.H{"challenge": nil})
+		return
+	}
+
+	challenge, err := h.challenge.Issue()
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"challenge": challenge})
+}
+
 func (h *AttendeeHandler) CreateAttendee(c *gin.Context) {
 	var req CreateAttendeeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.InvalidBody(err))
 		return
 	}
 
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("attendees")
+	name, designation, err := validateAttendeeDetails(req.Name, req.Designation)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if h.challenge != nil {
+		err := h.challenge.Verify(ctx, services.ChallengeSubmission{
+			Token:        req.ChallengeToken,
+			Solution:     req.ChallengeSolution,
+			CaptchaToken: req.CaptchaToken,
+			Honeypot:     req.Website,
+			RemoteIP:     c.ClientIP(),
+		})
+		if errors.Is(err, services.ErrChallengeFailed) {
+			logging.FromContext(ctx).Warn("registration rejected", "reason", err.Error())
+			apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.CodeVerificationFailed, "Verification failed, please reload the page and try again"))
+			return
+		}
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+	}
+
+	// Bots are turned away before the designations and questions are read
+	taxonomy, err := loadTaxonomy(ctx, h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if designation, err = normalizeDesignation(taxonomy, designation); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	questions, err := loadQuestions(ctx, h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	answers, err := validateAnswers(questions, req.Answers)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
 
 	attendee := models.Attendee{
-		Name:         req.Name,
-		Email:        req.Email,
-		Designation:  req.Designation,
-		RegisteredAt: time.Now(),
+		Name:            name,
+		Email:           strings.TrimSpace(req.Email),
+		EmailNormalized: services.NormalizeEmail(req.Email),
+		Designation:     designation,
+		ListPublicly:    req.ListPublicly,
+		Answers:         answers,
+		Status:          models.StatusConfirmed,
+		RegisteredAt:    time.Now(),
 	}
 
-	docRef, _, err := collection.Add(ctx, attendee)
+	err = h.outbox.Transact(ctx, func(tx *services.Tx) error {
+		ref, err := tx.Create("attendees", attendee)
+		if err != nil {
+			return err
+		}
+		attendee.ID = ref.ID
+		return tx.Emit(models.EventAttendeeCreated, attendee.ID, attendee)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Abort(c, err)
 		return
 	}
 
-	attendee.ID = docRef.ID
+	h.metrics.RegistrationCreated(attendee.Designation)
+
 	c.JSON(http.StatusCreated, attendee)
 }
 
+// validateAttendeeDetails applies the checks shared by registration and
+// self-service updates on top of the binding tags, returning the cleaned
+// values.
+func validateAttendeeDetails(name, designation string) (string, string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	designation = strings.TrimSpace(designation)
+
+	var details []apierror.FieldError
+	switch {
+	case name == "":
+		details = append(details, apierror.FieldError{Field: "name", Code: "required", Message: "is required"})
+	case utf8.RuneCountInString(name) > maxNameLength:
+		details = append(details, apierror.FieldError{Field: "name", Code: "max", Message: fmt.Sprintf("must be at most %d characters", maxNameLength)})
+	}
+	switch {
+	case designation == "":
+		details = append(details, apierror.FieldError{Field: "designation", Code: "required", Message: "is required"})
+	case utf8.RuneCountInString(designation) > maxDesignationLength:
+		details = append(details, apierror.FieldError{Field: "designation", Code: "max", Message: fmt.Sprintf("must be at most %d characters", maxDesignationLength)})
+	}
+	if len(details) > 0 {
+		apiErr := apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Validation failed")
+		apiErr.Details = details
+		return "", "", apiErr
+	}
+	return name, designation, nil
+}
+
+// isCancelled reports whether an attendee document is a cancelled
+// registration without decoding all of it.
+func isCancelled(doc *firestore.DocumentSnapshot) bool {
+	status, _ := doc.Data()["status"].(string)
+	return status == models.StatusCancelled
+}
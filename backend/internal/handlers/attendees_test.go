@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"appdirect-ai-workshop/internal/config"
 	"appdirect-ai-workshop/internal/models"
 	"appdirect-ai-workshop/internal/services"
 
@@ -21,6 +23,9 @@ type InMemoryStore struct {
 	sessions  []models.Session
 }
 
+// testFirestoreConfig keeps test writes out of the real workshop document.
+var testFirestoreConfig = config.FirestoreConfig{ProjectID: "test-project", SubdocID: "test"}
+
 func TestAttendeeHandler_CreateAttendee(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -61,11 +66,11 @@ func TestAttendeeHandler_CreateAttendee(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a firestore service
 			// Note: Tests will skip if Firestore is not available (no credentials)
-			mockService, err := services.NewFirestoreService()
+			mockService, err := services.NewFirestoreService(testFirestoreConfig)
 			if err != nil {
 				t.Skipf("Skipping test: Firestore not available: %v", err)
 			}
-			handler := NewAttendeeHandler(mockService)
+			handler := NewAttendeeHandler(mockService, nil, services.NewOutbox(mockService), nil, nil)
 
 			router := gin.New()
 			router.POST("/api/attendees", handler.CreateAttendee)
@@ -85,11 +90,11 @@ func TestAttendeeHandler_CreateAttendee(t *testing.T) {
 func TestAttendeeHandler_GetCount(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	mockService, err := services.NewFirestoreService()
+	mockService, err := services.NewFirestoreService(testFirestoreConfig)
 	if err != nil {
 		t.Skipf("Skipping test: Firestore not available: %v", err)
 	}
-	handler := NewAttendeeHandler(mockService)
+	handler := NewAttendeeHandler(mockService, nil, nil, nil, nil)
 
 	router := gin.New()
 	router.GET("/api/attendees/count", handler.GetCount)
@@ -107,3 +112,111 @@ func TestAttendeeHandler_GetCount(t *testing.T) {
 	assert.GreaterOrEqual(t, response.Count, 0)
 }
 
+
+type stubChallenge struct {
+	err error
+	got services.ChallengeSubmission
+}
+
+func (s *stubChallenge) Issue() (*services.Challenge, error) {
+	return &services.Challenge{Token: "token", Difficulty: 1}, nil
+}
+
+func (s *stubChallenge) Verify(ctx context.Context, sub services.ChallengeSubmission) error {
+	s.got = sub
+	return s.err
+}
+
+func TestAttendeeHandler_CreateAttendee_ChallengeFailed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	challenge := &stubChallenge{err: services.ErrChallengeFailed}
+	handler := NewAttendeeHandler(nil, challenge, nil, nil, nil)
+
+	router := gin.New()
+	router.POST("/api/attendees", handler.CreateAttendee)
+
+	body, _ := json.Marshal(CreateAttendeeRequest{
+		Name:              "John Doe",
+		Email:             "john@example.com",
+		Designation:       "Software Engineer",
+		ChallengeToken:    "token",
+		ChallengeSolution: "42",
+		Website:           "http://spam.example.com",
+	})
+	req, _ := http.NewRequest("POST", "/api/attendees", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "token", challenge.got.Token)
+	assert.Equal(t, "42", challenge.got.Solution)
+	assert.Equal(t, "http://spam.example.com", challenge.got.Honeypot)
+}
+
+func TestAttendeeHandler_GetChallenge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAttendeeHandler(nil, &stubChallenge{}, nil, nil, nil)
+
+	router := gin.New()
+	router.GET("/api/attendees/challenge", handler.GetChallenge)
+
+	req, _ := http.NewRequest("GET", "/api/attendees/challenge", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"token"`)
+}
+
+func TestAttendeeHandler_SearchAttendees(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	index := services.NewAttendeeIndex()
+	index.Apply(nil)
+	index.Put(models.Attendee{ID: "1", Name: "Jane Doe", Email: "jane@example.com", Designation: "Engineer"})
+	index.Put(models.Attendee{ID: "2", Name: "Rajan Iyer", Email: "rajan@example.com", Designation: "Designer"})
+	handler := NewAttendeeHandler(nil, nil, nil, index, nil)
+
+	router := gin.New()
+	router.GET("/api/admin/attendees/search", handler.SearchAttendees)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedIDs    []string
+		expectedTotal  int
+	}{
+		{"prefix and substring", "q=jan", http.StatusOK, []string{"1", "2"}, 2},
+		{"designation filter", "q=jan&designation=Designer", http.StatusOK, []string{"2"}, 1},
+		{"limit", "q=jan&limit=1", http.StatusOK, []string{"1"}, 2},
+		{"invalid limit", "q=jan&limit=0", http.StatusBadRequest, nil, 0},
+		{"query too long", "q=" + string(bytes.Repeat([]byte("a"), 101)), http.StatusBadRequest, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/admin/attendees/search?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedIDs == nil {
+				return
+			}
+			var result models.AttendeeSearchResult
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			var ids []string
+			for _, match := range result.Results {
+				ids = append(ids, match.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, tt.expectedTotal, result.Total)
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/logging"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// DesignationHandler manages the designations offered on the registration
+// form and the aliases that map onto them.
+type DesignationHandler struct {
+	firestore *services.FirestoreService
+	outbox    *services.Outbox
+}
+
+func NewDesignationHandler(firestore *services.FirestoreService, outbox *services.Outbox) *DesignationHandler {
+	return &DesignationHandler{firestore: firestore, outbox: outbox}
+}
+
+// GetOptions lists the designation names for the registration form.
+func (h *DesignationHandler) GetOptions(c *gin.Context) {
+	taxonomy, err := loadTaxonomy(c.Request.Context(), h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, taxonomy.Names())
+}
+
+// GetDesignations lists the designations defined by admins. While there are
+// none, the form offers models.DefaultDesignations.
+func (h *DesignationHandler) GetDesignations(c *gin.Context) {
+	designations, err := loadDesignations(c.Request.Context(), h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, designations)
+}
+
+func (h *DesignationHandler) CreateDesignation(c *gin.Context) {
+	var req models.DesignationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	designation := newDesignation(req)
+	err := h.changeDesignations(c.Request.Context(), func(tx *services.Tx, existing []models.Designation) error {
+		if err := models.CheckDesignations(append(existing, designation)); err != nil {
+			return apierror.New(http.StatusConflict, apierror.CodeConflict, err.Error())
+		}
+		ref, err := tx.Create("designations", designation)
+		if err != nil {
+			return err
+		}
+		designation.ID = ref.ID
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, designation)
+}
+
+// UpdateDesignation replaces a designation. Renaming it does not change
+// existing registrations until BackfillAttendees is run with the old name
+// listed as an alias.
+func (h *DesignationHandler) UpdateDesignation(c *gin.Context) {
+	id := c.Param("id")
+	var req models.DesignationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	designation := newDesignation(req)
+	designation.ID = id
+	updates := []firestore.Update{
+		{Path: "name", Value: designation.Name},
+		{Path: "aliases", Value: designation.Aliases},
+		{Path: "order", Value: designation.Order},
+	}
+
+	err := h.changeDesignations(c.Request.Context(), func(tx *services.Tx, existing []models.Designation) error {
+		found := false
+		for i := range existing {
+			if existing[i].ID == id {
+				existing[i] = designation
+				found = true
+			}
+		}
+		if !found {
+			return apierror.NotFound("Designation not found")
+		}
+		if err := models.CheckDesignations(existing); err != nil {
+			return apierror.New(http.StatusConflict, apierror.CodeConflict, err.Error())
+		}
+		return tx.Update("designations", id, updates)
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, designation)
+}
+
+// DeleteDesignation removes a designation from the form. Registrations
+// that use it keep it.
+func (h *DesignationHandler) DeleteDesignation(c *gin.Context) {
+	id := c.Param("id")
+	ctx := c.Request.Context()
+
+	if err := h.firestore.Delete(ctx, "designations", id); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Designation deleted successfully"})
+}
+
+// BackfillResult reports what a backfill changed. Unmatched counts the
+// designations that match no name or alias, so admins can add aliases for
+// them and run the backfill again.
+type BackfillResult struct {
+	Scanned   int            `json:"scanned"`
+	Updated   int            `json:"updated"`
+	Unmatched map[string]int `json:"unmatched"`
+}
+
+// BackfillAttendees rewrites the designation of every registration to its
+// canonical name, so stats group them correctly. Running it again is safe.
+func (h *DesignationHandler) BackfillAttendees(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	taxonomy, err := loadTaxonomy(ctx, h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	result := BackfillResult{Unmatched: make(map[string]int)}
+	changes := make(map[string]string)
+	err = h.firestore.All(ctx, "attendees", func(doc *firestore.DocumentSnapshot) error {
+		result.Scanned++
+		designation, _ := doc.Data()["designation"].(string)
+		name, ok := taxonomy.Normalize(designation)
+		if !ok {
+			result.Unmatched[designation]++
+			return nil
+		}
+		if name != designation {
+			changes[doc.Ref.ID] = name
+		}
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	for id, name := range changes {
+		if err := h.firestore.Update(ctx, "attendees", id, []firestore.Update{{Path: "designation", Value: name}}); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		result.Updated++
+	}
+
+	logging.FromContext(ctx).Info("Designations backfilled", "scanned", result.Scanned, "updated", result.Updated, "unmatched", len(result.Unmatched))
+	c.JSON(http.StatusOK, result)
+}
+
+func newDesignation(req models.DesignationRequest) models.Designation {
+	designation := models.Designation{Name: strings.TrimSpace(req.Name), Order: req.Order}
+	for _, alias := range req.Aliases {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			designation.Aliases = append(designation.Aliases, alias)
+		}
+	}
+	return designation
+}
+
+// loadDesignations returns the admin-defined designations in display order.
+func loadDesignations(ctx context.Context, fs *services.FirestoreService) ([]models.Designation, error) {
+	designations := []models.Designation{}
+	err := fs.All(ctx, "designations", func(doc *firestore.DocumentSnapshot) error {
+		return appendDesignation(&designations, doc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortDesignations(designations)
+	return designations, nil
+}
+
+// designationsLock is written by every transaction that checks the
+// designations against a change, so two such transactions conflict even
+// when each only adds a document the other did not read.
+const designationsLock = "designations"
+
+// changeDesignations runs fn in a transaction with every designation, so
+// the check fn makes and the write it does cannot race another change.
+func (h *DesignationHandler) changeDesignations(ctx context.Context, fn func(tx *services.Tx, existing []models.Designation) error) error {
+	return h.outbox.Transact(ctx, func(tx *services.Tx) error {
+		if _, err := tx.Get("locks", designationsLock); err != nil && apierror.From(err).Code != apierror.CodeNotFound {
+			return err
+		}
+		docs, err := tx.All("designations")
+		if err != nil {
+			return err
+		}
+		existing := []models.Designation{}
+		for _, doc := range docs {
+			if err := appendDesignation(&existing, doc); err != nil {
+				return err
+			}
+		}
+		sortDesignations(existing)
+
+		if err := fn(tx, existing); err != nil {
+			return err
+		}
+		return tx.Set("locks", designationsLock, map[string]any{"updatedAt": firestore.ServerTimestamp})
+	})
+}
+
+func appendDesignation(designations *[]models.Designation, doc *firestore.DocumentSnapshot) error {
+	var designation models.Designation
+	if err := doc.DataTo(&designation); err != nil {
+		return err
+	}
+	designation.ID = doc.Ref.ID
+	*designations = append(*designations, designation)
+	return nil
+}
+
+func sortDesignations(designations []models.Designation) {
+	sort.Slice(designations, func(i, j int) bool {
+		if designations[i].Order != designations[j].Order {
+			return designations[i].Order < designations[j].Order
+		}
+		return designations[i].Name < designations[j].Name
+	})
+}
+
+// loadTaxonomy returns the designations in effect: the admin-defined ones,
+// or the defaults while there are none.
+func loadTaxonomy(ctx context.Context, fs *services.FirestoreService) (*models.Taxonomy, error) {
+	designations, err := loadDesignations(ctx, fs)
+	if err != nil {
+		return nil, err
+	}
+	if len(designations) == 0 {
+		return models.NewTaxonomy(models.DefaultDesignations), nil
+	}
+	return models.NewTaxonomy(designations), nil
+}
+
+// normalizeDesignation maps a submitted designation to its canonical name,
+// rejecting values that match none.
+func normalizeDesignation(taxonomy *models.Taxonomy, designation string) (string, error) {
+	name, ok := taxonomy.Normalize(designation)
+	if !ok {
+		apiErr := apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Validation failed")
+		apiErr.Details = []apierror.FieldError{{Field: "designation", Code: "oneof", Message: "must be one of the designations from GET /api/designations"}}
+		return "", apiErr
+	}
+	return name, nil
+}
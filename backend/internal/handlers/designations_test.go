@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDesignation(t *testing.T) {
+	designation := newDesignation(models.DesignationRequest{
+		Name:    "  Tech Lead ",
+		Aliases: []string{" Team Lead", "", "  "},
+		Order:   3,
+	})
+	assert.Equal(t, models.Designation{Name: "Tech Lead", Aliases: []string{"Team Lead"}, Order: 3}, designation)
+}
+
+func TestNormalizeDesignation(t *testing.T) {
+	taxonomy := models.NewTaxonomy([]models.Designation{{Name: "Engineering Manager", Aliases: []string{"EM"}}})
+
+	name, err := normalizeDesignation(taxonomy, "em")
+	assert.NoError(t, err)
+	assert.Equal(t, "Engineering Manager", name)
+
+	_, err = normalizeDesignation(taxonomy, "Chef")
+	var apiErr *apierror.Error
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, apierror.CodeValidationFailed, apiErr.Code)
+		assert.Equal(t, "designation", apiErr.Details[0].Field)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/logging"
+	"appdirect-ai-workshop/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Stream timing. Proxies and load balancers close connections that stay
+// silent, so a comment is sent at least every heartbeat; clients wait
+// reconnectDelay before reconnecting after a stream ends.
+const (
+	heartbeat      = 25 * time.Second
+	reconnectDelay = 3 * time.Second
+)
+
+// EventHandler streams live updates to browsers as server-sent events.
+type EventHandler struct {
+	hub       *services.EventHub
+	heartbeat time.Duration
+}
+
+func NewEventHandler(hub *services.EventHub) *EventHandler {
+	return &EventHandler{hub: hub, heartbeat: heartbeat}
+}
+
+// Stream sends the registration count and agenda changes.
+func (h *EventHandler) Stream(c *gin.Context) {
+	h.stream(c, false)
+}
+
+// AdminStream also sends every new registration. Admin only.
+func (h *EventHandler) AdminStream(c *gin.Context) {
+	h.stream(c, true)
+}
+
+func (h *EventHandler) stream(c *gin.Context, admin bool) {
+	sub, initial, err := h.hub.Subscribe(admin, c.GetHeader("Last-Event-ID"))
+	if errors.Is(err, services.ErrTooManySubscribers) || errors.Is(err, services.ErrHubClosed) {
+		apiErr := apierror.Wrap(err, http.StatusServiceUnavailable, apierror.CodeUnavailable, "Live updates are busy, please poll instead")
+		apiErr.RetryAfter = 30 * time.Second
+		apierror.Abort(c, apiErr)
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	// The server's write timeout would otherwise cut every stream short
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	for _, e := range initial {
+		if err := writeEvent(w, e); err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to write live event", "type", e.Type, "error", err)
+		}
+	}
+	w.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-sub.C:
+			if !ok {
+				// Shutting down, or the client fell behind; it reconnects
+				// and resumes from its last event
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				logging.FromContext(c.Request.Context()).Error("Failed to write live event", "type", e.Type, "error", err)
+				return
+			}
+		}
+		w.Flush()
+	}
+}
+
+// writeEvent writes e in the text/event-stream format.
+func writeEvent(w io.Writer, e services.Event) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	if e.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", e.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	return err
+}
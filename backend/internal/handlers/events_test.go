@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventHandler_Stream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hub := services.NewEventHub(10, 10)
+	hub.Publish(services.Event{Type: services.EventCount, Data: models.AttendeeCount{Count: 3}})
+	handler := NewEventHandler(hub)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	w := httptest.NewRecorder()
+	router := gin.New()
+	router.GET("/api/events", handler.Stream)
+
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(w, req)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, time.Millisecond)
+	hub.Publish(services.Event{ID: "1-r", Type: services.EventRegistration, Data: models.Attendee{Name: "Jane"}, AdminOnly: true})
+	hub.Publish(services.Event{ID: "2-s", Type: services.EventAgenda, Data: services.AgendaChange{Collection: "sessions", Change: services.ChangeRemoved, ID: "s"}})
+	hub.Publish(services.Event{Type: services.EventReset, Data: struct{}{}})
+	// Closing the hub ends the stream once the queued events are written
+	hub.Close()
+	<-done
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "retry: 3000\n\n"+
+		"event: count\ndata: {\"count\":3}\n\n"+
+		"id: 2-s\nevent: agenda\ndata: {\"collection\":\"sessions\",\"change\":\"removed\",\"id\":\"s\"}\n\n"+
+		"event: reset\ndata: {}\n\n", w.Body.String())
+	assert.Equal(t, 0, hub.Subscribers())
+}
+
+func TestEventHandler_Stream_Full(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hub := services.NewEventHub(1, 10)
+	_, _, _ = hub.Subscribe(false, "")
+
+	router := gin.New()
+	router.GET("/api/events", NewEventHandler(hub).Stream)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+}
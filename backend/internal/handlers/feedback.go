@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/config"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const feedbackExportFilename = "session-feedback.csv"
+
+// FeedbackHandler takes attendees' ratings of the sessions they attended and
+// reports them to admins and, through a link of their own, to speakers.
+// Neither sees who left which rating.
+type FeedbackHandler struct {
+	firestore    *services.FirestoreService
+	links        *services.MagicLinks
+	speakerLinks *services.MagicLinks
+	editWindow   time.Duration
+	now          func() time.Time
+}
+
+// NewFeedbackHandler creates a FeedbackHandler. Attendees authenticate with
+// their self-service links; links may be nil, which disables everything but
+// the admin reports.
+func NewFeedbackHandler(firestore *services.FirestoreService, links *services.MagicLinks, cfg config.FeedbackConfig) *FeedbackHandler {
+	h := &FeedbackHandler{firestore: firestore, links: links, editWindow: cfg.EditWindow, now: time.Now}
+	if links != nil {
+		h.speakerLinks = links.SpeakerLinks(cfg.SpeakerLinkTTL)
+	}
+	return h
+}
+
+// SpeakerLink is a link for a speaker to read the feedback on their sessions.
+type SpeakerLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SubmitFeedback rates a session for the attendee the link was issued for.
+// Attendees checked in to a session may rate it once it has started.
+// Submitting again changes the rating while the edit window is open.
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	attendeeID, ok := h.authenticate(c, h.links)
+	if !ok {
+		return
+	}
+
+	var req models.FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	sessionID := c.Param("sessionId")
+
+	attendee, err := loadAttendee(ctx, h.firestore, attendeeID)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if attendee.Cancelled() {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "This registration has been cancelled"))
+		return
+	}
+
+	session, err := loadSession(ctx, h.firestore, sessionID)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	now := h.now()
+	if session.StartsAt == nil || now.Before(*session.StartsAt) {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "Feedback opens when the session starts"))
+		return
+	}
+	present, err := attended(ctx, h.firestore, sessionID, attendeeID)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if !present {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "Only attendees checked in to the session can rate it"))
+		return
+	}
+
+	id := models.FeedbackID(sessionID, attendeeID)
+	feedback := models.Feedback{
+		SessionID:   sessionID,
+		AttendeeID:  attendeeID,
+		Rating:      req.Rating,
+		Comment:     strings.TrimSpace(req.Comment),
+		SubmittedAt: now,
+	}
+
+	code := http.StatusCreated
+	err = h.firestore.Create(ctx, "feedback", id, feedback)
+	if status.Code(err) == codes.AlreadyExists {
+		code = http.StatusOK
+		feedback, err = h.edit(ctx, id, feedback)
+	}
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	feedback.ID = id
+	h.setEditableUntil(&feedback)
+	c.JSON(code, feedback)
+}
+
+// edit changes the submitted feedback stored under id to the rating and
+// comment of change, if it is still within the edit window.
+func (h *FeedbackHandler) edit(ctx context.Context, id string, change models.Feedback) (models.Feedback, error) {
+	var feedback models.Feedback
+	doc, err := h.firestore.Get(ctx, "feedback", id)
+	if err != nil {
+		return feedback, err
+	}
+	if err := doc.DataTo(&feedback); err != nil {
+		return feedback, err
+	}
+	if !feedback.Editable(change.SubmittedAt, h.editWindow) {
+		return feedback, apierror.New(http.StatusConflict, apierror.CodeConflict, "Feedback can no longer be changed")
+	}
+
+	updatedAt := change.SubmittedAt
+	feedback.Rating = change.Rating
+	feedback.Comment = change.Comment
+	feedback.UpdatedAt = &updatedAt
+	err = h.firestore.Update(ctx, "feedback", id, []firestore.Update{
+		{Path: "rating", Value: feedback.Rating},
+		{Path: "comment", Value: feedback.Comment},
+		{Path: "updatedAt", Value: updatedAt},
+	})
+	return feedback, err
+}
+
+// GetMyFeedback returns the feedback the attendee has given, with when each
+// can no longer be changed.
+func (h *FeedbackHandler) GetMyFeedback(c *gin.Context) {
+	attendeeID, ok := h.authenticate(c, h.links)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := loadAttendee(ctx, h.firestore, attendeeID); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	feedback := []models.Feedback{}
+	query := h.firestore.GetCollection("feedback").Where("attendeeId", "==", attendeeID)
+	err := h.firestore.Documents(ctx, "feedback", query, func(doc *firestore.DocumentSnapshot) error {
+		var f models.Feedback
+		if err := doc.DataTo(&f); err != nil {
+			return err
+		}
+		f.ID = doc.Ref.ID
+		h.setEditableUntil(&f)
+		feedback = append(feedback, f)
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
+
+// GetFeedback returns the feedback summary of every session, or of the one
+// given with ?session=.
+func (h *FeedbackHandler) GetFeedback(c *gin.Context) {
+	sessions, feedback, ok := h.adminFeedback(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, summarize(sessions, feedback))
+}
+
+// ExportFeedback downloads the feedback behind GetFeedback as CSV.
+func (h *FeedbackHandler) ExportFeedback(c *gin.Context) {
+	sessions, feedback, ok := h.adminFeedback(c)
+	if !ok {
+		return
+	}
+	writeFeedbackCSV(c, sessions, feedback)
+}
+
+// CreateSpeakerLink issues a link for the speaker to read the feedback on
+// their sessions. The link is returned rather than emailed, as speakers'
+// addresses are not stored.
+func (h *FeedbackHandler) CreateSpeakerLink(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := h.firestore.Get(c.Request.Context(), "speakers", id); err != nil {
+		if apierror.From(err).Code == apierror.CodeNotFound {
+			err = apierror.NotFound("Speaker not found")
+		}
+		apierror.Abort(c, err)
+		return
+	}
+
+	token, expiresAt := h.speakerLinks.Issue(id)
+	c.JSON(http.StatusCreated, SpeakerLink{URL: h.speakerLinks.URL(token), ExpiresAt: expiresAt})
+}
+
+// GetSpeakerFeedback returns the feedback summaries of the sessions of the
+// speaker the link was issued for.
+func (h *FeedbackHandler) GetSpeakerFeedback(c *gin.Context) {
+	sessions, feedback, ok := h.speakerFeedback(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, summarize(sessions, feedback))
+}
+
+// ExportSpeakerFeedback downloads the feedback behind GetSpeakerFeedback as
+// CSV.
+func (h *FeedbackHandler) ExportSpeakerFeedback(c *gin.Context) {
+	sessions, feedback, ok := h.speakerFeedback(c)
+	if !ok {
+		return
+	}
+	writeFeedbackCSV(c, sessions, feedback)
+}
+
+// adminFeedback loads the sessions an admin asked for and their feedback.
+func (h *FeedbackHandler) adminFeedback(c *gin.Context) ([]models.Session, map[string][]models.Feedback, bool) {
+	ctx := c.Request.Context()
+
+	var sessions []models.Session
+	if id := c.Query("session"); id != "" {
+		session, err := loadSession(ctx, h.firestore, id)
+		if err != nil {
+			apierror.Abort(c, err)
+			return nil, nil, false
+		}
+		sessions = []models.Session{session}
+	} else {
+		err := h.firestore.All(ctx, "sessions", func(doc *firestore.DocumentSnapshot) error {
+			return appendSession(&sessions, doc)
+		})
+		if err != nil {
+			apierror.Abort(c, err)
+			return nil, nil, false
+		}
+	}
+	return h.withFeedback(c, sessions)
+}
+
+// speakerFeedback loads the sessions of the speaker the link was issued for
+// and their feedback.
+func (h *FeedbackHandler) speakerFeedback(c *gin.Context) ([]models.Session, map[string][]models.Feedback, bool) {
+	speakerID, ok := h.authenticate(c, h.speakerLinks)
+	if !ok {
+		return nil, nil, false
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.firestore.Get(ctx, "speakers", speakerID); err != nil {
+		if apierror.From(err).Code == apierror.CodeNotFound {
+			// The speaker was deleted after the link was issued
+			err = apierror.NotFound("Speaker not found")
+		}
+		apierror.Abort(c, err)
+		return nil, nil, false
+	}
+
+	var sessions []models.Session
+	query := h.firestore.GetCollection("sessions").Where("speakerIds", "array-contains", speakerID)
+	err := h.firestore.Documents(ctx, "sessions", query, func(doc *firestore.DocumentSnapshot) error {
+		return appendSession(&sessions, doc)
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return nil, nil, false
+	}
+	return h.withFeedback(c, sessions)
+}
+
+// withFeedback sorts sessions and loads their feedback, grouped by session
+// ID and oldest first.
+func (h *FeedbackHandler) withFeedback(c *gin.Context, sessions []models.Session) ([]models.Session, map[string][]models.Feedback, bool) {
+	models.SortSessions(sessions, false)
+	feedback, err := h.loadFeedback(c.Request.Context(), sessions)
+	if err != nil {
+		apierror.Abort(c, err)
+		return nil, nil, false
+	}
+	return sessions, feedback, true
+}
+
+// loadFeedback reads the feedback on sessions, querying them in batches of
+// the most values Firestore allows in an "in" filter.
+func (h *FeedbackHandler) loadFeedback(ctx context.Context, sessions []models.Session) (map[string][]models.Feedback, error) {
+	const batch = 30
+
+	grouped := make(map[string][]models.Feedback, len(sessions))
+	for start := 0; start < len(sessions); start += batch {
+		var ids []string
+		for _, session := range sessions[start:min(start+batch, len(sessions))] {
+			ids = append(ids, session.ID)
+		}
+		query := h.firestore.GetCollection("feedback").Where("sessionId", "in", ids)
+		err := h.firestore.Documents(ctx, "feedback", query, func(doc *firestore.DocumentSnapshot) error {
+			var f models.Feedback
+			if err := doc.DataTo(&f); err != nil {
+				return err
+			}
+			f.ID = doc.Ref.ID
+			grouped[f.SessionID] = append(grouped[f.SessionID], f)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, list := range grouped {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].SubmittedAt.Before(list[j].SubmittedAt) })
+	}
+	return grouped, nil
+}
+
+func (h *FeedbackHandler) setEditableUntil(f *models.Feedback) {
+	until := f.SubmittedAt.Add(h.editWindow)
+	f.EditableUntil = &until
+}
+
+// enabled rejects the request when links are not configured.
+func (h *FeedbackHandler) enabled(c *gin.Context) bool {
+	if h.links == nil {
+		apierror.Abort(c, apierror.NotFound("Self-service is not enabled"))
+		return false
+	}
+	return true
+}
+
+// authenticate verifies a link token issued by links and returns the ID it
+// was issued for.
+func (h *FeedbackHandler) authenticate(c *gin.Context, links *services.MagicLinks) (string, bool) {
+	if !h.enabled(c) {
+		return "", false
+	}
+	return verifyLink(c, links)
+}
+
+func appendSession(sessions *[]models.Session, doc *firestore.DocumentSnapshot) error {
+	var session models.Session
+	if err := doc.DataTo(&session); err != nil {
+		return err
+	}
+	session.ID = doc.Ref.ID
+	*sessions = append(*sessions, session)
+	return nil
+}
+
+func summarize(sessions []models.Session, feedback map[string][]models.Feedback) []models.FeedbackSummary {
+	summaries := make([]models.FeedbackSummary, len(sessions))
+	for i, session := range sessions {
+		summaries[i] = models.NewFeedbackSummary(session, feedback[session.ID])
+	}
+	return summaries
+}
+
+// writeFeedbackCSV sends feedback as CSV, one row per rating, without the
+// attendee.
+func writeFeedbackCSV(c *gin.Context, sessions []models.Session, feedback map[string][]models.Feedback) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+feedbackExportFilename+`"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"session_id", "session_title", "rating", "comment", "submitted_at", "updated_at"})
+	for _, session := range sessions {
+		for _, f := range feedback[session.ID] {
+			updatedAt := ""
+			if f.UpdatedAt != nil {
+				updatedAt = f.UpdatedAt.UTC().Format(time.RFC3339)
+			}
+			_ = w.Write([]string{
+				csvCell(session.ID),
+				csvCell(session.Title),
+				strconv.Itoa(f.Rating),
+				csvCell(f.Comment),
+				f.SubmittedAt.UTC().Format(time.RFC3339),
+				updatedAt,
+			})
+		}
+	}
+	w.Flush()
+}
+
+// csvCell keeps free text from being run as a formula when the export is
+// opened in a spreadsheet.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/config"
+	"appdirect-ai-workshop/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func feedbackRouter(links *services.MagicLinks) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewFeedbackHandler(nil, links, config.FeedbackConfig{EditWindow: time.Hour, SpeakerLinkTTL: time.Hour})
+
+	router := gin.New()
+	router.PUT("/api/attendees/me/feedback/:sessionId", handler.SubmitFeedback)
+	router.GET("/api/attendees/me/feedback", handler.GetMyFeedback)
+	router.GET("/api/speakers/me/feedback", handler.GetSpeakerFeedback)
+	return router
+}
+
+func TestFeedbackHandler_Disabled(t *testing.T) {
+	router := feedbackRouter(nil)
+
+	for _, path := range []string{"/api/attendees/me/feedback", "/api/speakers/me/feedback"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+func TestFeedbackHandler_Authentication(t *testing.T) {
+	links := services.NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com")
+	router := feedbackRouter(links)
+
+	attendeeToken, _ := links.Issue("attendee-1")
+	speakerToken, _ := links.SpeakerLinks(time.Hour).Issue("speaker-1")
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+	}{
+		{"no token", "/api/attendees/me/feedback", ""},
+		{"speaker token as attendee", "/api/attendees/me/feedback", speakerToken},
+		{"attendee token as speaker", "/api/speakers/me/feedback", attendeeToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+}
+
+func TestFeedbackHandler_SubmitValidation(t *testing.T) {
+	links := services.NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com")
+	router := feedbackRouter(links)
+	token, _ := links.Issue("attendee-1")
+
+	for _, body := range []string{`{}`, `{"rating":0}`, `{"rating":6}`, `{"rating":3,"comment":"` + string(bytes.Repeat([]byte("a"), 2001)) + `"}`} {
+		req := httptest.NewRequest(http.MethodPut, "/api/attendees/me/feedback/keynote", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	assert.Equal(t, "Great talk", csvCell("Great talk"))
+	assert.Equal(t, "", csvCell(""))
+	for _, s := range []string{"=1+1", "+1", "-1", "@SUM(A1)", "\tx"} {
+		assert.Equal(t, "'"+s, csvCell(s))
+	}
+}
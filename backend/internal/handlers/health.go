@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"appdirect-ai-workshop/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthCheck is a named dependency check run by the readiness probe.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	checks   []HealthCheck
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewHealthHandler creates a HealthHandler running checks on readiness
+// probes, each bounded by timeout.
+func NewHealthHandler(timeout time.Duration, checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{checks: checks, timeout: timeout}
+}
+
+// CheckResult is one check's outcome. The probe is public, so why a check
+// failed is only logged.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+type HealthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Liveness reports that the process is up. It deliberately checks nothing
+// else so a dependency outage does not get the instance restarted.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Drain makes the readiness probe fail from now on, so no new traffic is
+// routed here while the server shuts down.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Readiness runs every check concurrently and responds 503 if any fails or
+// the server is draining.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: "draining"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	response := HealthResponse{Status: "ok", Checks: make(map[string]CheckResult, len(h.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check.Check(ctx)
+			result := CheckResult{
+				Status:    "ok",
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = "fail"
+				logging.FromContext(ctx).Warn("Readiness check failed", "check", check.Name, "error", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Checks[check.Name] = result
+			if err != nil {
+				response.Status = "fail"
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	if response.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
+}
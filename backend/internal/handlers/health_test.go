@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ok := HealthCheck{Name: "config", Check: func(ctx context.Context) error { return nil }}
+	failing := HealthCheck{Name: "firestore", Check: func(ctx context.Context) error { return errors.New("dial tcp 10.0.0.7:443: unreachable") }}
+	slow := HealthCheck{Name: "firestore", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	tests := []struct {
+		name           string
+		path           string
+		checks         []HealthCheck
+		expectedStatus int
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "liveness ignores checks",
+			path:           "/healthz",
+			checks:         []HealthCheck{failing},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "ready",
+			path:           "/readyz",
+			checks:         []HealthCheck{ok},
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"config": "ok"},
+		},
+		{
+			name:           "dependency down",
+			path:           "/readyz",
+			checks:         []HealthCheck{ok, failing},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"config": "ok", "firestore": "fail"},
+		},
+		{
+			name:           "dependency timeout",
+			path:           "/readyz",
+			checks:         []HealthCheck{slow},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"firestore": "fail"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(50*time.Millisecond, tt.checks...)
+			router := gin.New()
+			router.GET("/healthz", handler.Liveness)
+			router.GET("/readyz", handler.Readiness)
+
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.NotContains(t, w.Body.String(), "10.0.0.7", "dependency errors are not exposed")
+
+			var response HealthResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Len(t, response.Checks, len(tt.expectedChecks))
+			for name, status := range tt.expectedChecks {
+				assert.Equal(t, status, response.Checks[name].Status, name)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Draining(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewHealthHandler(50*time.Millisecond, HealthCheck{Name: "firestore", Check: func(ctx context.Context) error { return nil }})
+	router := gin.New()
+	router.GET("/healthz", handler.Liveness)
+	router.GET("/readyz", handler.Readiness)
+
+	handler.Drain()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"draining"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "a draining server is still alive")
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OutboxHandler lets admins inspect and redrive domain events that
+// subscribers failed to handle.
+type OutboxHandler struct {
+	outbox *services.Outbox
+}
+
+func NewOutboxHandler(outbox *services.Outbox) *OutboxHandler {
+	return &OutboxHandler{outbox: outbox}
+}
+
+// GetDeadLetters lists the most recent dead letters.
+func (h *OutboxHandler) GetDeadLetters(c *gin.Context) {
+	letters, err := h.outbox.DeadLetters(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, letters)
+}
+
+// RedriveDeadLetter puts a dead letter back in the outbox for the subscriber
+// that failed it.
+func (h *OutboxHandler) RedriveDeadLetter(c *gin.Context) {
+	event, err := h.outbox.Redrive(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, event)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/logging"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFilename is suggested to browsers downloading a data export.
+const exportFilename = "workshop-data-export.json"
+
+// PrivacyHandler is the admin tooling for data export and erasure requests.
+type PrivacyHandler struct {
+	privacy *services.PrivacyService
+}
+
+func NewPrivacyHandler(privacy *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacy: privacy}
+}
+
+type PrivacyExportRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+// PrivacyErasureRequest names the subject either directly or through a
+// pending request an attendee made.
+type PrivacyErasureRequest struct {
+	Email     string `json:"email" binding:"omitempty,email,max=254"`
+	RequestID string `json:"requestId"`
+}
+
+// GetRequests lists the compliance records of every export and erasure.
+func (h *PrivacyHandler) GetRequests(c *gin.Context) {
+	requests, err := h.privacy.Requests(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, requests)
+}
+
+// Export returns everything held about an email address as a JSON archive.
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	var req PrivacyExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+	writeExport(c, h.privacy, models.PrivacySourceAdmin, req.Email)
+}
+
+// Erase erases everything held about an email address, completing the
+// attendee's pending request when one is given.
+func (h *PrivacyHandler) Erase(c *gin.Context) {
+	var req PrivacyErasureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+	if (req.Email == "") == (req.RequestID == "") {
+		apierror.Abort(c, apierror.BadRequest("Provide either an email or a requestId"))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var pending *models.PrivacyRequest
+	email := req.Email
+	if req.RequestID != "" {
+		record, err := h.privacy.Get(ctx, req.RequestID)
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		if record.Type != models.PrivacyErasure || record.Status != models.PrivacyPending {
+			apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "Only pending erasure requests can be completed"))
+			return
+		}
+		pending = &record
+		email = record.Email
+	}
+
+	records, err := h.privacy.Erase(ctx, email)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	var record models.PrivacyRequest
+	if pending != nil {
+		record, err = h.privacy.Complete(ctx, *pending, records)
+	} else {
+		record, err = h.privacy.Record(ctx, models.PrivacyErasure, models.PrivacyCompleted, models.PrivacySourceAdmin, email, records)
+	}
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	logging.FromContext(ctx).Info("Personal data erased", "privacyRequestId", record.ID, "records", records)
+	c.JSON(http.StatusOK, record)
+}
+
+// writeExport sends the export archive for email and records the request.
+func writeExport(c *gin.Context, privacy *services.PrivacyService, source, email string) {
+	ctx := c.Request.Context()
+
+	export, records, err := privacy.Export(ctx, email)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	record, err := privacy.Record(ctx, models.PrivacyExport, models.PrivacyCompleted, source, email, records)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	logging.FromContext(ctx).Info("Personal data exported", "privacyRequestId", record.ID, "source", source)
+	c.Header("Content-Disposition", `attachment; filename="`+exportFilename+`"`)
+	c.JSON(http.StatusOK, export)
+}
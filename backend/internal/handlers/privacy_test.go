@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivacyHandler_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewPrivacyHandler(nil)
+
+	router := gin.New()
+	router.POST("/api/admin/privacy/export", handler.Export)
+	router.POST("/api/admin/privacy/erasure", handler.Erase)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"export without email", "/api/admin/privacy/export", `{}`},
+		{"export with invalid email", "/api/admin/privacy/export", `{"email": "nope"}`},
+		{"erasure without subject", "/api/admin/privacy/erasure", `{}`},
+		{"erasure with email and request", "/api/admin/privacy/erasure", `{"email": "jane@example.com", "requestId": "abc"}`},
+		{"erasure with invalid email", "/api/admin/privacy/erasure", `{"email": "nope"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/config"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// AttendeeIDKey is where QAHandler.Authenticate stores the attendee's ID in
+// the request context, for rate limits per attendee.
+const AttendeeIDKey = "attendeeId"
+
+// QAHandler runs the live Q&A of each session. Attendees ask and upvote
+// questions with their self-service link; admins moderate them. Changes
+// reach browsers through the live event stream.
+type QAHandler struct {
+	firestore *services.FirestoreService
+	links     *services.MagicLinks
+	cfg       config.QAConfig
+	now       func() time.Time
+}
+
+// NewQAHandler creates a QAHandler. links may be nil, which leaves the feed
+// readable but disables asking and voting.
+func NewQAHandler(firestore *services.FirestoreService, links *services.MagicLinks, cfg config.QAConfig) *QAHandler {
+	return &QAHandler{firestore: firestore, links: links, cfg: cfg, now: time.Now}
+}
+
+// Authenticate verifies the attendee's link token and stores their ID under
+// AttendeeIDKey. It runs before the per-attendee rate limits.
+func (h *QAHandler) Authenticate(c *gin.Context) {
+	if h.links == nil {
+		apierror.Abort(c, apierror.NotFound("Self-service is not enabled"))
+		return
+	}
+	id, ok := verifyLink(c, h.links)
+	if !ok {
+		return
+	}
+	c.Set(AttendeeIDKey, id)
+}
+
+// GetQuestions returns the session's approved questions in feed order. With
+// an attendee's link token, the questions they upvoted are marked.
+func (h *QAHandler) GetQuestions(c *gin.Context) {
+	attendeeID := ""
+	if h.links != nil && c.GetHeader("Authorization") != "" {
+		id, ok := verifyLink(c, h.links)
+		if !ok {
+			return
+		}
+		attendeeID = id
+	}
+
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+	if _, err := loadSession(ctx, h.firestore, sessionID); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	query := h.firestore.GetCollection("liveQuestions").
+		Where("sessionId", "==", sessionID).
+		Where("status", "==", models.QuestionApproved)
+	questions, err := h.load(ctx, query)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	feed := make([]models.PublicQuestion, len(questions))
+	for i, q := range questions {
+		feed[i] = models.NewPublicQuestion(q)
+		feed[i].Voted = attendeeID != "" && q.VotedBy(attendeeID)
+	}
+	c.JSON(http.StatusOK, feed)
+}
+
+// AskQuestion adds a question to the session. It appears in the feed at once
+// or, when questions are premoderated, once a moderator approves it.
+func (h *QAHandler) AskQuestion(c *gin.Context) {
+	attendeeID := c.GetString(AttendeeIDKey)
+
+	var req models.AskQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		apierror.Abort(c, fieldError("text", "required", "is required"))
+		return
+	}
+	if utf8.RuneCountInString(text) > h.cfg.MaxLength {
+		apierror.Abort(c, fieldError("text", "max", fmt.Sprintf("must be at most %d characters", h.cfg.MaxLength)))
+		return
+	}
+
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+	if _, err := loadSession(ctx, h.firestore, sessionID); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	attendee, err := h.participant(ctx, sessionID, attendeeID)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	question := models.LiveQuestion{
+		SessionID:  sessionID,
+		AttendeeID: attendeeID,
+		AuthorName: models.NewPublicAttendee(attendee).FirstName,
+		Anonymous:  req.Anonymous,
+		Text:       text,
+		Status:     models.QuestionApproved,
+		CreatedAt:  h.now(),
+		VoterIDs:   []string{},
+	}
+	if h.cfg.Premoderate {
+		question.Status = models.QuestionPending
+	}
+
+	ref, err := h.firestore.Add(ctx, "liveQuestions", question)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	question.ID = ref.ID
+
+	c.JSON(http.StatusCreated, question)
+}
+
+// Vote upvotes an approved question. Voting twice counts once.
+func (h *QAHandler) Vote(c *gin.Context) {
+	h.vote(c, true)
+}
+
+// Unvote takes the attendee's upvote back.
+func (h *QAHandler) Unvote(c *gin.Context) {
+	h.vote(c, false)
+}
+
+func (h *QAHandler) vote(c *gin.Context, up bool) {
+	attendeeID := c.GetString(AttendeeIDKey)
+	ctx := c.Request.Context()
+
+	if _, err := h.participant(ctx, c.Param("id"), attendeeID); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	question, err := h.loadQuestion(ctx, c.Param("id"), c.Param("questionId"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if !question.Visible() {
+		apierror.Abort(c, apierror.NotFound("Question not found"))
+		return
+	}
+
+	var change any = firestore.ArrayRemove(attendeeID)
+	if up {
+		change = firestore.ArrayUnion(attendeeID)
+	}
+	if err := h.firestore.Update(ctx, "liveQuestions", question.ID, []firestore.Update{{Path: "voterIds", Value: change}}); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	voters := []string{}
+	for _, id := range question.VoterIDs {
+		if id != attendeeID {
+			voters = append(voters, id)
+		}
+	}
+	if up {
+		voters = append(voters, attendeeID)
+	}
+	question.VoterIDs = voters
+
+	public := models.NewPublicQuestion(question)
+	public.Voted = up
+	c.JSON(http.StatusOK, public)
+}
+
+// GetModerationQueue returns every question asked in the session, with who
+// asked it, in feed order. Admin only.
+func (h *QAHandler) GetModerationQueue(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("id")
+	if _, err := loadSession(ctx, h.firestore, sessionID); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	query := h.firestore.GetCollection("liveQuestions").Where("sessionId", "==", sessionID)
+	if status := c.Query("status"); status != "" {
+		switch status {
+		case models.QuestionPending, models.QuestionApproved, models.QuestionHidden:
+		default:
+			apierror.Abort(c, fieldError("status", "oneof", "must be one of: pending approved hidden"))
+			return
+		}
+		query = query.Where("status", "==", status)
+	}
+
+	questions, err := h.load(ctx, query)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, questions)
+}
+
+// ModerateQuestion approves or hides a question, pins or unpins it, or marks
+// it answered. Admin only.
+func (h *QAHandler) ModerateQuestion(c *gin.Context) {
+	var req models.ModerateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+	if req.Status == nil && req.Pinned == nil && req.Answered == nil {
+		apierror.Abort(c, apierror.BadRequest("No fields to update"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	question, err := h.loadQuestion(ctx, c.Param("id"), c.Param("questionId"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	updates := []firestore.Update{}
+	if req.Status != nil {
+		question.Status = *req.Status
+		updates = append(updates, firestore.Update{Path: "status", Value: question.Status})
+	}
+	if req.Pinned != nil {
+		question.Pinned = *req.Pinned
+		updates = append(updates, firestore.Update{Path: "pinned", Value: question.Pinned})
+	}
+	if req.Answered != nil && *req.Answered != question.Answered() {
+		if *req.Answered {
+			now := h.now()
+			question.AnsweredAt = &now
+			updates = append(updates, firestore.Update{Path: "answeredAt", Value: now})
+		} else {
+			question.AnsweredAt = nil
+			updates = append(updates, firestore.Update{Path: "answeredAt", Value: firestore.Delete})
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := h.firestore.Update(ctx, "liveQuestions", question.ID, updates); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, question)
+}
+
+// participant loads the attendee and checks they may take part in the
+// session's Q&A: like session feedback, only attendees checked in to the
+// session may.
+func (h *QAHandler) participant(ctx context.Context, sessionID, attendeeID string) (models.Attendee, error) {
+	attendee, err := loadAttendee(ctx, h.firestore, attendeeID)
+	if err != nil {
+		return attendee, err
+	}
+	if attendee.Cancelled() {
+		return attendee, apierror.New(http.StatusConflict, apierror.CodeConflict, "This registration has been cancelled")
+	}
+	present, err := attended(ctx, h.firestore, sessionID, attendeeID)
+	if err != nil {
+		return attendee, err
+	}
+	if !present {
+		return attendee, apierror.New(http.StatusConflict, apierror.CodeConflict, "Only attendees checked in to the session can ask and vote")
+	}
+	return attendee, nil
+}
+
+// load runs query and returns the questions in feed order.
+func (h *QAHandler) load(ctx context.Context, query firestore.Query) ([]models.LiveQuestion, error) {
+	questions := []models.LiveQuestion{}
+	err := h.firestore.Documents(ctx, "liveQuestions", query, func(doc *firestore.DocumentSnapshot) error {
+		question, err := decodeQuestion(doc)
+		if err != nil {
+			return err
+		}
+		questions = append(questions, question)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	models.SortQuestions(questions)
+	return questions, nil
+}
+
+// loadQuestion loads a question, which must belong to sessionID.
+func (h *QAHandler) loadQuestion(ctx context.Context, sessionID, id string) (models.LiveQuestion, error) {
+	doc, err := h.firestore.Get(ctx, "liveQuestions", id)
+	if err != nil {
+		if apierror.From(err).Code == apierror.CodeNotFound {
+			return models.LiveQuestion{}, apierror.NotFound("Question not found")
+		}
+		return models.LiveQuestion{}, err
+	}
+	question, err := decodeQuestion(doc)
+	if err != nil {
+		return question, err
+	}
+	if question.SessionID != sessionID {
+		return question, apierror.NotFound("Question not found")
+	}
+	return question, nil
+}
+
+func decodeQuestion(doc *firestore.DocumentSnapshot) (models.LiveQuestion, error) {
+	var question models.LiveQuestion
+	if err := doc.DataTo(&question); err != nil {
+		return question, err
+	}
+	question.ID = doc.Ref.ID
+	question.Votes = len(question.VoterIDs)
+	return question, nil
+}
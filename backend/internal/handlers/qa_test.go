@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/config"
+	"appdirect-ai-workshop/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func qaRouter(links *services.MagicLinks) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewQAHandler(nil, links, config.QAConfig{MaxLength: 10})
+
+	router := gin.New()
+	router.POST("/api/sessions/:id/questions", handler.Authenticate, handler.AskQuestion)
+	router.PUT("/api/sessions/:id/questions/:questionId/vote", handler.Authenticate, handler.Vote)
+	router.PUT("/api/admin/sessions/:id/questions/:questionId", handler.ModerateQuestion)
+	return router
+}
+
+func TestQAHandler_Authentication(t *testing.T) {
+	links := services.NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com")
+	speakerToken, _ := links.SpeakerLinks(time.Hour).Issue("speaker-1")
+
+	tests := []struct {
+		name   string
+		links  *services.MagicLinks
+		header string
+		want   int
+	}{
+		{"self-service disabled", nil, "", http.StatusNotFound},
+		{"no token", links, "", http.StatusUnauthorized},
+		{"speaker token", links, "Bearer " + speakerToken, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := qaRouter(tt.links)
+			for _, req := range []*http.Request{
+				httptest.NewRequest(http.MethodPost, "/api/sessions/keynote/questions", bytes.NewBufferString(`{"text":"Why?"}`)),
+				httptest.NewRequest(http.MethodPut, "/api/sessions/keynote/questions/q1/vote", nil),
+			} {
+				if tt.header != "" {
+					req.Header.Set("Authorization", tt.header)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				assert.Equal(t, tt.want, w.Code, req.URL.Path)
+			}
+		})
+	}
+}
+
+func TestQAHandler_AskValidation(t *testing.T) {
+	links := services.NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com")
+	router := qaRouter(links)
+	token, _ := links.Issue("attendee-1")
+
+	for _, body := range []string{`{}`, `{"text":"   "}`, `{"text":"` + strings.Repeat("é", 11) + `"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/sessions/keynote/questions", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestQAHandler_ModerateValidation(t *testing.T) {
+	router := qaRouter(nil)
+
+	for _, body := range []string{`{}`, `{"status":"deleted"}`} {
+		req := httptest.NewRequest(http.MethodPut, "/api/admin/sessions/keynote/questions/q1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
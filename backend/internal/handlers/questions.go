@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// QuestionHandler manages the event's custom registration questions.
+type QuestionHandler struct {
+	firestore *services.FirestoreService
+}
+
+func NewQuestionHandler(firestore *services.FirestoreService) *QuestionHandler {
+	return &QuestionHandler{firestore: firestore}
+}
+
+// GetForm serves the registration form schema: the custom questions in
+// display order, after the built-in name, email and designation fields.
+func (h *QuestionHandler) GetForm(c *gin.Context) {
+	questions, err := loadQuestions(c.Request.Context(), h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"questions": questions})
+}
+
+func (h *QuestionHandler) GetQuestions(c *gin.Context) {
+	questions, err := loadQuestions(c.Request.Context(), h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, questions)
+}
+
+func (h *QuestionHandler) CreateQuestion(c *gin.Context) {
+	var req models.CreateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	question := models.Question{
+		Key:           req.Key,
+		Label:         strings.TrimSpace(req.Label),
+		HelpText:      strings.TrimSpace(req.HelpText),
+		Type:          req.Type,
+		Required:      req.Required,
+		Options:       req.Options,
+		Order:         req.Order,
+		MinLength:     req.MinLength,
+		MaxLength:     req.MaxLength,
+		Pattern:       req.Pattern,
+		MinSelections: req.MinSelections,
+		MaxSelections: req.MaxSelections,
+	}
+	if err := question.Check(); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, http.StatusBadRequest, apierror.CodeValidationFailed, err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Keys name the stored answers, so they must be unique
+	taken := false
+	query := h.firestore.GetCollection("questions").Where("key", "==", question.Key).Limit(1)
+	err := h.firestore.Documents(ctx, "questions", query, func(*firestore.DocumentSnapshot) error {
+		taken = true
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if taken {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "A question with this key already exists"))
+		return
+	}
+
+	docRef, err := h.firestore.Add(ctx, "questions", question)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	question.ID = docRef.ID
+	c.JSON(http.StatusCreated, question)
+}
+
+func (h *QuestionHandler) UpdateQuestion(c *gin.Context) {
+	id := c.Param("id")
+	var req models.UpdateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	doc, err := h.firestore.Get(ctx, "questions", id)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	var question models.Question
+	if err := doc.DataTo(&question); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	question.ID = doc.Ref.ID
+
+	question.Label = strings.TrimSpace(req.Label)
+	question.HelpText = strings.TrimSpace(req.HelpText)
+	question.Required = req.Required
+	question.Options = req.Options
+	question.Order = req.Order
+	question.MinLength = req.MinLength
+	question.MaxLength = req.MaxLength
+	question.Pattern = req.Pattern
+	question.MinSelections = req.MinSelections
+	question.MaxSelections = req.MaxSelections
+	if err := question.Check(); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, http.StatusBadRequest, apierror.CodeValidationFailed, err.Error()))
+		return
+	}
+
+	updates := []firestore.Update{
+		{Path: "label", Value: question.Label},
+		{Path: "helpText", Value: question.HelpText},
+		{Path: "required", Value: question.Required},
+		{Path: "options", Value: question.Options},
+		{Path: "order", Value: question.Order},
+		{Path: "minLength", Value: question.MinLength},
+		{Path: "maxLength", Value: question.MaxLength},
+		{Path: "pattern", Value: question.Pattern},
+		{Path: "minSelections", Value: question.MinSelections},
+		{Path: "maxSelections", Value: question.MaxSelections},
+	}
+	if err := h.firestore.Update(ctx, "questions", id, updates); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+// DeleteQuestion removes a question from the form. Answers already given
+// stay on the attendee records and in their exports.
+func (h *QuestionHandler) DeleteQuestion(c *gin.Context) {
+	id := c.Param("id")
+	ctx := c.Request.Context()
+
+	if err := h.firestore.Delete(ctx, "questions", id); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
+
+// loadQuestions returns the event's questions in display order.
+func loadQuestions(ctx context.Context, fs *services.FirestoreService) ([]models.Question, error) {
+	questions := []models.Question{}
+	err := fs.All(ctx, "questions", func(doc *firestore.DocumentSnapshot) error {
+		var question models.Question
+		if err := doc.DataTo(&question); err != nil {
+			return err
+		}
+		question.ID = doc.Ref.ID
+		questions = append(questions, question)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(questions, func(i, j int) bool {
+		if questions[i].Order != questions[j].Order {
+			return questions[i].Order < questions[j].Order
+		}
+		return questions[i].Key < questions[j].Key
+	})
+	return questions, nil
+}
+
+// validateAnswers checks answers against the questions and returns them as
+// stored, leaving out blank optional answers. Every rejected answer is
+// reported as a detail on the field "answers.<key>".
+func validateAnswers(questions []models.Question, answers map[string]any) (map[string]any, error) {
+	var details []apierror.FieldError
+	known := make(map[string]bool, len(questions))
+	cleaned := make(map[string]any)
+
+	for _, q := range questions {
+		known[q.Key] = true
+		value, err := q.Validate(answers[q.Key])
+		var answerErr *models.AnswerError
+		if errors.As(err, &answerErr) {
+			details = append(details, apierror.FieldError{Field: "answers." + q.Key, Code: answerErr.Code, Message: answerErr.Message})
+			continue
+		}
+		if value != nil {
+			cleaned[q.Key] = value
+		}
+	}
+
+	unknown := make([]string, 0)
+	for key := range answers {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		details = append(details, apierror.FieldError{Field: "answers." + key, Code: "unknown", Message: "is not a question on this form"})
+	}
+
+	if len(details) > 0 {
+		apiErr := apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Validation failed")
+		apiErr.Details = details
+		return nil, apiErr
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAnswers(t *testing.T) {
+	questions := []models.Question{
+		{Key: "company", Type: models.QuestionText, Required: true},
+		{Key: "tshirt", Type: models.QuestionSingleChoice, Options: []string{"S", "M"}},
+	}
+
+	answers, err := validateAnswers(questions, map[string]any{"company": " Acme ", "tshirt": ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"company": "Acme"}, answers, "blank optional answers are left out")
+
+	_, err = validateAnswers(questions, map[string]any{"tshirt": "XL", "shoe": "42"})
+	var apiErr *apierror.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, apierror.CodeValidationFailed, apiErr.Code)
+	assert.Equal(t, []apierror.FieldError{
+		{Field: "answers.company", Code: "required", Message: "is required"},
+		{Field: "answers.tshirt", Code: "oneof", Message: "must be one of the options"},
+		{Field: "answers.shoe", Code: "unknown", Message: "is not a question on this form"},
+	}, apiErr.Details)
+
+	answers, err = validateAnswers(nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, answers, "events without questions accept registrations without answers")
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/logging"
+	"appdirect-ai-workshop/internal/metrics"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// magicLinkSent is returned whether or not the email is registered so the
+// endpoint cannot be used to find out who registered.
+const magicLinkSent = "If that email is registered, a link to manage the registration is on its way"
+
+// SelfServiceHandler lets attendees view, update or cancel their own
+// registration through a magic link sent to their email address.
+type SelfServiceHandler struct {
+	firestore *services.FirestoreService
+	links     *services.MagicLinks
+	mailer    services.Mailer
+	privacy   *services.PrivacyService
+	outbox    *services.Outbox
+	metrics   *metrics.Metrics
+}
+
+// NewSelfServiceHandler creates a SelfServiceHandler. links may be nil to
+// disable self-service, and metrics may be nil to disable instrumentation.
+func NewSelfServiceHandler(firestore *services.FirestoreService, links *services.MagicLinks, mailer services.Mailer, privacy *services.PrivacyService, outbox *services.Outbox, metrics *metrics.Metrics) *SelfServiceHandler {
+	return &SelfServiceHandler{firestore: firestore, links: links, mailer: mailer, privacy: privacy, outbox: outbox, metrics: metrics}
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+// UpdateRegistrationRequest replaces the attendee-editable fields. Name and
+// designation go through the same validation as registration.
+type UpdateRegistrationRequest struct {
+	Name         string `json:"name" binding:"required,max=200"`
+	Designation  string `json:"designation" binding:"required,max=100"`
+	ListPublicly *bool  `json:"listPublicly"`
+
+	// Answers, when present, replace all answers to the custom questions
+	Answers map[string]any `json:"answers"`
+}
+
+// RequestMagicLink emails a self-service link for every active registration
+// with the given address, in any case. The response is the same either way,
+// and the mailer should queue messages (see services.MailQueue) so it takes
+// the same time too.
+func (h *SelfServiceHandler) RequestMagicLink(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	var req MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	email := strings.TrimSpace(req.Email)
+
+	query := h.firestore.GetCollection("attendees").Where("emailNormalized", "==", services.NormalizeEmail(email))
+	var ids []string
+	err := h.firestore.Documents(ctx, "attendees", query, func(doc *firestore.DocumentSnapshot) error {
+		if !isCancelled(doc) {
+			ids = append(ids, doc.Ref.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	for _, id := range ids {
+		token, expiresAt := h.links.Issue(id)
+		msg := services.Message{
+			To:      email,
+			Subject: "Manage your AI Workshop registration",
+			Body: fmt.Sprintf("Use this link to view, update or cancel your registration:\n\n%s\n\nThe link expires on %s. If you did not ask for it, you can ignore this email.\n",
+				h.links.URL(token), expiresAt.UTC().Format("2 Jan 2006 15:04 MST")),
+		}
+		if err := h.mailer.Send(ctx, msg); err != nil {
+			// Failing the request would tell the caller the email exists
+			logging.FromContext(ctx).Error("Failed to queue magic link", "attendeeId", id, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": magicLinkSent})
+}
+
+// GetRegistration returns the registration the link was issued for.
+func (h *SelfServiceHandler) GetRegistration(c *gin.Context) {
+	id, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	attendee, err := h.load(c, id)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attendee)
+}
+
+// UpdateRegistration changes the attendee's name, designation and listing
+// preference. Cancelled registrations cannot be changed.
+func (h *SelfServiceHandler) UpdateRegistration(c *gin.Context) {
+	id, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+	name, designation, err := validateAttendeeDetails(req.Name, req.Designation)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	var answers map[string]any
+	if req.Answers != nil {
+		questions, err := loadQuestions(c.Request.Context(), h.firestore)
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		if answers, err = validateAnswers(questions, req.Answers); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+	}
+
+	attendee, err := h.load(c, id)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if attendee.Cancelled() {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "This registration has been cancelled"))
+		return
+	}
+
+	// A designation from before the current list may be kept as it is
+	if designation != attendee.Designation {
+		taxonomy, err := loadTaxonomy(c.Request.Context(), h.firestore)
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		if designation, err = normalizeDesignation(taxonomy, designation); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+	}
+
+	now := time.Now()
+	updates := []firestore.Update{
+		{Path: "name", Value: name},
+		{Path: "designation", Value: designation},
+		{Path: "updatedAt", Value: now},
+	}
+	attendee.Name = name
+	attendee.Designation = designation
+	attendee.UpdatedAt = &now
+	if req.ListPublicly != nil {
+		updates = append(updates, firestore.Update{Path: "listPublicly", Value: *req.ListPublicly})
+		attendee.ListPublicly = *req.ListPublicly
+	}
+	if answers != nil {
+		updates = append(updates, firestore.Update{Path: "answers", Value: answers})
+		attendee.Answers = answers
+	}
+
+	err = h.outbox.Transact(c.Request.Context(), func(tx *services.Tx) error {
+		if err := tx.Update("attendees", id, updates); err != nil {
+			return err
+		}
+		return tx.Emit(models.EventAttendeeUpdated, id, attendee)
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attendee)
+}
+
+// CancelRegistration cancels the registration, freeing its place. The record
+// is kept with a cancelled status. Cancelling twice is not an error.
+func (h *SelfServiceHandler) CancelRegistration(c *gin.Context) {
+	id, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	attendee, err := h.load(c, id)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if !attendee.Cancelled() {
+		now := time.Now()
+		updates := []firestore.Update{
+			{Path: "status", Value: models.StatusCancelled},
+			{Path: "listPublicly", Value: false},
+			{Path: "updatedAt", Value: now},
+		}
+		attendee.Status = models.StatusCancelled
+		attendee.ListPublicly = false
+		attendee.UpdatedAt = &now
+		err := h.outbox.Transact(c.Request.Context(), func(tx *services.Tx) error {
+			if err := tx.Update("attendees", id, updates); err != nil {
+				return err
+			}
+			return tx.Emit(models.EventAttendeeCancelled, id, attendee)
+		})
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		h.metrics.RegistrationCancelled()
+		logging.FromContext(c.Request.Context()).Info("Registration cancelled", "attendeeId", id)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Registration cancelled"})
+}
+
+// ExportData returns everything held about the attendee's email address as
+// a JSON archive. The magic link proves the requester owns the address.
+func (h *SelfServiceHandler) ExportData(c *gin.Context) {
+	id, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	attendee, err := h.load(c, id)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	writeExport(c, h.privacy, models.PrivacySourceSelfService, attendee.Email)
+}
+
+// RequestErasure files a request to erase everything held about the
+// attendee's email address. Erasure cannot be undone, so an admin carries it
+// out; asking again while a request is pending returns that request.
+func (h *SelfServiceHandler) RequestErasure(c *gin.Context) {
+	id, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	attendee, err := h.load(c, id)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	pending, err := h.privacy.Pending(ctx, models.PrivacyErasure, attendee.Email)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if pending == nil {
+		record, err := h.privacy.Record(ctx, models.PrivacyErasure, models.PrivacyPending, models.PrivacySourceSelfService, attendee.Email, nil)
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		pending = &record
+		logging.FromContext(ctx).Info("Erasure requested", "privacyRequestId", record.ID)
+	}
+
+	c.JSON(http.StatusAccepted, pending)
+}
+
+// enabled rejects the request when self-service is not configured.
+func (h *SelfServiceHandler) enabled(c *gin.Context) bool {
+	if h.links == nil {
+		apierror.Abort(c, apierror.NotFound("Self-service is not enabled"))
+		return false
+	}
+	return true
+}
+
+// authenticate verifies the magic-link token sent as a bearer token and
+// returns the attendee ID it was issued for.
+func (h *SelfServiceHandler) authenticate(c *gin.Context) (string, bool) {
+	if !h.enabled(c) {
+		return "", false
+	}
+	return verifyLink(c, h.links)
+}
+
+func (h *SelfServiceHandler) load(c *gin.Context, id string) (models.Attendee, error) {
+	return loadAttendee(c.Request.Context(), h.firestore, id)
+}
+
+// verifyLink verifies the link token sent as a bearer token and returns the
+// ID it was issued for.
+func verifyLink(c *gin.Context, links *services.MagicLinks) (string, bool) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		apierror.Abort(c, apierror.Unauthorized("A link token is required"))
+		return "", false
+	}
+	id, err := links.Verify(token)
+	if err != nil {
+		apierror.Abort(c, apierror.Unauthorized("This link is invalid or has expired, please request a new one"))
+		return "", false
+	}
+	return id, true
+}
+
+// loadAttendee loads the registration a link was issued for. Erased
+// registrations are not found.
+func loadAttendee(ctx context.Context, fs *services.FirestoreService, id string) (models.Attendee, error) {
+	var attendee models.Attendee
+	doc, err := fs.Get(ctx, "attendees", id)
+	if err != nil {
+		if apierror.From(err).Code == apierror.CodeNotFound {
+			// The registration was deleted after the link was issued
+			return attendee, apierror.NotFound("Registration not found")
+		}
+		return attendee, err
+	}
+	if err := doc.DataTo(&attendee); err != nil {
+		return attendee, err
+	}
+	if attendee.Erased() {
+		return attendee, apierror.NotFound("Registration not found")
+	}
+	attendee.ID = doc.Ref.ID
+	return attendee, nil
+}
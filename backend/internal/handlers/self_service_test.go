@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selfServiceRouter(links *services.MagicLinks) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewSelfServiceHandler(nil, links, services.LogMailer{}, nil, nil, nil)
+
+	router := gin.New()
+	router.POST("/api/attendees/magic-link", handler.RequestMagicLink)
+	router.GET("/api/attendees/me", handler.GetRegistration)
+	router.PUT("/api/attendees/me", handler.UpdateRegistration)
+	router.DELETE("/api/attendees/me", handler.CancelRegistration)
+	return router
+}
+
+func TestSelfServiceHandler_Disabled(t *testing.T) {
+	router := selfServiceRouter(nil)
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/api/attendees/me", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+	}
+}
+
+func TestSelfServiceHandler_Authentication(t *testing.T) {
+	links := services.NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com")
+	router := selfServiceRouter(links)
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"no token", ""},
+		{"not a bearer token", "Basic abc"},
+		{"forged token", "Bearer abc.def"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/attendees/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+}
+
+func TestSelfServiceHandler_UpdateValidation(t *testing.T) {
+	links := services.NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com")
+	router := selfServiceRouter(links)
+	token, _ := links.Issue("attendee-1")
+
+	tests := []struct {
+		name      string
+		body      map[string]any
+		wantField string
+	}{
+		{"missing name", map[string]any{"designation": "Architect"}, "name"},
+		{"blank name", map[string]any{"name": "   ", "designation": "Architect"}, "name"},
+		{"blank designation", map[string]any{"name": "Jane Doe", "designation": " "}, "designation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPut, "/api/attendees/me", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp struct {
+				Code    string `json:"code"`
+				Details []struct {
+					Field string `json:"field"`
+				} `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "validation_failed", resp.Code)
+			require.Len(t, resp.Details, 1)
+			assert.Equal(t, tt.wantField, resp.Details[0].Field)
+		})
+	}
+}
+
+func TestValidateAttendeeDetails(t *testing.T) {
+	name, designation, err := validateAttendeeDetails("  Jane   Doe ", " Tech Lead ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", name)
+	assert.Equal(t, "Tech Lead", designation)
+
+	_, _, err = validateAttendeeDetails(string(bytes.Repeat([]byte("a"), maxNameLength+1)), "Tech Lead")
+	assert.Error(t, err)
+}
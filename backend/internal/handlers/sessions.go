@@ -2,67 +2,202 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
+	"appdirect-ai-workshop/internal/apierror"
 	"appdirect-ai-workshop/internal/models"
 	"appdirect-ai-workshop/internal/services"
 
-	"github.com/gin-gonic/gin"
 	"cloud.google.com/go/firestore"
-	"google.golang.org/api/iterator"
+	"github.com/gin-gonic/gin"
 )
 
 type SessionHandler struct {
 	firestore *services.FirestoreService
+	outbox    *services.Outbox
+	location  *time.Location
 }
 
-func NewSessionHandler(firestore *services.FirestoreService) *SessionHandler {
-	return &SessionHandler{firestore: firestore}
+// NewSessionHandler creates a SessionHandler. Agenda days are dates in
+// location, the event's time zone.
+func NewSessionHandler(firestore *services.FirestoreService, outbox *services.Outbox, location *time.Location) *SessionHandler {
+	return &SessionHandler{firestore: firestore, outbox: outbox, location: location}
 }
 
-func (h *SessionHandler) GetSessions(c *gin.Context) {
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("sessions")
+// sessionQuery holds the validated query parameters of the agenda.
+type sessionQuery struct {
+	filter         models.SessionFilter
+	desc           bool
+	expandSpeakers bool
+}
 
-	var sessions []models.Session
-	iter := collection.Documents(ctx)
-	defer iter.Stop()
+// parseSessionQuery validates the agenda's filter, sort and expand
+// parameters. Filters are applied in memory: the agenda is small and this
+// avoids a composite index per combination.
+func parseSessionQuery(c *gin.Context, loc *time.Location) (sessionQuery, error) {
+	q := sessionQuery{filter: models.SessionFilter{
+		TrackIDs:   c.QueryArray("track"),
+		RoomIDs:    c.QueryArray("room"),
+		Tags:       c.QueryArray("tag"),
+		SpeakerIDs: c.QueryArray("speaker"),
+	}}
 
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
+	var details []apierror.FieldError
+	if v := c.Query("day"); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+			details = append(details, apierror.FieldError{Field: "day", Code: "date", Message: "must be a date such as 2026-03-14"})
+		} else {
+			q.filter.From, q.filter.To = d, d.AddDate(0, 0, 1)
 		}
+	}
+	switch c.DefaultQuery("sort", "startsAt") {
+	case "startsAt":
+	case "-startsAt":
+		q.desc = true
+	default:
+		details = append(details, apierror.FieldError{Field: "sort", Code: "oneof", Message: "must be one of: startsAt -startsAt"})
+	}
+	switch c.Query("expand") {
+	case "":
+	case "speakers":
+		q.expandSpeakers = true
+	default:
+		details = append(details, apierror.FieldError{Field: "expand", Code: "oneof", Message: "must be one of: speakers"})
+	}
+
+	if len(details) > 0 {
+		apiErr := apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Validation failed")
+		apiErr.Details = details
+		return q, apiErr
+	}
+	return q, nil
+}
+
+// GetSessions returns the agenda, filtered by day, track, room, tag and
+// speaker and sorted by start time. With expand=speakers each session
+// carries its speakers' records, read in a single batch.
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	query, err := parseSessionQuery(c, h.location)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	sessions, err := h.load(c.Request.Context(), query)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// GetAgenda returns the agenda as a grid of days, tracks and rooms. It takes
+// the same filters as GetSessions.
+func (h *SessionHandler) GetAgenda(c *gin.Context) {
+	query, err := parseSessionQuery(c, h.location)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	sessions, err := h.load(ctx, query)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	tracks, err := loadTracks(ctx, h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	rooms, err := loadRooms(ctx, h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewAgendaGrid(sessions, tracks, rooms, h.location))
+}
 
+// load returns the sessions matching query, sorted and expanded as asked.
+func (h *SessionHandler) load(ctx context.Context, query sessionQuery) ([]models.Session, error) {
+	sessions := []models.Session{}
+	err := h.firestore.All(ctx, "sessions", func(doc *firestore.DocumentSnapshot) error {
 		var session models.Session
 		if err := doc.DataTo(&session); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+			return err
 		}
 		session.ID = doc.Ref.ID
-		sessions = append(sessions, session)
+		if query.filter.Match(session) {
+			sessions = append(sessions, session)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	models.SortSessions(sessions, query.desc)
 
-	// Ensure we always return an array, not null
-	if sessions == nil {
-		sessions = []models.Session{}
+	if query.expandSpeakers {
+		if err := h.expandSpeakers(ctx, sessions); err != nil {
+			return nil, err
+		}
 	}
-	c.JSON(http.StatusOK, sessions)
+	return sessions, nil
+}
+
+// expandSpeakers fills in each session's speakers, in the order of its
+// speaker IDs. Speakers that no longer exist are left out.
+func (h *SessionHandler) expandSpeakers(ctx context.Context, sessions []models.Session) error {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, session := range sessions {
+		for _, id := range session.SpeakerIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	docs, err := h.firestore.GetAll(ctx, "speakers", ids)
+	if err != nil {
+		return err
+	}
+	speakers := make(map[string]models.Speaker, len(docs))
+	for _, doc := range docs {
+		var speaker models.Speaker
+		if err := doc.DataTo(&speaker); err != nil {
+			return err
+		}
+		speaker.ID = doc.Ref.ID
+		speakers[speaker.ID] = speaker
+	}
+
+	for i := range sessions {
+		list := []models.Speaker{}
+		for _, id := range sessions[i].SpeakerIDs {
+			if speaker, ok := speakers[id]; ok {
+				list = append(list, speaker)
+			}
+		}
+		sessions[i].Speakers = list
+	}
+	return nil
 }
 
 func (h *SessionHandler) CreateSession(c *gin.Context) {
 	var req models.CreateSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.InvalidBody(err))
 		return
 	}
 
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("sessions")
+	ctx := c.Request.Context()
 
 	session := models.Session{
 		Title:       req.Title,
@@ -70,15 +205,33 @@ func (h *SessionHandler) CreateSession(c *gin.Context) {
 		Time:        req.Time,
 		Duration:    req.Duration,
 		SpeakerIDs:  req.SpeakerIDs,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		TrackID:     req.TrackID,
+		RoomID:      req.RoomID,
+		Tags:        req.Tags,
+	}
+	if !session.ValidTimes() {
+		apierror.Abort(c, fieldError("endsAt", "gtfield", "must be after startsAt"))
+		return
 	}
 
-	docRef, _, err := collection.Add(ctx, session)
+	err := h.outbox.Transact(ctx, func(tx *services.Tx) error {
+		if err := checkPlacement(tx, session); err != nil {
+			return err
+		}
+		ref, err := tx.Create("sessions", session)
+		if err != nil {
+			return err
+		}
+		session.ID = ref.ID
+		return tx.Emit(models.EventSessionCreated, session.ID, session)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Abort(c, err)
 		return
 	}
 
-	session.ID = docRef.ID
 	c.JSON(http.StatusCreated, session)
 }
 
@@ -86,13 +239,11 @@ func (h *SessionHandler) UpdateSession(c *gin.Context) {
 	id := c.Param("id")
 	var req models.UpdateSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.InvalidBody(err))
 		return
 	}
 
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("sessions")
-	docRef := collection.Doc(id)
+	ctx := c.Request.Context()
 
 	updates := []firestore.Update{}
 	if req.Title != "" {
@@ -110,47 +261,143 @@ func (h *SessionHandler) UpdateSession(c *gin.Context) {
 	if req.SpeakerIDs != nil {
 		updates = append(updates, firestore.Update{Path: "speakerIds", Value: req.SpeakerIDs})
 	}
-
-	if len(updates) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
-		return
+	if req.StartsAt != nil {
+		updates = append(updates, firestore.Update{Path: "startsAt", Value: *req.StartsAt})
 	}
-
-	_, err := docRef.Update(ctx, updates)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if req.EndsAt != nil {
+		updates = append(updates, firestore.Update{Path: "endsAt", Value: *req.EndsAt})
+	}
+	if req.TrackID != nil {
+		updates = append(updates, firestore.Update{Path: "trackId", Value: *req.TrackID})
+	}
+	if req.RoomID != nil {
+		updates = append(updates, firestore.Update{Path: "roomId", Value: *req.RoomID})
+	}
+	if req.Tags != nil {
+		updates = append(updates, firestore.Update{Path: "tags", Value: req.Tags})
 	}
 
-	// Fetch updated document
-	doc, err := docRef.Get(ctx)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if len(updates) == 0 {
+		apierror.Abort(c, apierror.BadRequest("No fields to update"))
 		return
 	}
 
 	var session models.Session
-	if err := doc.DataTo(&session); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := h.outbox.Transact(ctx, func(tx *services.Tx) error {
+		doc, err := tx.Get("sessions", id)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&session); err != nil {
+			return err
+		}
+		session.ID = id
+		req.Apply(&session)
+		if !session.ValidTimes() {
+			return fieldError("endsAt", "gtfield", "must be after startsAt")
+		}
+		if err := checkPlacement(tx, session); err != nil {
+			return err
+		}
+
+		if err := tx.Update("sessions", id, updates); err != nil {
+			return err
+		}
+		return tx.Emit(models.EventSessionUpdated, id, session)
+	})
+	if err != nil {
+		apierror.Abort(c, err)
 		return
 	}
-	session.ID = doc.Ref.ID
 
 	c.JSON(http.StatusOK, session)
 }
 
 func (h *SessionHandler) DeleteSession(c *gin.Context) {
 	id := c.Param("id")
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("sessions")
-	docRef := collection.Doc(id)
+	ctx := c.Request.Context()
 
-	_, err := docRef.Delete(ctx)
+	err := h.outbox.Transact(ctx, func(tx *services.Tx) error {
+		if err := tx.Delete("sessions", id); err != nil {
+			return err
+		}
+		return tx.Emit(models.EventSessionDeleted, id, gin.H{"id": id})
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Session deleted successfully"})
 }
 
+// checkPlacement validates the session's track and room, and that nothing
+// else is booked in the room while it is on. Every booking of a room writes
+// to the room's document, so concurrent bookings of the same room conflict
+// and the later one is retried with the earlier one in view. It must be
+// called after the transaction's other reads.
+func checkPlacement(tx *services.Tx, session models.Session) error {
+	if session.TrackID != "" {
+		if _, err := tx.Get("tracks", session.TrackID); err != nil {
+			if apierror.From(err).Code == apierror.CodeNotFound {
+				return fieldError("trackId", "exists", "must be an existing track")
+			}
+			return err
+		}
+	}
+	if session.RoomID == "" {
+		return nil
+	}
+
+	if !session.Scheduled() {
+		return fieldError("roomId", "scheduled", "needs startsAt and endsAt")
+	}
+	if _, err := tx.Get("rooms", session.RoomID); err != nil {
+		if apierror.From(err).Code == apierror.CodeNotFound {
+			return fieldError("roomId", "exists", "must be an existing room")
+		}
+		return err
+	}
+	docs, err := tx.Where("sessions", "roomId", "==", session.RoomID)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if doc.Ref.ID == session.ID {
+			continue
+		}
+		var other models.Session
+		if err := doc.DataTo(&other); err != nil {
+			return err
+		}
+		if session.Overlaps(other) {
+			return apierror.New(http.StatusConflict, apierror.CodeConflict, fmt.Sprintf("The room is booked for %q at that time", other.Title))
+		}
+	}
+	return tx.Update("rooms", session.RoomID, []firestore.Update{{Path: "bookedAt", Value: firestore.ServerTimestamp}})
+}
+
+// loadSession loads a session, failing with a not found error if there is
+// no such session.
+func loadSession(ctx context.Context, fs *services.FirestoreService, id string) (models.Session, error) {
+	var session models.Session
+	doc, err := fs.Get(ctx, "sessions", id)
+	if err != nil {
+		if apierror.From(err).Code == apierror.CodeNotFound {
+			return session, apierror.NotFound("Session not found")
+		}
+		return session, err
+	}
+	if err := doc.DataTo(&session); err != nil {
+		return session, err
+	}
+	session.ID = doc.Ref.ID
+	return session, nil
+}
+
+// fieldError is a validation error for a single field.
+func fieldError(field, code, message string) error {
+	apiErr := apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Validation failed")
+	apiErr.Details = []apierror.FieldError{{Field: field, Code: code, Message: message}}
+	return apiErr
+}
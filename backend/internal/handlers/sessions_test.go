@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"appdirect-ai-workshop/internal/models"
 	"appdirect-ai-workshop/internal/services"
@@ -17,11 +18,11 @@ import (
 func TestSessionHandler_GetSessions(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	mockService, err := services.NewFirestoreService()
+	mockService, err := services.NewFirestoreService(testFirestoreConfig)
 	if err != nil {
 		t.Skipf("Skipping test: Firestore not available: %v", err)
 	}
-	handler := NewSessionHandler(mockService)
+	handler := NewSessionHandler(mockService, services.NewOutbox(mockService), time.UTC)
 
 	router := gin.New()
 	router.GET("/api/sessions", handler.GetSessions)
@@ -70,11 +71,11 @@ func TestSessionHandler_CreateSession(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockService, err := services.NewFirestoreService()
+			mockService, err := services.NewFirestoreService(testFirestoreConfig)
 			if err != nil {
 				t.Skipf("Skipping test: Firestore not available: %v", err)
 			}
-			handler := NewSessionHandler(mockService)
+			handler := NewSessionHandler(mockService, services.NewOutbox(mockService), time.UTC)
 
 			router := gin.New()
 			router.POST("/api/admin/sessions", handler.CreateSession)
@@ -91,3 +92,54 @@ func TestSessionHandler_CreateSession(t *testing.T) {
 	}
 }
 
+
+func TestSessionHandler_GetSessions_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewSessionHandler(nil, nil, time.UTC)
+
+	router := gin.New()
+	router.GET("/api/sessions", handler.GetSessions)
+
+	tests := []struct {
+		name  string
+		query string
+		field string
+	}{
+		{"invalid day", "day=14-03-2026", "day"},
+		{"invalid sort", "sort=title", "sort"},
+		{"invalid expand", "expand=attendees", "expand"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/sessions?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"field":"`+tt.field+`"`)
+		})
+	}
+}
+
+func TestSessionHandler_CreateSession_InvalidTimes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewSessionHandler(nil, nil, time.UTC)
+
+	router := gin.New()
+	router.POST("/api/admin/sessions", handler.CreateSession)
+
+	start := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	body, _ := json.Marshal(models.CreateSessionRequest{Title: "Backwards", StartsAt: &start, EndsAt: &end})
+	req, _ := http.NewRequest("POST", "/api/admin/sessions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"endsAt"`)
+}
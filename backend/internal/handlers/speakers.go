@@ -1,50 +1,41 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 
+	"appdirect-ai-workshop/internal/apierror"
 	"appdirect-ai-workshop/internal/models"
 	"appdirect-ai-workshop/internal/services"
 
-	"github.com/gin-gonic/gin"
 	"cloud.google.com/go/firestore"
-	"google.golang.org/api/iterator"
+	"github.com/gin-gonic/gin"
 )
 
 type SpeakerHandler struct {
 	firestore *services.FirestoreService
+	outbox    *services.Outbox
 }
 
-func NewSpeakerHandler(firestore *services.FirestoreService) *SpeakerHandler {
-	return &SpeakerHandler{firestore: firestore}
+func NewSpeakerHandler(firestore *services.FirestoreService, outbox *services.Outbox) *SpeakerHandler {
+	return &SpeakerHandler{firestore: firestore, outbox: outbox}
 }
 
 func (h *SpeakerHandler) GetSpeakers(c *gin.Context) {
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("speakers")
+	ctx := c.Request.Context()
 
 	var speakers []models.Speaker
-	iter := collection.Documents(ctx)
-	defer iter.Stop()
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-
+	err := h.firestore.All(ctx, "speakers", func(doc *firestore.DocumentSnapshot) error {
 		var speaker models.Speaker
 		if err := doc.DataTo(&speaker); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+			return err
 		}
 		speaker.ID = doc.Ref.ID
 		speakers = append(speakers, speaker)
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
 	}
 
 	// Ensure we always return an array, not null
@@ -57,12 +48,11 @@ func (h *SpeakerHandler) GetSpeakers(c *gin.Context) {
 func (h *SpeakerHandler) CreateSpeaker(c *gin.Context) {
 	var req models.CreateSpeakerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.InvalidBody(err))
 		return
 	}
 
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("speakers")
+	ctx := c.Request.Context()
 
 	speaker := models.Speaker{
 		Name:     req.Name,
@@ -71,13 +61,19 @@ func (h *SpeakerHandler) CreateSpeaker(c *gin.Context) {
 		Sessions: req.Sessions,
 	}
 
-	docRef, _, err := collection.Add(ctx, speaker)
+	err := h.outbox.Transact(ctx, func(tx *services.Tx) error {
+		ref, err := tx.Create("speakers", speaker)
+		if err != nil {
+			return err
+		}
+		speaker.ID = ref.ID
+		return tx.Emit(models.EventSpeakerCreated, speaker.ID, speaker)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Abort(c, err)
 		return
 	}
 
-	speaker.ID = docRef.ID
 	c.JSON(http.StatusCreated, speaker)
 }
 
@@ -85,13 +81,11 @@ func (h *SpeakerHandler) UpdateSpeaker(c *gin.Context) {
 	id := c.Param("id")
 	var req models.UpdateSpeakerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.InvalidBody(err))
 		return
 	}
 
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("speakers")
-	docRef := collection.Doc(id)
+	ctx := c.Request.Context()
 
 	updates := []firestore.Update{}
 	if req.Name != "" {
@@ -108,45 +102,49 @@ func (h *SpeakerHandler) UpdateSpeaker(c *gin.Context) {
 	}
 
 	if len(updates) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		apierror.Abort(c, apierror.BadRequest("No fields to update"))
 		return
 	}
 
-	_, err := docRef.Update(ctx, updates)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+	var speaker models.Speaker
+	err := h.outbox.Transact(ctx, func(tx *services.Tx) error {
+		doc, err := tx.Get("speakers", id)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&speaker); err != nil {
+			return err
+		}
+		speaker.ID = id
+		req.Apply(&speaker)
 
-	// Fetch updated document
-	doc, err := docRef.Get(ctx)
+		if err := tx.Update("speakers", id, updates); err != nil {
+			return err
+		}
+		return tx.Emit(models.EventSpeakerUpdated, id, speaker)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Abort(c, err)
 		return
 	}
 
-	var speaker models.Speaker
-	if err := doc.DataTo(&speaker); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	speaker.ID = doc.Ref.ID
-
 	c.JSON(http.StatusOK, speaker)
 }
 
 func (h *SpeakerHandler) DeleteSpeaker(c *gin.Context) {
 	id := c.Param("id")
-	ctx := context.Background()
-	collection := h.firestore.GetCollection("speakers")
-	docRef := collection.Doc(id)
+	ctx := c.Request.Context()
 
-	_, err := docRef.Delete(ctx)
+	err := h.outbox.Transact(ctx, func(tx *services.Tx) error {
+		if err := tx.Delete("speakers", id); err != nil {
+			return err
+		}
+		return tx.Emit(models.EventSpeakerDeleted, id, gin.H{"id": id})
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Speaker deleted successfully"})
 }
-
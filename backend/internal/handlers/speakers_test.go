@@ -17,11 +17,11 @@ import (
 func TestSpeakerHandler_GetSpeakers(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	mockService, err := services.NewFirestoreService()
+	mockService, err := services.NewFirestoreService(testFirestoreConfig)
 	if err != nil {
 		t.Skipf("Skipping test: Firestore not available: %v", err)
 	}
-	handler := NewSpeakerHandler(mockService)
+	handler := NewSpeakerHandler(mockService, services.NewOutbox(mockService))
 
 	router := gin.New()
 	router.GET("/api/speakers", handler.GetSpeakers)
@@ -68,11 +68,11 @@ func TestSpeakerHandler_CreateSpeaker(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockService, err := services.NewFirestoreService()
+			mockService, err := services.NewFirestoreService(testFirestoreConfig)
 			if err != nil {
 				t.Skipf("Skipping test: Firestore not available: %v", err)
 			}
-			handler := NewSpeakerHandler(mockService)
+			handler := NewSpeakerHandler(mockService, services.NewOutbox(mockService))
 
 			router := gin.New()
 			router.POST("/api/admin/speakers", handler.CreateSpeaker)
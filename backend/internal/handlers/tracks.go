@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// TrackHandler manages the agenda's tracks.
+type TrackHandler struct {
+	firestore *services.FirestoreService
+	outbox    *services.Outbox
+}
+
+func NewTrackHandler(firestore *services.FirestoreService, outbox *services.Outbox) *TrackHandler {
+	return &TrackHandler{firestore: firestore, outbox: outbox}
+}
+
+// GetTracks lists the tracks in display order.
+func (h *TrackHandler) GetTracks(c *gin.Context) {
+	tracks, err := loadTracks(c.Request.Context(), h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tracks)
+}
+
+func (h *TrackHandler) CreateTrack(c *gin.Context) {
+	var req models.TrackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	track := newTrack(req)
+	docRef, err := h.firestore.Add(c.Request.Context(), "tracks", track)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	track.ID = docRef.ID
+	c.JSON(http.StatusCreated, track)
+}
+
+// UpdateTrack replaces a track's details.
+func (h *TrackHandler) UpdateTrack(c *gin.Context) {
+	id := c.Param("id")
+	var req models.TrackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	track := newTrack(req)
+	track.ID = id
+	updates := []firestore.Update{
+		{Path: "name", Value: track.Name},
+		{Path: "description", Value: track.Description},
+		{Path: "color", Value: track.Color},
+		{Path: "order", Value: track.Order},
+	}
+	if err := h.firestore.Update(c.Request.Context(), "tracks", id, updates); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, track)
+}
+
+// DeleteTrack removes a track. Sessions in it must be moved first.
+func (h *TrackHandler) DeleteTrack(c *gin.Context) {
+	err := deleteUnreferenced(c.Request.Context(), h.outbox, "tracks", c.Param("id"), "sessions", "trackId", "Sessions are in this track; move them first")
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Track deleted successfully"})
+}
+
+func newTrack(req models.TrackRequest) models.Track {
+	return models.Track{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Color:       req.Color,
+		Order:       req.Order,
+	}
+}
+
+// loadTracks returns every track in display order.
+func loadTracks(ctx context.Context, fs *services.FirestoreService) ([]models.Track, error) {
+	tracks := []models.Track{}
+	err := fs.All(ctx, "tracks", func(doc *firestore.DocumentSnapshot) error {
+		var track models.Track
+		if err := doc.DataTo(&track); err != nil {
+			return err
+		}
+		track.ID = doc.Ref.ID
+		tracks = append(tracks, track)
+		return nil
+	})
+	models.SortTracks(tracks)
+	return tracks, err
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// VenueHandler manages the venues the workshop is held at and their rooms.
+type VenueHandler struct {
+	firestore *services.FirestoreService
+	outbox    *services.Outbox
+}
+
+func NewVenueHandler(firestore *services.FirestoreService, outbox *services.Outbox) *VenueHandler {
+	return &VenueHandler{firestore: firestore, outbox: outbox}
+}
+
+// GetVenues lists the venues with their rooms.
+func (h *VenueHandler) GetVenues(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	venues, err := loadVenues(ctx, h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	rooms, err := loadRooms(ctx, h.firestore)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	for i := range venues {
+		for _, room := range rooms {
+			if room.VenueID == venues[i].ID {
+				venues[i].Rooms = append(venues[i].Rooms, room)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, venues)
+}
+
+func (h *VenueHandler) CreateVenue(c *gin.Context) {
+	var req models.VenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	venue := newVenue(req)
+	docRef, err := h.firestore.Add(c.Request.Context(), "venues", venue)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	venue.ID = docRef.ID
+	c.JSON(http.StatusCreated, venue)
+}
+
+// UpdateVenue replaces a venue's details. Its rooms are unchanged.
+func (h *VenueHandler) UpdateVenue(c *gin.Context) {
+	id := c.Param("id")
+	var req models.VenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	venue := newVenue(req)
+	venue.ID = id
+	updates := []firestore.Update{
+		{Path: "name", Value: venue.Name},
+		{Path: "address", Value: venue.Address},
+		{Path: "directions", Value: venue.Directions},
+		{Path: "mapUrl", Value: venue.MapURL},
+	}
+	if err := h.firestore.Update(c.Request.Context(), "venues", id, updates); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+// DeleteVenue removes a venue. Its rooms must be removed first.
+func (h *VenueHandler) DeleteVenue(c *gin.Context) {
+	err := deleteUnreferenced(c.Request.Context(), h.outbox, "venues", c.Param("id"), "rooms", "venueId", "Remove the venue's rooms first")
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Venue deleted successfully"})
+}
+
+func (h *VenueHandler) CreateRoom(c *gin.Context) {
+	var req models.RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	room := newRoom(req)
+	err := h.outbox.Transact(c.Request.Context(), func(tx *services.Tx) error {
+		if err := checkVenue(tx, room.VenueID); err != nil {
+			return err
+		}
+		ref, err := tx.Create("rooms", room)
+		if err != nil {
+			return err
+		}
+		room.ID = ref.ID
+		return nil
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, room)
+}
+
+// UpdateRoom replaces a room's details. Moving it to another venue keeps
+// its sessions.
+func (h *VenueHandler) UpdateRoom(c *gin.Context) {
+	id := c.Param("id")
+	var req models.RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+
+	room := newRoom(req)
+	room.ID = id
+	updates := []firestore.Update{
+		{Path: "venueId", Value: room.VenueID},
+		{Path: "name", Value: room.Name},
+		{Path: "capacity", Value: room.Capacity},
+		{Path: "accessibility", Value: room.Accessibility},
+	}
+	err := h.outbox.Transact(c.Request.Context(), func(tx *services.Tx) error {
+		if err := checkVenue(tx, room.VenueID); err != nil {
+			return err
+		}
+		return tx.Update("rooms", id, updates)
+	})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, room)
+}
+
+// DeleteRoom removes a room. Sessions in it must be moved first.
+func (h *VenueHandler) DeleteRoom(c *gin.Context) {
+	err := deleteUnreferenced(c.Request.Context(), h.outbox, "rooms", c.Param("id"), "sessions", "roomId", "Sessions are scheduled in this room; move them first")
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Room deleted successfully"})
+}
+
+// checkVenue rejects a room whose venue does not exist. Reading the venue
+// in the transaction that writes the room keeps it from being deleted
+// meanwhile.
+func checkVenue(tx *services.Tx, venueID string) error {
+	if _, err := tx.Get("venues", venueID); err != nil {
+		if apierror.From(err).Code == apierror.CodeNotFound {
+			return fieldError("venueId", "exists", "must be an existing venue")
+		}
+		return err
+	}
+	return nil
+}
+
+func newVenue(req models.VenueRequest) models.Venue {
+	return models.Venue{
+		Name:       strings.TrimSpace(req.Name),
+		Address:    strings.TrimSpace(req.Address),
+		Directions: strings.TrimSpace(req.Directions),
+		MapURL:     req.MapURL,
+	}
+}
+
+func newRoom(req models.RoomRequest) models.Room {
+	return models.Room{
+		VenueID:       req.VenueID,
+		Name:          strings.TrimSpace(req.Name),
+		Capacity:      req.Capacity,
+		Accessibility: strings.TrimSpace(req.Accessibility),
+	}
+}
+
+// loadVenues returns every venue by name.
+func loadVenues(ctx context.Context, fs *services.FirestoreService) ([]models.Venue, error) {
+	venues := []models.Venue{}
+	err := fs.Documents(ctx, "venues", fs.GetCollection("venues").OrderBy("name", firestore.Asc), func(doc *firestore.DocumentSnapshot) error {
+		var venue models.Venue
+		if err := doc.DataTo(&venue); err != nil {
+			return err
+		}
+		venue.ID = doc.Ref.ID
+		venues = append(venues, venue)
+		return nil
+	})
+	return venues, err
+}
+
+// loadRooms returns every room by name.
+func loadRooms(ctx context.Context, fs *services.FirestoreService) ([]models.Room, error) {
+	rooms := []models.Room{}
+	err := fs.All(ctx, "rooms", func(doc *firestore.DocumentSnapshot) error {
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+		room.ID = doc.Ref.ID
+		rooms = append(rooms, room)
+		return nil
+	})
+	models.SortRooms(rooms)
+	return rooms, err
+}
+
+// deleteUnreferenced deletes a document unless a document in refCollection
+// has its ID at path, in which case it fails with a conflict carrying
+// inUse. The check and the delete run in one transaction, and the document
+// is read in it, so a booking or room that refers to it conflicts with the
+// delete instead of being left pointing at nothing.
+func deleteUnreferenced(ctx context.Context, outbox *services.Outbox, collection, id, refCollection, path, inUse string) error {
+	return outbox.Transact(ctx, func(tx *services.Tx) error {
+		if _, err := tx.Get(collection, id); err != nil {
+			return err
+		}
+		docs, err := tx.Where(refCollection, path, "==", id)
+		if err != nil {
+			return err
+		}
+		if len(docs) > 0 {
+			return apierror.New(http.StatusConflict, apierror.CodeConflict, inUse)
+		}
+		return tx.Delete(collection, id)
+	})
+}
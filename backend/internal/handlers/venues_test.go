@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"testing"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoom(t *testing.T) {
+	room := newRoom(models.RoomRequest{VenueID: "v1", Name: " Room A ", Capacity: 40, Accessibility: " Step-free access "})
+	assert.Equal(t, models.Room{VenueID: "v1", Name: "Room A", Capacity: 40, Accessibility: "Step-free access"}, room)
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/models"
+	"appdirect-ai-workshop/internal/services"
+
+	"cloud.google.com/go/firestore"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler manages outbound webhooks and their delivery log.
+type WebhookHandler struct {
+	webhooks      *services.WebhookService
+	allowInsecure bool
+}
+
+// NewWebhookHandler creates a WebhookHandler. allowInsecure accepts plain
+// http and internal addresses; see config.WebhooksConfig.
+func NewWebhookHandler(webhooks *services.WebhookService, allowInsecure bool) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks, allowInsecure: allowInsecure}
+}
+
+// GetWebhooks lists every webhook, without secrets.
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	webhooks, err := h.webhooks.Webhooks(c.Request.Context())
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// CreateWebhook adds a webhook. The response is the only time its signing
+// secret is shown.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req models.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+	if err := h.validate(req); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	webhook := models.Webhook{URL: strings.TrimSpace(req.URL), Events: req.Events, Active: true}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	webhook, err := h.webhooks.CreateWebhook(c.Request.Context(), webhook)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id := c.Param("id")
+	var req models.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.InvalidBody(err))
+		return
+	}
+	if err := h.validate(req); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	updates := []firestore.Update{
+		{Path: "url", Value: strings.TrimSpace(req.URL)},
+		{Path: "events", Value: req.Events},
+	}
+	if req.Active != nil {
+		updates = append(updates, firestore.Update{Path: "active", Value: *req.Active})
+	}
+	webhook, err := h.webhooks.UpdateWebhook(c.Request.Context(), id, updates)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	webhook.Secret = ""
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.webhooks.DeleteWebhook(c.Request.Context(), c.Param("id")); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// TestWebhook sends a webhook.test event straight away and returns the
+// logged delivery, whether or not the receiver accepted it.
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	webhook, err := h.webhooks.Webhook(ctx, c.Param("id"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	delivery, err := h.webhooks.Test(ctx, webhook)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// GetDeliveries lists the most recent deliveries to a webhook.
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	deliveries, err := h.webhooks.Deliveries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayDelivery queues a delivery to be sent again.
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	delivery, err := h.webhooks.Replay(c.Request.Context(), c.Param("id"), c.Param("deliveryId"))
+	if errors.Is(err, services.ErrDeliveryInFlight) {
+		apierror.Abort(c, apierror.New(http.StatusConflict, apierror.CodeConflict, "The delivery is being sent; try again shortly"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// validate checks the parts of a webhook request that binding tags cannot.
+func (h *WebhookHandler) validate(req models.WebhookRequest) error {
+	if err := validateWebhookURL(req.URL, h.allowInsecure); err != nil {
+		return err
+	}
+	return validateWebhookEvents(req.Events)
+}
+
+// validateWebhookEvents accepts the events listed in models.WebhookEvents.
+func validateWebhookEvents(events []string) error {
+	for _, event := range events {
+		if !models.IsWebhookEvent(event) {
+			return fieldError("events", "oneof", "must be one of "+strings.Join(models.WebhookEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// validateWebhookURL accepts absolute https URLs that do not name an
+// internal host. With allowInsecure, plain http and internal hosts are
+// accepted too, so webhooks can be tried against a local receiver. Where a
+// hostname resolves is checked again on every delivery.
+func validateWebhookURL(raw string, allowInsecure bool) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fieldError("url", "url", "must be an absolute http or https URL")
+	}
+	if services.CheckWebhookURL(u, allowInsecure) != nil {
+		return fieldError("url", "url", "must be an https URL with a public address")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	for _, valid := range []string{"https://hooks.slack.com/services/T000/B000/XXX", " https://crm.example.com/in ", "https://203.0.113.10/hook"} {
+		assert.NoError(t, validateWebhookURL(valid, false), valid)
+	}
+
+	invalid := []string{
+		"ftp://example.com/hook", "/relative", "https://", "mailto:ops@example.com",
+		"http://crm.example.com/in", "https://localhost:9000/hook", "https://127.0.0.1/hook",
+		"https://169.254.169.254/computeMetadata/v1/", "https://10.0.0.5/hook", "https://[::1]/hook",
+	}
+	for _, raw := range invalid {
+		err := validateWebhookURL(raw, false)
+		var apiErr *apierror.Error
+		if assert.True(t, errors.As(err, &apiErr), raw) {
+			assert.Equal(t, "url", apiErr.Details[0].Field)
+		}
+	}
+}
+
+func TestValidateWebhookURL_AllowInsecure(t *testing.T) {
+	for _, valid := range []string{"http://localhost:9000/hook", "http://192.168.1.20/hook"} {
+		assert.NoError(t, validateWebhookURL(valid, true), valid)
+	}
+	assert.Error(t, validateWebhookURL("ftp://example.com/hook", true))
+}
+
+func TestValidateWebhookEvents(t *testing.T) {
+	assert.NoError(t, validateWebhookEvents(models.WebhookEvents))
+
+	err := validateWebhookEvents([]string{models.EventAttendeeCreated, models.EventWebhookTest})
+	var apiErr *apierror.Error
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, "events", apiErr.Details[0].Field)
+		assert.Equal(t, "oneof", apiErr.Details[0].Code)
+	}
+}
@@ -0,0 +1,50 @@
+// Package lifecycle runs background workers and stops them in order when
+// the server shuts down.
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Workers is a group of background goroutines sharing one cancellation.
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel}
+}
+
+// Go starts fn in a goroutine. fn must return promptly once ctx is done.
+func (w *Workers) Go(name string, fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+		slog.Debug("worker stopped", "worker", name)
+	}()
+}
+
+// Stop cancels every worker and waits for them to return, giving up when
+// ctx is done.
+func (w *Workers) Stop(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,40 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkers_Stop(t *testing.T) {
+	workers := NewWorkers()
+
+	stopped := make(chan struct{})
+	workers.Go("test", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	assert.NoError(t, workers.Stop(context.Background()))
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("worker did not stop")
+	}
+}
+
+func TestWorkers_StopDeadline(t *testing.T) {
+	workers := NewWorkers()
+
+	release := make(chan struct{})
+	defer close(release)
+	workers.Go("stuck", func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, workers.Stop(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,188 @@
+// Package metrics exposes Prometheus metrics for HTTP traffic, Firestore
+// operations and registrations. All methods are safe to call on a nil
+// *Metrics, which makes instrumentation optional in tests.
+package metrics
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"appdirect-ai-workshop/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// maxDesignations caps the number of distinct designation label values;
+// anything beyond it is counted as "other".
+const maxDesignations = 50
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	firestoreOps      *prometheus.CounterVec
+	firestoreErrors   *prometheus.CounterVec
+	firestoreDuration *prometheus.HistogramVec
+
+	registrations *prometheus.CounterVec
+	cancellations prometheus.Counter
+	attendees     prometheus.Gauge
+
+	mu           sync.Mutex
+	designations map[string]bool
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		firestoreOps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "firestore_operations_total",
+			Help: "Firestore operations by operation and collection.",
+		}, []string{"op", "collection"}),
+		firestoreErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "firestore_errors_total",
+			Help: "Failed Firestore operations by operation and collection.",
+		}, []string{"op", "collection"}),
+		firestoreDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "firestore_operation_duration_seconds",
+			Help:    "Firestore operation latency by operation and collection.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"op", "collection"}),
+		registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "workshop_registrations_total",
+			Help: "Attendee registrations by designation.",
+		}, []string{"designation"}),
+		cancellations: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "workshop_cancellations_total",
+			Help: "Registrations cancelled by attendees.",
+		}),
+		attendees: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "workshop_attendees",
+			Help: "Current number of registered attendees.",
+		}),
+		designations: make(map[string]bool),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration,
+		m.firestoreOps, m.firestoreErrors, m.firestoreDuration,
+		m.registrations, m.cancellations, m.attendees,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format. If token is
+// set, requests must carry it as a bearer token. Without a token the
+// metrics are only served when public is set, and are not found otherwise.
+func (m *Metrics) Handler(token string, public bool) gin.HandlerFunc {
+	h := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	want := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		switch {
+		case token != "":
+			if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), want) != 1 {
+				apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
+				return
+			}
+		case !public:
+			apierror.Abort(c, apierror.NotFound("Route not found"))
+			return
+		}
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// Middleware records request counts and latency per route and status.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m == nil {
+			c.Next()
+			return
+		}
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveFirestore records a finished Firestore operation.
+func (m *Metrics) ObserveFirestore(op, collection string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.firestoreOps.WithLabelValues(op, collection).Inc()
+	m.firestoreDuration.WithLabelValues(op, collection).Observe(duration.Seconds())
+	if err != nil {
+		m.firestoreErrors.WithLabelValues(op, collection).Inc()
+	}
+}
+
+// RegistrationCreated counts a new registration and bumps the attendee gauge.
+func (m *Metrics) RegistrationCreated(designation string) {
+	if m == nil {
+		return
+	}
+	m.registrations.WithLabelValues(m.designationLabel(designation)).Inc()
+	m.attendees.Inc()
+}
+
+// RegistrationCancelled counts a cancellation and lowers the attendee gauge.
+func (m *Metrics) RegistrationCancelled() {
+	if m == nil {
+		return
+	}
+	m.cancellations.Inc()
+	m.attendees.Dec()
+}
+
+// SetAttendees sets the attendee gauge to an exact count.
+func (m *Metrics) SetAttendees(count int) {
+	if m == nil {
+		return
+	}
+	m.attendees.Set(float64(count))
+}
+
+func (m *Metrics) designationLabel(designation string) string {
+	label := strings.ToLower(strings.TrimSpace(designation))
+	if label == "" {
+		return "unknown"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.designations[label] {
+		if len(m.designations) >= maxDesignations {
+			return "other"
+		}
+		m.designations[label] = true
+	}
+	return label
+}
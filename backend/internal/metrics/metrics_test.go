@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := New()
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/metrics", m.Handler("secret", false))
+	router.GET("/api/sessions/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	req, _ := http.NewRequest("GET", "/api/sessions/42", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	m.ObserveFirestore("get", "sessions", 10*time.Millisecond, nil)
+	m.ObserveFirestore("add", "attendees", 10*time.Millisecond, errors.New("boom"))
+	m.SetAttendees(10)
+	m.RegistrationCreated(" Software Engineer ")
+	m.RegistrationCreated("Architect")
+	m.RegistrationCancelled()
+
+	req, _ = http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req.Header.Set("Authorization", "Bearer secreT")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/api/sessions/:id",status="200"} 1`)
+	assert.Contains(t, body, `firestore_operations_total{collection="sessions",op="get"} 1`)
+	assert.Contains(t, body, `firestore_errors_total{collection="attendees",op="add"} 1`)
+	assert.Contains(t, body, `workshop_registrations_total{designation="software engineer"} 1`)
+	assert.Contains(t, body, `workshop_cancellations_total 1`)
+	assert.Contains(t, body, `workshop_attendees 11`)
+}
+
+func TestMetrics_DesignationCardinality(t *testing.T) {
+	m := New()
+	for i := 0; i < maxDesignations; i++ {
+		m.designationLabel(fmt.Sprintf("designation %d", i))
+	}
+	assert.Equal(t, "other", m.designationLabel("one too many"))
+	assert.Equal(t, "designation 1", m.designationLabel("Designation 1"))
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	m.ObserveFirestore("get", "sessions", time.Millisecond, nil)
+	m.RegistrationCreated("x")
+	m.RegistrationCancelled()
+	m.SetAttendees(1)
+}
+
+func TestHandler_WithoutToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New()
+
+	for _, tt := range []struct {
+		public bool
+		want   int
+	}{
+		{false, http.StatusNotFound},
+		{true, http.StatusOK},
+	} {
+		router := gin.New()
+		router.GET("/metrics", m.Handler("", tt.public))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, tt.want, w.Code, "public=%v", tt.public)
+	}
+}
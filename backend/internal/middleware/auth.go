@@ -1,38 +1,111 @@
 package middleware
 
 import (
-	"net/http"
-	"os"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/config"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Simple session-based auth using cookie
-func AdminAuth() gin.HandlerFunc {
+// AdminSessionCookie holds the signed session token issued at admin login.
+const AdminSessionCookie = "admin_session"
+
+var errInvalidSession = errors.New("invalid or expired admin session")
+
+// AdminSessions issues and verifies admin session tokens. A token holds its
+// expiry and a random nonce, signed with HMAC-SHA256, so every instance
+// configured with the same key accepts it without shared state.
+type AdminSessions struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewAdminSessions signs sessions with the configured session secret or,
+// without one, with a key derived from the admin password, so changing the
+// password signs every admin out.
+func NewAdminSessions(admin config.AdminConfig) *AdminSessions {
+	key := []byte(admin.SessionSecret)
+	if len(key) == 0 {
+		sum := sha256.Sum256([]byte("admin-session:" + admin.PasswordHash + "\x00" + admin.Password))
+		key = sum[:]
+	}
+	return &AdminSessions{key: key, ttl: admin.SessionTTL, now: time.Now}
+}
+
+// TTL is how long a session lasts.
+func (s *AdminSessions) TTL() time.Duration {
+	return s.ttl
+}
+
+// Issue returns a new session token.
+func (s *AdminSessions) Issue() (string, error) {
+	payload := make([]byte, 8+16)
+	binary.BigEndian.PutUint64(payload, uint64(s.now().Add(s.ttl).Unix()))
+	if _, err := rand.Read(payload[8:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload)), nil
+}
+
+// Verify checks the token's signature and expiry.
+func (s *AdminSessions) Verify(token string) error {
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return errInvalidSession
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil || len(payload) != 8+16 {
+		return errInvalidSession
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, s.sign(payload)) {
+		return errInvalidSession
+	}
+	if s.now().After(time.Unix(int64(binary.BigEndian.Uint64(payload[:8])), 0)) {
+		return errInvalidSession
+	}
+	return nil
+}
+
+func (s *AdminSessions) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// AdminAuth only lets requests with a valid admin session cookie through.
+func AdminAuth(sessions *AdminSessions) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Check for admin session cookie
-		adminCookie, err := c.Cookie("admin_session")
-		if err != nil || adminCookie != "authenticated" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort()
+		token, err := c.Cookie(AdminSessionCookie)
+		if err != nil || sessions.Verify(token) != nil {
+			apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 			return
 		}
 
+		c.Set(ActorKey, "admin")
 		c.Next()
 	}
 }
 
-// VerifyPassword compares password with hashed password from env
-func VerifyPassword(password string) bool {
-	hashedPassword := os.Getenv("ADMIN_PASSWORD_HASH")
-	if hashedPassword == "" {
+// VerifyPassword compares password with the configured admin password hash
+func VerifyPassword(admin config.AdminConfig, password string) bool {
+	if admin.PasswordHash == "" {
 		// Fallback to plain password for development
-		expectedPassword := os.Getenv("ADMIN_PASSWORD")
-		return password == expectedPassword
+		return admin.Password != "" && password == admin.Password
 	}
 
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	err := bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(password))
 	return err == nil
 }
 
@@ -41,4 +114,3 @@ func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
 }
-
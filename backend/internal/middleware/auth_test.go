@@ -3,8 +3,10 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +15,17 @@ import (
 func TestAdminAuth(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
+	sessions := NewAdminSessions(config.AdminConfig{Password: "testpassword", SessionTTL: time.Hour})
+	token, err := sessions.Issue()
+	assert.NoError(t, err)
+
+	// Same kind of token, signed for another password
+	other, _ := NewAdminSessions(config.AdminConfig{Password: "other", SessionTTL: time.Hour}).Issue()
+
+	expired := NewAdminSessions(config.AdminConfig{Password: "testpassword", SessionTTL: time.Hour})
+	expired.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	expiredToken, _ := expired.Issue()
+
 	tests := []struct {
 		name           string
 		cookieValue    string
@@ -20,9 +33,24 @@ func TestAdminAuth(t *testing.T) {
 	}{
 		{
 			name:           "authenticated",
-			cookieValue:    "authenticated",
+			cookieValue:    token,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "forged constant cookie",
+			cookieValue:    "authenticated",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "signed with another key",
+			cookieValue:    other,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "expired",
+			cookieValue:    expiredToken,
+			expectedStatus: http.StatusUnauthorized,
+		},
 		{
 			name:           "not authenticated",
 			cookieValue:    "",
@@ -38,7 +66,7 @@ func TestAdminAuth(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			router.Use(AdminAuth())
+			router.Use(AdminAuth(sessions))
 			router.GET("/test", func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{"message": "success"})
 			})
@@ -46,7 +74,7 @@ func TestAdminAuth(t *testing.T) {
 			req, _ := http.NewRequest("GET", "/test", nil)
 			if tt.cookieValue != "" {
 				req.AddCookie(&http.Cookie{
-					Name:  "admin_session",
+					Name:  AdminSessionCookie,
 					Value: tt.cookieValue,
 				})
 			}
@@ -60,8 +88,7 @@ func TestAdminAuth(t *testing.T) {
 }
 
 func TestVerifyPassword(t *testing.T) {
-	os.Setenv("ADMIN_PASSWORD", "testpassword")
-	defer os.Unsetenv("ADMIN_PASSWORD")
+	admin := config.AdminConfig{Password: "testpassword"}
 
 	tests := []struct {
 		name     string
@@ -82,9 +109,20 @@ func TestVerifyPassword(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := VerifyPassword(tt.password)
+			result := VerifyPassword(admin, tt.password)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
-}
 
+	t.Run("bcrypt hash", func(t *testing.T) {
+		hash, err := HashPassword("hashedpassword")
+		assert.NoError(t, err)
+		hashed := config.AdminConfig{PasswordHash: hash, Password: "ignored"}
+		assert.True(t, VerifyPassword(hashed, "hashedpassword"))
+		assert.False(t, VerifyPassword(hashed, "ignored"))
+	})
+
+	t.Run("no password configured", func(t *testing.T) {
+		assert.False(t, VerifyPassword(config.AdminConfig{}, ""))
+	})
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"appdirect-ai-workshop/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	// ActorKey is the gin context key holding who made an authenticated request.
+	ActorKey = "actor"
+)
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID assigns every request an ID, reusing a well-formed incoming
+// X-Request-ID header, and returns it in the response. The ID and a logger
+// tagged with it are stored on the request context so handlers and the
+// service layer log with the same correlation fields.
+//
+// projectID, when set, links log lines to the current OpenTelemetry span or,
+// without one, to the X-Cloud-Trace-Context header added by Cloud Run.
+func RequestID(logger *slog.Logger, projectID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		attrs := []any{slog.String("requestId", id)}
+		if projectID != "" {
+			if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+				attrs = append(attrs,
+					slog.String(logging.TraceKey, fmt.Sprintf("projects/%s/traces/%s", projectID, span.TraceID())),
+					slog.String(logging.SpanKey, span.SpanID().String()),
+				)
+			} else if header := c.GetHeader("X-Cloud-Trace-Context"); header != "" {
+				traceID, _, _ := strings.Cut(header, "/")
+				attrs = append(attrs, slog.String(logging.TraceKey, fmt.Sprintf("projects/%s/traces/%s", projectID, traceID)))
+			}
+		}
+
+		ctx := logging.WithRequestID(c.Request.Context(), id)
+		ctx = logging.WithLogger(ctx, logger.With(attrs...))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequestLogger writes one structured line per request with the route,
+// status, latency and, for authenticated routes, the actor.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		attrs := []any{
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("latencyMs", float64(latency.Microseconds())/1000),
+			slog.Group("httpRequest",
+				slog.String("requestMethod", c.Request.Method),
+				slog.String("requestUrl", c.Request.URL.RequestURI()),
+				slog.Int("status", status),
+				slog.Int("responseSize", c.Writer.Size()),
+				slog.String("userAgent", c.Request.UserAgent()),
+				slog.String("remoteIp", c.ClientIP()),
+				slog.String("latency", fmt.Sprintf("%.9fs", latency.Seconds())),
+			),
+		}
+		if actor := c.GetString(ActorKey); actor != "" {
+			attrs = append(attrs, slog.String("actor", actor))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"appdirect-ai-workshop/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var seen string
+	router := gin.New()
+	router.Use(RequestID(slog.Default(), ""))
+	router.GET("/test", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{name: "generated", incoming: "", reused: false},
+		{name: "reused", incoming: "abc-123", reused: true},
+		{name: "invalid replaced", incoming: "bad id\n", reused: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/test", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, seen)
+			if tt.reused {
+				assert.Equal(t, tt.incoming, id)
+			} else {
+				assert.NotEqual(t, tt.incoming, id)
+			}
+		})
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	router := gin.New()
+	router.Use(RequestID(logger, "my-project"), RequestLogger())
+	router.GET("/admin/:id", func(c *gin.Context) {
+		c.Set(ActorKey, "admin")
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+
+	req, _ := http.NewRequest("GET", "/admin/42", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	req.Header.Set("X-Cloud-Trace-Context", "105445aa7843bc8bf206b12000100000/1;o=1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARNING", entry["severity"])
+	assert.Equal(t, "request", entry["message"])
+	assert.Equal(t, "req-1", entry["requestId"])
+	assert.Equal(t, "/admin/:id", entry["route"])
+	assert.Equal(t, float64(http.StatusNotFound), entry["status"])
+	assert.Equal(t, "admin", entry["actor"])
+	assert.Equal(t, "projects/my-project/traces/105445aa7843bc8bf206b12000100000", entry[logging.TraceKey])
+	assert.Contains(t, entry, "latencyMs")
+	assert.Contains(t, entry, "httpRequest")
+}
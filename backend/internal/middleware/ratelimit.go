@@ -0,0 +1,364 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"appdirect-ai-workshop/internal/apierror"
+	"appdirect-ai-workshop/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitStore holds token bucket and lockout state. The in-memory store
+// is enough for a single instance; a shared store (e.g. Firestore) keeps the
+// limits consistent when Cloud Run scales out.
+type RateLimitStore interface {
+	// Take removes one token from the bucket identified by key and reports
+	// whether the request is allowed. When it is not, retryAfter is the time
+	// until the next token becomes available.
+	Take(ctx context.Context, key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+
+	// LockedUntil returns the time until which key is locked out.
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+
+	// RecordFailure registers a failed attempt for key and returns the
+	// resulting lockout expiry (zero if the key is not locked).
+	RecordFailure(ctx context.Context, key string, policy LockoutPolicy) (time.Time, error)
+
+	// Reset clears the failure history for key.
+	Reset(ctx context.Context, key string) error
+
+	// Claim remembers key until expires and reports whether it was free,
+	// so that one-time tokens such as solved challenges are accepted once
+	// across every instance.
+	Claim(ctx context.Context, key string, expires time.Time) (bool, error)
+}
+
+// Limit describes a token bucket: Rate tokens are added per second up to a
+// maximum of Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns a limit allowing n requests per minute with the given burst.
+func PerMinute(n int, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// TokenBucket is the persisted state of a single bucket.
+type TokenBucket struct {
+	Tokens    float64   `firestore:"tokens"`
+	UpdatedAt time.Time `firestore:"updatedAt"`
+}
+
+// Take refills the bucket up to now and tries to consume one token.
+func (b *TokenBucket) Take(now time.Time, limit Limit) (bool, time.Duration) {
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = float64(limit.Burst)
+	} else if elapsed := now.Sub(b.UpdatedAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(float64(limit.Burst), b.Tokens+elapsed*limit.Rate)
+	}
+	b.UpdatedAt = now
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return true, 0
+	}
+	if limit.Rate <= 0 {
+		return false, time.Hour
+	}
+	wait := (1 - b.Tokens) / limit.Rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// LockoutPolicy configures exponential lockout after repeated failures.
+// Once Threshold consecutive failures have been recorded the key is locked
+// for BaseDelay, doubling with every further failure up to MaxDelay.
+// Failures older than ResetAfter are forgotten.
+type LockoutPolicy struct {
+	Threshold  int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	ResetAfter time.Duration
+}
+
+// DefaultLockoutPolicy is used for the admin login.
+var DefaultLockoutPolicy = LockoutPolicy{
+	Threshold:  5,
+	BaseDelay:  30 * time.Second,
+	MaxDelay:   time.Hour,
+	ResetAfter: 24 * time.Hour,
+}
+
+// LockoutState is the persisted failure history of a single key.
+type LockoutState struct {
+	Failures    int       `firestore:"failures"`
+	LastFailure time.Time `firestore:"lastFailure"`
+	LockedUntil time.Time `firestore:"lockedUntil"`
+}
+
+// Fail records a failure at now and updates LockedUntil according to policy.
+func (s *LockoutState) Fail(now time.Time, policy LockoutPolicy) {
+	if policy.ResetAfter > 0 && !s.LastFailure.IsZero() && now.Sub(s.LastFailure) > policy.ResetAfter {
+		s.Failures = 0
+	}
+	s.Failures++
+	s.LastFailure = now
+
+	if s.Failures < policy.Threshold {
+		return
+	}
+	delay := policy.BaseDelay << uint(min(s.Failures-policy.Threshold, 30))
+	if delay <= 0 || delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	s.LockedUntil = now.Add(delay)
+}
+
+// KeyFunc extracts the value a rate limit is keyed on. Returning an empty
+// string skips the rule for that request.
+type KeyFunc func(c *gin.Context) string
+
+// ClientIPKey keys requests by client IP.
+func ClientIPKey(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// ContextKey keys requests by a string an earlier handler stored in the
+// context under name, e.g. the ID of an authenticated attendee.
+func ContextKey(name string) KeyFunc {
+	return func(c *gin.Context) string {
+		return c.GetString(name)
+	}
+}
+
+// JSONFieldKey keys requests by a top-level string field of the JSON body,
+// e.g. the email on a registration. The body is restored for the handler.
+func JSONFieldKey(field string) KeyFunc {
+	return func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ""
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+		c.Request.Body.Close()
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return ""
+		}
+
+		var fields map[string]interface{}
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return ""
+		}
+		value, _ := fields[field].(string)
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+}
+
+// RateLimitRule is a named token bucket applied to a route group.
+type RateLimitRule struct {
+	Name  string
+	Limit Limit
+	Key   KeyFunc
+}
+
+// RateLimit enforces every rule in order and rejects the request with 429
+// and a Retry-After header as soon as one bucket is empty. Store errors are
+// logged and the request is let through.
+func RateLimit(store RateLimitStore, rules ...RateLimitRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, rule := range rules {
+			key := rule.Key(c)
+			if key == "" {
+				continue
+			}
+
+			allowed, retryAfter, err := store.Take(c.Request.Context(), rule.Name+":"+key, rule.Limit)
+			if err != nil {
+				logging.FromContext(c.Request.Context()).Error("rate limit store failed", "rule", rule.Name, "error", err)
+				continue
+			}
+			if !allowed {
+				tooManyRequests(c, retryAfter)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// LoginLockout locks a key out after repeated failed logins. The wrapped
+// handler signals a failure by responding 401 and a success with 200.
+func LoginLockout(store RateLimitStore, policy LockoutPolicy, keyFunc KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		key = "lockout:" + key
+		ctx := c.Request.Context()
+
+		lockedUntil, err := store.LockedUntil(ctx, key)
+		if err != nil {
+			logging.FromContext(ctx).Error("login lockout store failed", "error", err)
+		} else if wait := time.Until(lockedUntil); wait > 0 {
+			tooManyRequests(c, wait)
+			return
+		}
+
+		c.Next()
+
+		switch c.Writer.Status() {
+		case http.StatusUnauthorized:
+			_, err = store.RecordFailure(ctx, key, policy)
+		case http.StatusOK:
+			err = store.Reset(ctx, key)
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("login lockout store failed", "error", err)
+		}
+	}
+}
+
+func tooManyRequests(c *gin.Context, retryAfter time.Duration) {
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	apierror.Abort(c, apierror.New(http.StatusTooManyRequests, apierror.CodeRateLimited, "Too many requests, please try again later").WithRetryAfter(retryAfter))
+}
+
+// MemoryRateLimitStore keeps rate limit state in process memory. Expired
+// state is swept out at most once per pruneInterval.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*TokenBucket
+	lockouts  map[string]*memoryLockout
+	claims    map[string]time.Time
+	nextPrune time.Time
+	now       func() time.Time
+}
+
+// memoryLockout is a key's failure history and how long the policy that
+// recorded it remembers failures.
+type memoryLockout struct {
+	LockoutState
+	resetAfter time.Duration
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:  make(map[string]*TokenBucket),
+		lockouts: make(map[string]*memoryLockout),
+		claims:   make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+const (
+	// maxIdleBucket is how long an untouched bucket is kept before it is pruned.
+	maxIdleBucket = time.Hour
+	// pruneInterval is how often expired state is swept out.
+	pruneInterval = time.Minute
+)
+
+// prune drops idle buckets, expired claims and lockouts that no longer
+// lock the key out or count towards one. Callers hold s.mu.
+func (s *MemoryRateLimitStore) prune(now time.Time) {
+	if now.Before(s.nextPrune) {
+		return
+	}
+	s.nextPrune = now.Add(pruneInterval)
+
+	for k, b := range s.buckets {
+		if now.Sub(b.UpdatedAt) > maxIdleBucket {
+			delete(s.buckets, k)
+		}
+	}
+	for k, until := range s.claims {
+		if now.After(until) {
+			delete(s.claims, k)
+		}
+	}
+	for k, state := range s.lockouts {
+		if now.After(state.LockedUntil) && state.resetAfter > 0 && now.Sub(state.LastFailure) > state.resetAfter {
+			delete(s.lockouts, k)
+		}
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &TokenBucket{}
+		s.buckets[key] = bucket
+	}
+	allowed, retryAfter := bucket.Take(now, limit)
+	return allowed, retryAfter, nil
+}
+
+func (s *MemoryRateLimitStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(s.now())
+	if state, ok := s.lockouts[key]; ok {
+		return state.LockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+func (s *MemoryRateLimitStore) RecordFailure(ctx context.Context, key string, policy LockoutPolicy) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	state, ok := s.lockouts[key]
+	if !ok {
+		state = &memoryLockout{}
+		s.lockouts[key] = state
+	}
+	state.Fail(now, policy)
+	state.resetAfter = policy.ResetAfter
+	return state.LockedUntil, nil
+}
+
+func (s *MemoryRateLimitStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.lockouts, key)
+	return nil
+}
+
+func (s *MemoryRateLimitStore) Claim(ctx context.Context, key string, expires time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	if until, taken := s.claims[key]; taken && !now.After(until) {
+		return false, nil
+	}
+	s.claims[key] = expires
+	return true, nil
+}
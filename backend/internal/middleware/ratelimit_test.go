@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_Take(t *testing.T) {
+	now := time.Now()
+	limit := Limit{Rate: 1, Burst: 2}
+	var bucket TokenBucket
+
+	allowed, _ := bucket.Take(now, limit)
+	assert.True(t, allowed)
+	allowed, _ = bucket.Take(now, limit)
+	assert.True(t, allowed)
+
+	allowed, retryAfter := bucket.Take(now, limit)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, retryAfter)
+
+	allowed, _ = bucket.Take(now.Add(time.Second), limit)
+	assert.True(t, allowed)
+}
+
+func TestLockoutState_Fail(t *testing.T) {
+	now := time.Now()
+	policy := LockoutPolicy{Threshold: 3, BaseDelay: time.Second, MaxDelay: 5 * time.Second, ResetAfter: time.Hour}
+	var state LockoutState
+
+	state.Fail(now, policy)
+	state.Fail(now, policy)
+	assert.True(t, state.LockedUntil.IsZero())
+
+	state.Fail(now, policy)
+	assert.Equal(t, now.Add(time.Second), state.LockedUntil)
+
+	state.Fail(now, policy)
+	assert.Equal(t, now.Add(2*time.Second), state.LockedUntil)
+
+	state.Fail(now, policy)
+	state.Fail(now, policy)
+	assert.Equal(t, now.Add(5*time.Second), state.LockedUntil)
+
+	later := now.Add(2 * time.Hour)
+	state.Fail(later, policy)
+	assert.Equal(t, 1, state.Failures)
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := NewMemoryRateLimitStore()
+	router := gin.New()
+	router.Use(RateLimit(store, RateLimitRule{Name: "test", Limit: PerMinute(1, 2), Key: ClientIPKey}))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	expected := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for _, status := range expected {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code)
+	}
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	// A different client has its own bucket
+	req, _ = http.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestJSONFieldKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var key string
+	var body []byte
+	router := gin.New()
+	router.POST("/test", func(c *gin.Context) {
+		key = JSONFieldKey("email")(c)
+		body, _ = io.ReadAll(c.Request.Body)
+	})
+
+	payload := `{"email": " John@Example.com "}`
+	req, _ := http.NewRequest("POST", "/test", bytes.NewBufferString(payload))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "john@example.com", key)
+	assert.Equal(t, payload, string(body))
+}
+
+func TestContextKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit(store, RateLimitRule{Name: "per-user", Limit: PerMinute(1, 1), Key: ContextKey("userId")})
+
+	router := gin.New()
+	router.GET("/anonymous", limit, func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/:user", func(c *gin.Context) { c.Set("userId", c.Param("user")) }, limit, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get("/alice"))
+	assert.Equal(t, http.StatusTooManyRequests, get("/alice"))
+	assert.Equal(t, http.StatusOK, get("/bob"), "each key has its own bucket")
+	assert.Equal(t, http.StatusOK, get("/anonymous"))
+	assert.Equal(t, http.StatusOK, get("/anonymous"), "requests without the key are not limited")
+}
+
+func TestLoginLockout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := NewMemoryRateLimitStore()
+	policy := LockoutPolicy{Threshold: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	router := gin.New()
+	router.POST("/login", LoginLockout(store, policy, ClientIPKey), func(c *gin.Context) {
+		if c.Query("password") == "secret" {
+			c.JSON(http.StatusOK, gin.H{"message": "Login successful"})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
+	})
+
+	login := func(password string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/login?password="+password, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+	assert.Equal(t, http.StatusOK, login("secret").Code)
+
+	assert.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+
+	w := login("secret")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+}
+
+func TestMemoryRateLimitStore_Claim(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+
+	ok, err := store.Claim(ctx, "token", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, _ = store.Claim(ctx, "token", now.Add(time.Minute))
+	assert.False(t, ok, "claimed twice")
+
+	// Expired claims are forgotten
+	now = now.Add(2 * time.Minute)
+	ok, _ = store.Claim(ctx, "token", now.Add(time.Minute))
+	assert.True(t, ok)
+}
+
+func TestMemoryRateLimitStore_Prune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	policy := LockoutPolicy{Threshold: 1, BaseDelay: time.Minute, MaxDelay: time.Minute, ResetAfter: 2 * time.Hour}
+
+	_, _, err := store.Take(ctx, "ip", PerMinute(1, 1))
+	require.NoError(t, err)
+	_, err = store.RecordFailure(ctx, "login", policy)
+	require.NoError(t, err)
+	_, err = store.Claim(ctx, "token", now.Add(time.Minute))
+	require.NoError(t, err)
+
+	// The lockout has expired but its failures still count
+	now = now.Add(90 * time.Minute)
+	_, _ = store.LockedUntil(ctx, "other")
+	assert.Empty(t, store.buckets)
+	assert.Empty(t, store.claims)
+	assert.Len(t, store.lockouts, 1)
+
+	// Sweeps are spaced out
+	_, _ = store.Claim(ctx, "token", now.Add(10*time.Second))
+	now = now.Add(pruneInterval / 2)
+	_, _ = store.LockedUntil(ctx, "other")
+	assert.Len(t, store.claims, 1)
+
+	now = now.Add(time.Hour)
+	_, _ = store.LockedUntil(ctx, "other")
+	assert.Empty(t, store.claims)
+	assert.Empty(t, store.lockouts)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// SessionAttendance records that an attendee was at a session. Staff check
+// attendees in at the door, and only attendees checked in to a session may
+// rate it. Each attendee has at most one per session, stored under
+// AttendanceID.
+type SessionAttendance struct {
+	ID          string    `json:"id" firestore:"-"`
+	SessionID   string    `json:"sessionId" firestore:"sessionId"`
+	AttendeeID  string    `json:"attendeeId" firestore:"attendeeId"`
+	CheckedInAt time.Time `json:"checkedInAt" firestore:"checkedInAt"`
+
+	// Name is the attendee's name, filled in for admins listing a session's
+	// attendance. It is not stored.
+	Name string `json:"name,omitempty" firestore:"-"`
+}
+
+// AttendanceID is the document ID of attendeeID's attendance of sessionID,
+// so checking in twice finds the first record instead of adding another.
+func AttendanceID(sessionID, attendeeID string) string {
+	return sessionID + "_" + attendeeID
+}
@@ -1,15 +1,94 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
+// Registration statuses. Records created before statuses existed have an
+// empty status and count as confirmed. Registrations are confirmed when
+// created; pending marks one still awaiting confirmation and is reported
+// separately in the stats funnel.
+const (
+	StatusPending   = "pending"
+	StatusConfirmed = "confirmed"
+	StatusCancelled = "cancelled"
+)
+
+// Attendee is the full registration record. It contains personal data and
+// must only be served to admins; use PublicAttendee for anything public.
 type Attendee struct {
-	ID           string    `json:"id" firestore:"-"`
-	Name         string    `json:"name" firestore:"name"`
-	Email        string    `json:"email" firestore:"email"`
-	Designation  string    `json:"designation" firestore:"designation"`
-	RegisteredAt time.Time `json:"registeredAt" firestore:"registeredAt"`
+	ID           string     `json:"id" firestore:"-"`
+	Name         string     `json:"name" firestore:"name"`
+	Email        string     `json:"email" firestore:"email"`
+	Designation  string     `json:"designation" firestore:"designation"`
+	ListPublicly bool       `json:"listPublicly" firestore:"listPublicly"`
+	Status       string     `json:"status" firestore:"status"`
+	RegisteredAt time.Time  `json:"registeredAt" firestore:"registeredAt"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
+	CheckedInAt  *time.Time `json:"checkedInAt,omitempty" firestore:"checkedInAt,omitempty"`
+
+	// Answers to the event's custom questions, keyed by Question.Key.
+	Answers map[string]any `json:"answers,omitempty" firestore:"answers,omitempty"`
+
+	// EmailNormalized is Email trimmed and lower-cased. Lookups by address
+	// match on it, whatever case the attendee typed.
+	EmailNormalized string `json:"-" firestore:"emailNormalized"`
+
+	// ErasedAt is set when the attendee's personal data was erased. The
+	// record stays, without name or email, so aggregate stats are unchanged.
+	ErasedAt *time.Time `json:"erasedAt,omitempty" firestore:"erasedAt,omitempty"`
+}
+
+// Cancelled reports whether the attendee cancelled their registration.
+// Cancelled registrations are kept for the record but no longer count.
+func (a Attendee) Cancelled() bool {
+	return a.Status == StatusCancelled
+}
+
+// Confirmed reports whether the registration is confirmed.
+func (a Attendee) Confirmed() bool {
+	return a.Status == StatusConfirmed || a.Status == ""
+}
+
+// CheckedIn reports whether the attendee was checked in at the event.
+func (a Attendee) CheckedIn() bool {
+	return a.CheckedInAt != nil
+}
+
+// Erased reports whether the attendee's personal data was erased.
+func (a Attendee) Erased() bool {
+	return a.ErasedAt != nil
+}
+
+// PublicAttendee is what anyone may see about an attendee who consented to
+// be listed. It deliberately has no field that could hold an email, ID or
+// full name.
+type PublicAttendee struct {
+	FirstName   string `json:"firstName"`
+	Designation string `json:"designation"`
+}
+
+func NewPublicAttendee(a Attendee) PublicAttendee {
+	firstName := ""
+	if fields := strings.Fields(a.Name); len(fields) > 0 {
+		firstName = fields[0]
+	}
+	return PublicAttendee{FirstName: firstName, Designation: a.Designation}
+}
+
+// AttendeeMatch is an attendee search result. Higher scores are better
+// matches.
+type AttendeeMatch struct {
+	Attendee
+	Score int `json:"score"`
+}
+
+// AttendeeSearchResult is a page of search results. Total counts every
+// match, including those past the limit.
+type AttendeeSearchResult struct {
+	Results []AttendeeMatch `json:"results"`
+	Total   int             `json:"total"`
 }
 
 type AttendeeCount struct {
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPublicAttendee(t *testing.T) {
+	attendee := Attendee{
+		ID:           "abc",
+		Name:         "  Priya Sharma ",
+		Email:        "priya@example.com",
+		Designation:  "Tech Lead",
+		ListPublicly: true,
+		RegisteredAt: time.Now(),
+	}
+
+	public := NewPublicAttendee(attendee)
+	assert.Equal(t, PublicAttendee{FirstName: "Priya", Designation: "Tech Lead"}, public)
+
+	body, err := json.Marshal(public)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"firstName":"Priya","designation":"Tech Lead"}`, string(body))
+
+	assert.Equal(t, "", NewPublicAttendee(Attendee{}).FirstName)
+}
+
+func TestAttendee_Cancelled(t *testing.T) {
+	assert.False(t, Attendee{}.Cancelled(), "records without a status are confirmed")
+	assert.False(t, Attendee{Status: StatusConfirmed}.Cancelled())
+	assert.True(t, Attendee{Status: StatusCancelled}.Cancelled())
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Designation is a canonical job title offered on the registration form.
+// Values matching the name or one of the aliases are stored as the name, so
+// "SWE" and "software engineer" are counted as one designation.
+type Designation struct {
+	ID      string   `json:"id" firestore:"-"`
+	Name    string   `json:"name" firestore:"name"`
+	Aliases []string `json:"aliases,omitempty" firestore:"aliases,omitempty"`
+	Order   int      `json:"order" firestore:"order"`
+}
+
+type DesignationRequest struct {
+	Name    string   `json:"name" binding:"required,max=100"`
+	Aliases []string `json:"aliases" binding:"max=50,dive,max=100"`
+	Order   int      `json:"order"`
+}
+
+// DefaultDesignations are offered until an admin defines their own.
+var DefaultDesignations = []Designation{
+	{Name: "Software Engineer", Aliases: []string{"SWE", "SDE", "Developer", "Software Developer"}},
+	{Name: "Senior Software Engineer", Aliases: []string{"Senior SWE", "Sr Software Engineer", "Senior Developer"}},
+	{Name: "Tech Lead", Aliases: []string{"Technical Lead", "Team Lead"}},
+	{Name: "Engineering Manager", Aliases: []string{"EM"}},
+	{Name: "Product Manager", Aliases: []string{"PM"}},
+	{Name: "Data Scientist"},
+	{Name: "ML Engineer", Aliases: []string{"Machine Learning Engineer", "MLE"}},
+	{Name: "DevOps Engineer", Aliases: []string{"DevOps"}},
+	{Name: "Architect", Aliases: []string{"Software Architect", "Solutions Architect"}},
+	{Name: "Director"},
+	{Name: "VP", Aliases: []string{"Vice President"}},
+	{Name: "Other"},
+}
+
+// designationKey is the form designations are matched in: lower case, with
+// punctuation and repeated spaces reduced to single spaces.
+func designationKey(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// CheckDesignations reports whether a set of designations can be told
+// apart: every name and alias must match exactly one designation.
+func CheckDesignations(designations []Designation) error {
+	// Owners are compared by position, so two entries with the same name
+	// conflict while an alias may still repeat its own entry's name
+	owner := make(map[string]int)
+	for i, d := range designations {
+		for _, value := range append([]string{d.Name}, d.Aliases...) {
+			key := designationKey(value)
+			if key == "" {
+				return fmt.Errorf("%q: names and aliases need at least one letter or digit", d.Name)
+			}
+			if other, ok := owner[key]; ok && other != i {
+				return fmt.Errorf("%q is already used by %q", value, designations[other].Name)
+			}
+			owner[key] = i
+		}
+	}
+	return nil
+}
+
+// Taxonomy maps what registrants type to canonical designations.
+type Taxonomy struct {
+	names []string
+	byKey map[string]string
+}
+
+func NewTaxonomy(designations []Designation) *Taxonomy {
+	t := &Taxonomy{byKey: make(map[string]string)}
+	for _, d := range designations {
+		t.names = append(t.names, d.Name)
+		for _, value := range append([]string{d.Name}, d.Aliases...) {
+			if _, taken := t.byKey[designationKey(value)]; !taken {
+				t.byKey[designationKey(value)] = d.Name
+			}
+		}
+	}
+	return t
+}
+
+// Names returns the canonical designations in display order.
+func (t *Taxonomy) Names() []string {
+	return t.names
+}
+
+// Normalize returns the canonical designation value stands for.
+func (t *Taxonomy) Normalize(value string) (string, bool) {
+	name, ok := t.byKey[designationKey(value)]
+	return name, ok
+}
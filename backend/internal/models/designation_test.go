@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesignationKey(t *testing.T) {
+	assert.Equal(t, "sr software engineer", designationKey("  Sr. Software   Engineer "))
+	assert.Equal(t, "ml engineer", designationKey("ML-Engineer"))
+	assert.Equal(t, "", designationKey(" -- "))
+}
+
+func TestCheckDesignations(t *testing.T) {
+	assert.NoError(t, CheckDesignations(DefaultDesignations))
+
+	assert.Error(t, CheckDesignations([]Designation{
+		{Name: "Software Engineer", Aliases: []string{"SWE"}},
+		{Name: "Site Reliability Engineer", Aliases: []string{"swe"}},
+	}), "an alias may only belong to one designation")
+	assert.Error(t, CheckDesignations([]Designation{{Name: "Engineer"}, {Name: "engineer"}}))
+	assert.Error(t, CheckDesignations([]Designation{{Name: "Engineer"}, {Name: "Engineer"}}),
+		"two designations may not share a name")
+	assert.Error(t, CheckDesignations([]Designation{{Name: "Engineer"}, {Name: "Developer", Aliases: []string{"Engineer"}}}))
+	assert.Error(t, CheckDesignations([]Designation{{Name: "Engineer", Aliases: []string{"..."}}}))
+	assert.NoError(t, CheckDesignations([]Designation{{Name: "Engineer", Aliases: []string{"engineer"}}}),
+		"an alias may repeat its own name")
+}
+
+func TestTaxonomy_Normalize(t *testing.T) {
+	taxonomy := NewTaxonomy(DefaultDesignations)
+
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"Software Engineer", "Software Engineer", true},
+		{"software engineer", "Software Engineer", true},
+		{"SWE", "Software Engineer", true},
+		{"sr. software engineer", "Senior Software Engineer", true},
+		{"Vice-President", "VP", true},
+		{"Astronaut", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := taxonomy.Normalize(tt.value)
+		assert.Equal(t, tt.ok, ok, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+
+	assert.Equal(t, "Software Engineer", taxonomy.Names()[0])
+	assert.Len(t, taxonomy.Names(), len(DefaultDesignations))
+}
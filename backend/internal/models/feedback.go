@@ -0,0 +1,85 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Feedback is an attendee's rating of a session. Each attendee has at most
+// one per session, stored under FeedbackID, and may change it for a while
+// after submitting it.
+type Feedback struct {
+	ID          string     `json:"id" firestore:"-"`
+	SessionID   string     `json:"sessionId" firestore:"sessionId"`
+	AttendeeID  string     `json:"attendeeId" firestore:"attendeeId"`
+	Rating      int        `json:"rating" firestore:"rating"`
+	Comment     string     `json:"comment,omitempty" firestore:"comment,omitempty"`
+	SubmittedAt time.Time  `json:"submittedAt" firestore:"submittedAt"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
+
+	// EditableUntil is when the attendee can no longer change the feedback.
+	EditableUntil *time.Time `json:"editableUntil,omitempty" firestore:"-"`
+}
+
+// FeedbackID is the document ID of attendeeID's feedback on sessionID, so a
+// second submission finds the first instead of adding another.
+func FeedbackID(sessionID, attendeeID string) string {
+	return sessionID + "_" + attendeeID
+}
+
+type FeedbackRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+// Editable reports whether the feedback can still be changed at now, given
+// that it may be changed for window after it was first submitted.
+func (f Feedback) Editable(now time.Time, window time.Duration) bool {
+	return now.Before(f.SubmittedAt.Add(window))
+}
+
+// FeedbackSummary aggregates the feedback on one session for admins and
+// speakers. Comments carry no attendee ID so speakers see them anonymously.
+type FeedbackSummary struct {
+	SessionID string  `json:"sessionId"`
+	Title     string  `json:"title"`
+	Count     int     `json:"count"`
+	Average   float64 `json:"average"`
+
+	// Distribution counts the ratings from 1 to 5 at indexes 0 to 4.
+	Distribution [5]int            `json:"distribution"`
+	Comments     []FeedbackComment `json:"comments"`
+}
+
+type FeedbackComment struct {
+	Rating      int       `json:"rating"`
+	Comment     string    `json:"comment"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+// NewFeedbackSummary aggregates feedback, all on session. Comments are
+// newest first; ratings without a comment only count towards the figures.
+func NewFeedbackSummary(session Session, feedback []Feedback) FeedbackSummary {
+	summary := FeedbackSummary{SessionID: session.ID, Title: session.Title, Comments: []FeedbackComment{}}
+
+	total := 0
+	for _, f := range feedback {
+		if f.Rating < 1 || f.Rating > 5 {
+			continue
+		}
+		summary.Count++
+		summary.Distribution[f.Rating-1]++
+		total += f.Rating
+		if f.Comment != "" {
+			summary.Comments = append(summary.Comments, FeedbackComment{Rating: f.Rating, Comment: f.Comment, SubmittedAt: f.SubmittedAt})
+		}
+	}
+	if summary.Count > 0 {
+		summary.Average = float64(total) / float64(summary.Count)
+	}
+
+	sort.SliceStable(summary.Comments, func(i, j int) bool {
+		return summary.Comments[i].SubmittedAt.After(summary.Comments[j].SubmittedAt)
+	})
+	return summary
+}
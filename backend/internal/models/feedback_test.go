@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedback_Editable(t *testing.T) {
+	submitted := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	f := Feedback{SubmittedAt: submitted}
+
+	assert.True(t, f.Editable(submitted.Add(time.Hour), 24*time.Hour))
+	assert.False(t, f.Editable(submitted.Add(24*time.Hour), 24*time.Hour))
+	assert.False(t, f.Editable(submitted, 0), "a zero window makes feedback final")
+}
+
+func TestNewFeedbackSummary(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2026, 3, 14, hour, 0, 0, 0, time.UTC) }
+	session := Session{ID: "keynote", Title: "Keynote"}
+
+	summary := NewFeedbackSummary(session, []Feedback{
+		{AttendeeID: "a", Rating: 5, Comment: "Great", SubmittedAt: at(10)},
+		{AttendeeID: "b", Rating: 4, SubmittedAt: at(11)},
+		{AttendeeID: "c", Rating: 2, Comment: "Too fast", SubmittedAt: at(12)},
+		{AttendeeID: "d", Rating: 5, SubmittedAt: at(13)},
+	})
+
+	assert.Equal(t, "keynote", summary.SessionID)
+	assert.Equal(t, "Keynote", summary.Title)
+	assert.Equal(t, 4, summary.Count)
+	assert.InDelta(t, 4.0, summary.Average, 0.001)
+	assert.Equal(t, [5]int{0, 1, 0, 1, 2}, summary.Distribution)
+	assert.Equal(t, []FeedbackComment{
+		{Rating: 2, Comment: "Too fast", SubmittedAt: at(12)},
+		{Rating: 5, Comment: "Great", SubmittedAt: at(10)},
+	}, summary.Comments)
+
+	empty := NewFeedbackSummary(session, nil)
+	assert.Zero(t, empty.Count)
+	assert.Zero(t, empty.Average)
+	assert.NotNil(t, empty.Comments)
+}
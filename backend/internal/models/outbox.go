@@ -0,0 +1,55 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// OutboxEvent is a domain event waiting in the outbox. It is written in the
+// same transaction as the change it describes and removed once every
+// subscriber has handled it.
+type OutboxEvent struct {
+	ID string `json:"id" firestore:"-"`
+	// EventID identifies the event to subscribers. It is the document ID,
+	// except for events redriven from a dead letter.
+	EventID   string `json:"eventId" firestore:"eventId"`
+	Type      string `json:"type" firestore:"type"`
+	SubjectID string `json:"subjectId" firestore:"subjectId"`
+	// AttendeeID is set for events about an attendee, so they are erased
+	// with the rest of their data
+	AttendeeID string `json:"attendeeId,omitempty" firestore:"attendeeId,omitempty"`
+	// Payload is the JSON of the entity after the change
+	Payload    string    `json:"payload" firestore:"payload"`
+	OccurredAt time.Time `json:"occurredAt" firestore:"occurredAt"`
+
+	// Subscriber, when set, limits delivery to that subscriber
+	Subscriber string `json:"subscriber,omitempty" firestore:"subscriber,omitempty"`
+	// Handled lists the subscribers that have handled the event, so a
+	// retry does not run them again
+	Handled       []string   `json:"handled" firestore:"handled"`
+	Attempts      int        `json:"attempts" firestore:"attempts"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty" firestore:"nextAttemptAt,omitempty"`
+	LastError     string     `json:"lastError,omitempty" firestore:"lastError,omitempty"`
+}
+
+// DeadLetter is an event a subscriber kept failing to handle. It can be
+// redriven to that subscriber once the cause is fixed.
+type DeadLetter struct {
+	ID         string    `json:"id" firestore:"-"`
+	EventID    string    `json:"eventId" firestore:"eventId"`
+	Type       string    `json:"type" firestore:"type"`
+	SubjectID  string    `json:"subjectId" firestore:"subjectId"`
+	AttendeeID string    `json:"attendeeId,omitempty" firestore:"attendeeId,omitempty"`
+	Payload    string    `json:"payload" firestore:"payload"`
+	OccurredAt time.Time `json:"occurredAt" firestore:"occurredAt"`
+	Subscriber string    `json:"subscriber" firestore:"subscriber"`
+	Attempts   int       `json:"attempts" firestore:"attempts"`
+	Error      string    `json:"error" firestore:"error"`
+	FailedAt   time.Time `json:"failedAt" firestore:"failedAt"`
+}
+
+// AboutAttendee reports whether events of the given type have an attendee
+// as their subject.
+func AboutAttendee(eventType string) bool {
+	return strings.HasPrefix(eventType, "attendee.")
+}
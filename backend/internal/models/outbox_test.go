@@ -0,0 +1,14 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAboutAttendee(t *testing.T) {
+	assert.True(t, AboutAttendee(EventAttendeeCreated))
+	assert.True(t, AboutAttendee(EventAttendeeCancelled))
+	assert.False(t, AboutAttendee(EventSessionUpdated))
+	assert.False(t, AboutAttendee(EventSpeakerDeleted))
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Privacy request types and statuses.
+const (
+	PrivacyExport  = "export"
+	PrivacyErasure = "erasure"
+
+	PrivacyPending   = "pending"
+	PrivacyCompleted = "completed"
+
+	// PrivacySourceSelfService marks requests an attendee made through a
+	// magic link; PrivacySourceAdmin marks requests handled by an admin
+	// on the attendee's behalf.
+	PrivacySourceSelfService = "self-service"
+	PrivacySourceAdmin       = "admin"
+)
+
+// PrivacyRequest is the compliance record of a data export or erasure. The
+// email is dropped once an erasure completes; EmailHash still identifies the
+// subject so the record can be matched to a later enquiry.
+type PrivacyRequest struct {
+	ID          string     `json:"id" firestore:"-"`
+	Type        string     `json:"type" firestore:"type"`
+	Status      string     `json:"status" firestore:"status"`
+	Source      string     `json:"source" firestore:"source"`
+	Email       string     `json:"email,omitempty" firestore:"email"`
+	EmailHash   string     `json:"emailHash" firestore:"emailHash"`
+	RequestedAt time.Time  `json:"requestedAt" firestore:"requestedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty" firestore:"completedAt,omitempty"`
+
+	// Records counts the documents exported or erased per collection.
+	Records map[string]int `json:"records,omitempty" firestore:"records,omitempty"`
+}
+
+// DataExport is the archive returned for an export request: every document
+// held about one email address, grouped by collection.
+type DataExport struct {
+	Email       string                      `json:"email"`
+	GeneratedAt time.Time                   `json:"generatedAt"`
+	Collections map[string][]map[string]any `json:"collections"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Moderation statuses of a live question. Only approved questions are shown
+// to the audience.
+const (
+	QuestionPending  = "pending"
+	QuestionApproved = "approved"
+	QuestionHidden   = "hidden"
+)
+
+// LiveQuestion is a question an attendee asked during a session. Moderators
+// always see who asked it; the audience sees the asker's first name unless
+// they asked anonymously.
+type LiveQuestion struct {
+	ID         string     `json:"id" firestore:"-"`
+	SessionID  string     `json:"sessionId" firestore:"sessionId"`
+	AttendeeID string     `json:"attendeeId" firestore:"attendeeId"`
+	AuthorName string     `json:"authorName,omitempty" firestore:"authorName,omitempty"`
+	Anonymous  bool       `json:"anonymous" firestore:"anonymous"`
+	Text       string     `json:"text" firestore:"text"`
+	Status     string     `json:"status" firestore:"status"`
+	Pinned     bool       `json:"pinned" firestore:"pinned"`
+	AnsweredAt *time.Time `json:"answeredAt,omitempty" firestore:"answeredAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt" firestore:"createdAt"`
+
+	// VoterIDs are the attendees who upvoted the question. Adding to the
+	// array is idempotent, so nobody can vote twice.
+	VoterIDs []string `json:"-" firestore:"voterIds"`
+	Votes    int      `json:"votes" firestore:"-"`
+}
+
+type AskQuestionRequest struct {
+	Text      string `json:"text" binding:"required,max=2000"`
+	Anonymous bool   `json:"anonymous"`
+}
+
+// ModerateQuestionRequest changes the fields that are set.
+type ModerateQuestionRequest struct {
+	Status   *string `json:"status" binding:"omitempty,oneof=approved hidden"`
+	Pinned   *bool   `json:"pinned"`
+	Answered *bool   `json:"answered"`
+}
+
+// Answered reports whether a moderator marked the question answered.
+func (q LiveQuestion) Answered() bool {
+	return q.AnsweredAt != nil
+}
+
+// Visible reports whether the audience may see the question.
+func (q LiveQuestion) Visible() bool {
+	return q.Status == QuestionApproved
+}
+
+// VotedBy reports whether attendeeID upvoted the question.
+func (q LiveQuestion) VotedBy(attendeeID string) bool {
+	for _, id := range q.VoterIDs {
+		if id == attendeeID {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicQuestion is what the audience sees of an approved question. It has
+// no attendee or voter IDs.
+type PublicQuestion struct {
+	ID         string     `json:"id"`
+	SessionID  string     `json:"sessionId"`
+	AuthorName string     `json:"authorName,omitempty"`
+	Text       string     `json:"text"`
+	Votes      int        `json:"votes"`
+	Pinned     bool       `json:"pinned"`
+	AnsweredAt *time.Time `json:"answeredAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+
+	// Voted is whether the attendee asking for the feed upvoted it.
+	Voted bool `json:"voted,omitempty"`
+}
+
+func NewPublicQuestion(q LiveQuestion) PublicQuestion {
+	p := PublicQuestion{
+		ID:         q.ID,
+		SessionID:  q.SessionID,
+		Text:       q.Text,
+		Votes:      len(q.VoterIDs),
+		Pinned:     q.Pinned,
+		AnsweredAt: q.AnsweredAt,
+		CreatedAt:  q.CreatedAt,
+	}
+	if !q.Anonymous {
+		p.AuthorName = q.AuthorName
+	}
+	return p
+}
+
+// SortQuestions orders a session's questions for the feed: pinned first,
+// then open questions before answered ones, most votes first and, among
+// equals, oldest first.
+func SortQuestions(questions []LiveQuestion) {
+	sort.SliceStable(questions, func(i, j int) bool {
+		a, b := questions[i], questions[j]
+		if a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		if a.Answered() != b.Answered() {
+			return !a.Answered()
+		}
+		if len(a.VoterIDs) != len(b.VoterIDs) {
+			return len(a.VoterIDs) > len(b.VoterIDs)
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
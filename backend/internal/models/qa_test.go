@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortQuestions(t *testing.T) {
+	at := func(minute int) time.Time { return time.Date(2026, 3, 14, 10, minute, 0, 0, time.UTC) }
+	answered := at(30)
+	questions := []LiveQuestion{
+		{ID: "answered", VoterIDs: []string{"a", "b", "c"}, AnsweredAt: &answered, CreatedAt: at(1)},
+		{ID: "late", VoterIDs: []string{"a"}, CreatedAt: at(5)},
+		{ID: "popular", VoterIDs: []string{"a", "b"}, CreatedAt: at(6)},
+		{ID: "early", VoterIDs: []string{"b"}, CreatedAt: at(2)},
+		{ID: "pinned", CreatedAt: at(9), Pinned: true},
+	}
+
+	SortQuestions(questions)
+
+	var ids []string
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+	}
+	assert.Equal(t, []string{"pinned", "popular", "early", "late", "answered"}, ids)
+}
+
+func TestNewPublicQuestion(t *testing.T) {
+	q := LiveQuestion{
+		ID:         "q1",
+		SessionID:  "keynote",
+		AttendeeID: "attendee-1",
+		AuthorName: "Asha",
+		Text:       "What about evals?",
+		Status:     QuestionApproved,
+		VoterIDs:   []string{"attendee-2", "attendee-3"},
+	}
+
+	public := NewPublicQuestion(q)
+	assert.Equal(t, "Asha", public.AuthorName)
+	assert.Equal(t, 2, public.Votes)
+	assert.Equal(t, "What about evals?", public.Text)
+
+	q.Anonymous = true
+	assert.Empty(t, NewPublicQuestion(q).AuthorName)
+
+	assert.True(t, q.VotedBy("attendee-2"))
+	assert.False(t, q.VotedBy("attendee-1"))
+}
@@ -0,0 +1,303 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Question types.
+const (
+	QuestionText         = "text"
+	QuestionSingleChoice = "single_choice"
+	QuestionMultiChoice  = "multi_choice"
+	QuestionBoolean      = "boolean"
+)
+
+// MaxAnswerLength caps free-text answers when a question sets no MaxLength.
+const MaxAnswerLength = 1000
+
+// questionKey is the shape of Question.Key, which names the answer on the
+// attendee record.
+var questionKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// reservedKeys are the built-in registration fields.
+var reservedKeys = map[string]bool{"name": true, "email": true, "designation": true, "listPublicly": true}
+
+// Question is an extra registration question defined by an admin for the
+// event. Answers are stored on the attendee under Key, which therefore
+// cannot change once attendees may have answered.
+type Question struct {
+	ID       string   `json:"id" firestore:"-"`
+	Key      string   `json:"key" firestore:"key"`
+	Label    string   `json:"label" firestore:"label"`
+	HelpText string   `json:"helpText,omitempty" firestore:"helpText,omitempty"`
+	Type     string   `json:"type" firestore:"type"`
+	Required bool     `json:"required" firestore:"required"`
+	Options  []string `json:"options,omitempty" firestore:"options,omitempty"`
+	Order    int      `json:"order" firestore:"order"`
+
+	// Validation rules. Zero values mean no limit.
+	MinLength     int    `json:"minLength,omitempty" firestore:"minLength,omitempty"`
+	MaxLength     int    `json:"maxLength,omitempty" firestore:"maxLength,omitempty"`
+	Pattern       string `json:"pattern,omitempty" firestore:"pattern,omitempty"`
+	MinSelections int    `json:"minSelections,omitempty" firestore:"minSelections,omitempty"`
+	MaxSelections int    `json:"maxSelections,omitempty" firestore:"maxSelections,omitempty"`
+}
+
+type CreateQuestionRequest struct {
+	Key           string   `json:"key" binding:"required"`
+	Label         string   `json:"label" binding:"required,max=200"`
+	HelpText      string   `json:"helpText" binding:"max=500"`
+	Type          string   `json:"type" binding:"required,oneof=text single_choice multi_choice boolean"`
+	Required      bool     `json:"required"`
+	Options       []string `json:"options"`
+	Order         int      `json:"order"`
+	MinLength     int      `json:"minLength" binding:"min=0"`
+	MaxLength     int      `json:"maxLength" binding:"min=0"`
+	Pattern       string   `json:"pattern" binding:"max=200"`
+	MinSelections int      `json:"minSelections" binding:"min=0"`
+	MaxSelections int      `json:"maxSelections" binding:"min=0"`
+}
+
+// UpdateQuestionRequest replaces a question's definition. Key and type
+// are fixed at creation so existing answers keep their meaning.
+type UpdateQuestionRequest struct {
+	Label         string   `json:"label" binding:"required,max=200"`
+	HelpText      string   `json:"helpText" binding:"max=500"`
+	Required      bool     `json:"required"`
+	Options       []string `json:"options"`
+	Order         int      `json:"order"`
+	MinLength     int      `json:"minLength" binding:"min=0"`
+	MaxLength     int      `json:"maxLength" binding:"min=0"`
+	Pattern       string   `json:"pattern" binding:"max=200"`
+	MinSelections int      `json:"minSelections" binding:"min=0"`
+	MaxSelections int      `json:"maxSelections" binding:"min=0"`
+}
+
+// Check reports whether the question definition is usable.
+func (q Question) Check() error {
+	if !questionKey.MatchString(q.Key) || reservedKeys[q.Key] {
+		return fmt.Errorf("key must be 1-40 lower-case letters, digits or underscores, start with a letter and not be a built-in field")
+	}
+	if strings.TrimSpace(q.Label) == "" {
+		return fmt.Errorf("label is required")
+	}
+
+	choice := q.Type == QuestionSingleChoice || q.Type == QuestionMultiChoice
+	switch {
+	case choice && len(q.Options) < 2:
+		return fmt.Errorf("choice questions need at least two options")
+	case !choice && len(q.Options) > 0:
+		return fmt.Errorf("only choice questions have options")
+	}
+	seen := make(map[string]bool)
+	for _, option := range q.Options {
+		if strings.TrimSpace(option) == "" || utf8.RuneCountInString(option) > 200 {
+			return fmt.Errorf("options must be 1-200 characters")
+		}
+		if seen[option] {
+			return fmt.Errorf("option %q is listed twice", option)
+		}
+		seen[option] = true
+	}
+
+	if q.Type != QuestionText && (q.MinLength > 0 || q.MaxLength > 0 || q.Pattern != "") {
+		return fmt.Errorf("minLength, maxLength and pattern only apply to text questions")
+	}
+	if q.MaxLength > MaxAnswerLength || (q.MaxLength > 0 && q.MinLength > q.MaxLength) {
+		return fmt.Errorf("length limits must satisfy minLength <= maxLength <= %d", MaxAnswerLength)
+	}
+	if q.Pattern != "" {
+		if _, err := regexp.Compile(q.Pattern); err != nil {
+			return fmt.Errorf("pattern is not a valid regular expression")
+		}
+	}
+
+	if q.Type != QuestionMultiChoice && (q.MinSelections > 0 || q.MaxSelections > 0) {
+		return fmt.Errorf("minSelections and maxSelections only apply to multi-choice questions")
+	}
+	if q.MaxSelections > 0 && q.MinSelections > q.MaxSelections {
+		return fmt.Errorf("minSelections must not exceed maxSelections")
+	}
+	if q.MinSelections > len(q.Options) {
+		return fmt.Errorf("minSelections exceeds the number of options")
+	}
+	return nil
+}
+
+// AnswerError explains why an answer was rejected.
+type AnswerError struct {
+	Code    string
+	Message string
+}
+
+func (e *AnswerError) Error() string { return e.Message }
+
+// Validate checks an answer decoded from JSON and returns it in the form it
+// is stored: trimmed text, an option, a list of options or a bool. A nil
+// result with a nil error means the optional question was left blank.
+func (q Question) Validate(answer any) (any, error) {
+	if isBlank(answer) {
+		if q.Required {
+			return nil, &AnswerError{Code: "required", Message: "is required"}
+		}
+		return nil, nil
+	}
+
+	switch q.Type {
+	case QuestionText:
+		text, ok := answer.(string)
+		if !ok {
+			return nil, &AnswerError{Code: "type", Message: "must be a string"}
+		}
+		text = strings.TrimSpace(text)
+		length := utf8.RuneCountInString(text)
+		maxLength := q.MaxLength
+		if maxLength == 0 {
+			maxLength = MaxAnswerLength
+		}
+		if length < q.MinLength {
+			return nil, &AnswerError{Code: "min", Message: fmt.Sprintf("must be at least %d characters", q.MinLength)}
+		}
+		if length > maxLength {
+			return nil, &AnswerError{Code: "max", Message: fmt.Sprintf("must be at most %d characters", maxLength)}
+		}
+		if q.Pattern != "" {
+			if re, err := regexp.Compile(q.Pattern); err != nil || !re.MatchString(text) {
+				return nil, &AnswerError{Code: "pattern", Message: "is not in the expected format"}
+			}
+		}
+		return text, nil
+
+	case QuestionSingleChoice:
+		choice, ok := answer.(string)
+		if !ok {
+			return nil, &AnswerError{Code: "type", Message: "must be a string"}
+		}
+		if !q.hasOption(choice) {
+			return nil, &AnswerError{Code: "oneof", Message: "must be one of the options"}
+		}
+		return choice, nil
+
+	case QuestionMultiChoice:
+		list, ok := answer.([]any)
+		if !ok {
+			return nil, &AnswerError{Code: "type", Message: "must be a list"}
+		}
+		choices := make([]string, 0, len(list))
+		seen := make(map[string]bool)
+		for _, item := range list {
+			choice, ok := item.(string)
+			if !ok || !q.hasOption(choice) {
+				return nil, &AnswerError{Code: "oneof", Message: "must only contain the options"}
+			}
+			if !seen[choice] {
+				seen[choice] = true
+				choices = append(choices, choice)
+			}
+		}
+		if len(choices) < q.MinSelections {
+			return nil, &AnswerError{Code: "min", Message: fmt.Sprintf("must have at least %d selections", q.MinSelections)}
+		}
+		if q.MaxSelections > 0 && len(choices) > q.MaxSelections {
+			return nil, &AnswerError{Code: "max", Message: fmt.Sprintf("must have at most %d selections", q.MaxSelections)}
+		}
+		return choices, nil
+
+	case QuestionBoolean:
+		value, ok := answer.(bool)
+		if !ok {
+			return nil, &AnswerError{Code: "type", Message: "must be a boolean"}
+		}
+		return value, nil
+	}
+
+	return nil, &AnswerError{Code: "invalid", Message: "is invalid"}
+}
+
+func (q Question) hasOption(choice string) bool {
+	for _, option := range q.Options {
+		if option == choice {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlank reports whether an answer counts as not given.
+func isBlank(answer any) bool {
+	switch v := answer.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// QuestionStats summarizes the answers to one question across confirmed
+// registrations. Counts are per option for choice and boolean questions.
+type QuestionStats struct {
+	Key      string        `json:"key"`
+	Label    string        `json:"label"`
+	Type     string        `json:"type"`
+	Answered int           `json:"answered"`
+	Counts   []OptionCount `json:"counts,omitempty"`
+}
+
+type OptionCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// NewQuestionStats tallies answers, one map per attendee, for each question.
+// Choice options and boolean values are listed even when nobody chose them.
+func NewQuestionStats(questions []Question, answers []map[string]any) []QuestionStats {
+	stats := make([]QuestionStats, 0, len(questions))
+	for _, q := range questions {
+		s := QuestionStats{Key: q.Key, Label: q.Label, Type: q.Type}
+
+		var values []string
+		switch q.Type {
+		case QuestionSingleChoice, QuestionMultiChoice:
+			values = q.Options
+		case QuestionBoolean:
+			values = []string{"true", "false"}
+		}
+		counts := make(map[string]int)
+
+		for _, a := range answers {
+			answer, ok := a[q.Key]
+			if !ok || isBlank(answer) {
+				continue
+			}
+			s.Answered++
+			switch v := answer.(type) {
+			case string:
+				counts[v]++
+			case bool:
+				counts[fmt.Sprint(v)]++
+			case []any:
+				for _, item := range v {
+					if choice, ok := item.(string); ok {
+						counts[choice]++
+					}
+				}
+			case []string:
+				for _, choice := range v {
+					counts[choice]++
+				}
+			}
+		}
+
+		for _, value := range values {
+			s.Counts = append(s.Counts, OptionCount{Value: value, Count: counts[value]})
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuestion_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		question Question
+		wantErr  bool
+	}{
+		{"text", Question{Key: "company", Label: "Company", Type: QuestionText, MaxLength: 100}, false},
+		{"single choice", Question{Key: "tshirt", Label: "T-shirt", Type: QuestionSingleChoice, Options: []string{"S", "M", "L"}}, false},
+		{"multi choice", Question{Key: "diet", Label: "Dietary needs", Type: QuestionMultiChoice, Options: []string{"Vegan", "Halal"}, MaxSelections: 2}, false},
+		{"boolean", Question{Key: "newsletter", Label: "Newsletter?", Type: QuestionBoolean}, false},
+		{"bad key", Question{Key: "Company Name", Label: "Company", Type: QuestionText}, true},
+		{"reserved key", Question{Key: "email", Label: "Email again", Type: QuestionText}, true},
+		{"blank label", Question{Key: "company", Label: " ", Type: QuestionText}, true},
+		{"choice without options", Question{Key: "tshirt", Label: "T-shirt", Type: QuestionSingleChoice, Options: []string{"S"}}, true},
+		{"duplicate option", Question{Key: "tshirt", Label: "T-shirt", Type: QuestionSingleChoice, Options: []string{"S", "S"}}, true},
+		{"options on text", Question{Key: "company", Label: "Company", Type: QuestionText, Options: []string{"a", "b"}}, true},
+		{"length on choice", Question{Key: "tshirt", Label: "T-shirt", Type: QuestionSingleChoice, Options: []string{"S", "M"}, MaxLength: 3}, true},
+		{"min above max", Question{Key: "company", Label: "Company", Type: QuestionText, MinLength: 10, MaxLength: 5}, true},
+		{"max too long", Question{Key: "company", Label: "Company", Type: QuestionText, MaxLength: MaxAnswerLength + 1}, true},
+		{"bad pattern", Question{Key: "company", Label: "Company", Type: QuestionText, Pattern: "("}, true},
+		{"selections on single", Question{Key: "tshirt", Label: "T-shirt", Type: QuestionSingleChoice, Options: []string{"S", "M"}, MaxSelections: 1}, true},
+		{"min selections above options", Question{Key: "diet", Label: "Diet", Type: QuestionMultiChoice, Options: []string{"a", "b"}, MinSelections: 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.question.Check()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQuestion_Validate(t *testing.T) {
+	text := Question{Key: "years", Type: QuestionText, Required: true, MaxLength: 5, Pattern: `^\d+$`}
+	single := Question{Key: "tshirt", Type: QuestionSingleChoice, Options: []string{"S", "M"}}
+	multi := Question{Key: "diet", Type: QuestionMultiChoice, Options: []string{"Vegan", "Halal", "Nut-free"}, MaxSelections: 2}
+	boolean := Question{Key: "newsletter", Type: QuestionBoolean, Required: true}
+
+	tests := []struct {
+		name     string
+		question Question
+		answer   any
+		want     any
+		wantCode string
+	}{
+		{"text", text, " 12 ", "12", ""},
+		{"text required", text, "  ", nil, "required"},
+		{"text pattern", text, "twelve", nil, "max"},
+		{"text pattern short", text, "ten", nil, "pattern"},
+		{"text type", text, 12.0, nil, "type"},
+		{"single", single, "M", "M", ""},
+		{"single optional blank", single, nil, nil, ""},
+		{"single unknown option", single, "XL", nil, "oneof"},
+		{"multi", multi, []any{"Vegan", "Vegan", "Halal"}, []string{"Vegan", "Halal"}, ""},
+		{"multi too many", multi, []any{"Vegan", "Halal", "Nut-free"}, nil, "max"},
+		{"multi unknown option", multi, []any{"Keto"}, nil, "oneof"},
+		{"multi type", multi, "Vegan", nil, "type"},
+		{"boolean false counts as answered", boolean, false, false, ""},
+		{"boolean required", boolean, nil, nil, "required"},
+		{"boolean type", boolean, "yes", nil, "type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.question.Validate(tt.answer)
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			var answerErr *AnswerError
+			if assert.ErrorAs(t, err, &answerErr) {
+				assert.Equal(t, tt.wantCode, answerErr.Code)
+			}
+		})
+	}
+
+	long := Question{Key: "bio", Type: QuestionText}
+	_, err := long.Validate(strings.Repeat("a", MaxAnswerLength+1))
+	assert.Error(t, err, "text answers are capped without an explicit maxLength")
+}
+
+func TestNewQuestionStats(t *testing.T) {
+	questions := []Question{
+		{Key: "tshirt", Label: "T-shirt", Type: QuestionSingleChoice, Options: []string{"S", "M"}},
+		{Key: "diet", Label: "Diet", Type: QuestionMultiChoice, Options: []string{"Vegan", "Halal"}},
+		{Key: "newsletter", Label: "Newsletter", Type: QuestionBoolean},
+		{Key: "company", Label: "Company", Type: QuestionText},
+	}
+	answers := []map[string]any{
+		{"tshirt": "M", "diet": []any{"Vegan", "Halal"}, "newsletter": true, "company": "Acme"},
+		{"tshirt": "M", "diet": []any{"Vegan"}, "newsletter": false},
+		nil,
+	}
+
+	stats := NewQuestionStats(questions, answers)
+	assert.Equal(t, []QuestionStats{
+		{Key: "tshirt", Label: "T-shirt", Type: QuestionSingleChoice, Answered: 2, Counts: []OptionCount{{"S", 0}, {"M", 2}}},
+		{Key: "diet", Label: "Diet", Type: QuestionMultiChoice, Answered: 2, Counts: []OptionCount{{"Vegan", 2}, {"Halal", 1}}},
+		{Key: "newsletter", Label: "Newsletter", Type: QuestionBoolean, Answered: 2, Counts: []OptionCount{{"true", 1}, {"false", 1}}},
+		{Key: "company", Label: "Company", Type: QuestionText, Answered: 1},
+	}, stats)
+}
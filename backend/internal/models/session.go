@@ -1,5 +1,11 @@
 package models
 
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
 type Session struct {
 	ID          string   `json:"id" firestore:"-"`
 	Title       string   `json:"title" firestore:"title"`
@@ -7,21 +13,162 @@ type Session struct {
 	Time        string   `json:"time" firestore:"time"`
 	Duration    string   `json:"duration" firestore:"duration"`
 	SpeakerIDs  []string `json:"speakerIds" firestore:"speakerIds"`
+
+	// StartsAt and EndsAt place the session on the agenda. Time and
+	// Duration are free text, shown as entered.
+	StartsAt *time.Time `json:"startsAt,omitempty" firestore:"startsAt,omitempty"`
+	EndsAt   *time.Time `json:"endsAt,omitempty" firestore:"endsAt,omitempty"`
+	TrackID  string     `json:"trackId,omitempty" firestore:"trackId,omitempty"`
+	RoomID   string     `json:"roomId,omitempty" firestore:"roomId,omitempty"`
+	Tags     []string   `json:"tags,omitempty" firestore:"tags,omitempty"`
+
+	// Speakers is filled in with the speakers' records when requested with
+	// expand=speakers.
+	Speakers []Speaker `json:"speakers,omitempty" firestore:"-"`
 }
 
 type CreateSessionRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description"`
-	Time        string   `json:"time"`
-	Duration    string   `json:"duration"`
-	SpeakerIDs  []string `json:"speakerIds"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	Time        string     `json:"time"`
+	Duration    string     `json:"duration"`
+	SpeakerIDs  []string   `json:"speakerIds"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	TrackID     string     `json:"trackId"`
+	RoomID      string     `json:"roomId"`
+	Tags        []string   `json:"tags" binding:"max=20,dive,required,max=50"`
 }
 
+// UpdateSessionRequest changes the fields that are set. TrackID and RoomID
+// are pointers so that an empty string can take the session out of its
+// track or room.
 type UpdateSessionRequest struct {
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	Time        string   `json:"time"`
-	Duration    string   `json:"duration"`
-	SpeakerIDs  []string `json:"speakerIds"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Time        string     `json:"time"`
+	Duration    string     `json:"duration"`
+	SpeakerIDs  []string   `json:"speakerIds"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	TrackID     *string    `json:"trackId"`
+	RoomID      *string    `json:"roomId"`
+	Tags        []string   `json:"tags" binding:"max=20,dive,required,max=50"`
+}
+
+// Scheduled reports whether the session has both a start and an end time,
+// which it needs to be given a room.
+func (s Session) Scheduled() bool {
+	return s.StartsAt != nil && s.EndsAt != nil
+}
+
+// ValidTimes reports whether the session ends after it starts, when both
+// are set.
+func (s Session) ValidTimes() bool {
+	return s.StartsAt == nil || s.EndsAt == nil || s.EndsAt.After(*s.StartsAt)
+}
+
+// SessionFilter narrows the agenda to sessions starting in [From, To), in
+// one of TrackIDs, in one of RoomIDs, with one of Tags and given by one of
+// SpeakerIDs. Tags ignore case. Zero values match everything.
+type SessionFilter struct {
+	From       time.Time
+	To         time.Time
+	TrackIDs   []string
+	RoomIDs    []string
+	Tags       []string
+	SpeakerIDs []string
 }
 
+// Match reports whether the session passes the filter. Sessions without a
+// start time never match a date range.
+func (f SessionFilter) Match(s Session) bool {
+	if !f.From.IsZero() || !f.To.IsZero() {
+		if s.StartsAt == nil {
+			return false
+		}
+		if !f.From.IsZero() && s.StartsAt.Before(f.From) {
+			return false
+		}
+		if !f.To.IsZero() && !s.StartsAt.Before(f.To) {
+			return false
+		}
+	}
+	if len(f.TrackIDs) > 0 && !containsAny(f.TrackIDs, []string{s.TrackID}, equal) {
+		return false
+	}
+	if len(f.RoomIDs) > 0 && !containsAny(f.RoomIDs, []string{s.RoomID}, equal) {
+		return false
+	}
+	if len(f.Tags) > 0 && !containsAny(f.Tags, s.Tags, strings.EqualFold) {
+		return false
+	}
+	if len(f.SpeakerIDs) > 0 && !containsAny(f.SpeakerIDs, s.SpeakerIDs, equal) {
+		return false
+	}
+	return true
+}
+
+func equal(a, b string) bool { return a == b }
+
+func containsAny(set, values []string, equal func(a, b string) bool) bool {
+	for _, v := range values {
+		for _, s := range set {
+			if equal(s, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SortSessions orders sessions by start time, latest first when desc is
+// set. Sessions without a start time come last; ties are broken by title.
+func SortSessions(sessions []Session, desc bool) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		a, b := sessions[i].StartsAt, sessions[j].StartsAt
+		switch {
+		case a == nil && b == nil:
+			return sessions[i].Title < sessions[j].Title
+		case a == nil || b == nil:
+			return b == nil
+		case !a.Equal(*b):
+			return a.Before(*b) != desc
+		}
+		return sessions[i].Title < sessions[j].Title
+	})
+}
+
+// Apply copies the fields set in the request onto session.
+func (r UpdateSessionRequest) Apply(session *Session) {
+	if r.Title != "" {
+		session.Title = r.Title
+	}
+	if r.Description != "" {
+		session.Description = r.Description
+	}
+	if r.Time != "" {
+		session.Time = r.Time
+	}
+	if r.Duration != "" {
+		session.Duration = r.Duration
+	}
+	if r.SpeakerIDs != nil {
+		session.SpeakerIDs = r.SpeakerIDs
+	}
+	if r.StartsAt != nil {
+		session.StartsAt = r.StartsAt
+	}
+	if r.EndsAt != nil {
+		session.EndsAt = r.EndsAt
+	}
+	if r.TrackID != nil {
+		session.TrackID = *r.TrackID
+	}
+	if r.RoomID != nil {
+		session.RoomID = *r.RoomID
+	}
+	if r.Tags != nil {
+		session.Tags = r.Tags
+	}
+}
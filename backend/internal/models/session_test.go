@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func startingAt(title, s string) Session {
+	session := Session{Title: title}
+	if s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		session.StartsAt = &t
+	}
+	return session
+}
+
+func TestSessionFilter_Match(t *testing.T) {
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	filter := SessionFilter{From: day, To: day.AddDate(0, 0, 1)}
+
+	assert.True(t, filter.Match(startingAt("a", "2026-03-14T09:00:00Z")))
+	assert.False(t, filter.Match(startingAt("a", "2026-03-15T00:00:00Z")), "To is exclusive")
+	assert.False(t, filter.Match(startingAt("a", "")), "unscheduled sessions have no day")
+	assert.True(t, SessionFilter{}.Match(startingAt("a", "")))
+
+	session := Session{TrackID: "t1", RoomID: "r1", Tags: []string{"LLM", "hands-on"}, SpeakerIDs: []string{"s1", "s2"}}
+	assert.True(t, SessionFilter{TrackIDs: []string{"t2", "t1"}}.Match(session))
+	assert.False(t, SessionFilter{TrackIDs: []string{"t2"}}.Match(session))
+	assert.True(t, SessionFilter{RoomIDs: []string{"r1"}}.Match(session))
+	assert.False(t, SessionFilter{RoomIDs: []string{"r2"}}.Match(session))
+	assert.True(t, SessionFilter{Tags: []string{"keynote", "llm"}}.Match(session), "any tag matches")
+	assert.False(t, SessionFilter{Tags: []string{"keynote"}}.Match(session))
+	assert.True(t, SessionFilter{SpeakerIDs: []string{"s2"}}.Match(session))
+	assert.False(t, SessionFilter{SpeakerIDs: []string{"S2"}}.Match(session), "speaker IDs are exact")
+}
+
+func TestSortSessions(t *testing.T) {
+	sessions := []Session{
+		startingAt("Unscheduled", ""),
+		startingAt("Lunch", "2026-03-14T12:00:00Z"),
+		startingAt("Keynote", "2026-03-14T09:00:00Z"),
+		startingAt("Breakout B", "2026-03-14T10:00:00Z"),
+		startingAt("Breakout A", "2026-03-14T10:00:00Z"),
+	}
+	titles := func() []string {
+		var out []string
+		for _, s := range sessions {
+			out = append(out, s.Title)
+		}
+		return out
+	}
+
+	SortSessions(sessions, false)
+	assert.Equal(t, []string{"Keynote", "Breakout A", "Breakout B", "Lunch", "Unscheduled"}, titles())
+
+	SortSessions(sessions, true)
+	assert.Equal(t, []string{"Lunch", "Breakout A", "Breakout B", "Keynote", "Unscheduled"}, titles())
+}
+
+func TestSession_ValidTimes(t *testing.T) {
+	start := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	assert.True(t, Session{}.ValidTimes())
+	assert.True(t, Session{StartsAt: &start, EndsAt: &end}.ValidTimes())
+	assert.False(t, Session{StartsAt: &end, EndsAt: &start}.ValidTimes())
+	assert.False(t, Session{StartsAt: &start, EndsAt: &start}.ValidTimes())
+}
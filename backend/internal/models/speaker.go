@@ -22,3 +22,18 @@ type UpdateSpeakerRequest struct {
 	Sessions []string `json:"sessions"`
 }
 
+// Apply copies the fields set in the request onto speaker.
+func (r UpdateSpeakerRequest) Apply(speaker *Speaker) {
+	if r.Name != "" {
+		speaker.Name = r.Name
+	}
+	if r.Bio != "" {
+		speaker.Bio = r.Bio
+	}
+	if r.Avatar != "" {
+		speaker.Avatar = r.Avatar
+	}
+	if r.Sessions != nil {
+		speaker.Sessions = r.Sessions
+	}
+}
@@ -0,0 +1,245 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Timeline intervals.
+const (
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+)
+
+// MaxTimelineBuckets bounds a timeline, so a wide range at hourly
+// resolution cannot produce an unbounded response.
+const MaxTimelineBuckets = 24 * 93
+
+// StatsFilter narrows registration stats to registrations made in
+// [From, To) with one of Designations. Zero values match everything.
+type StatsFilter struct {
+	From         time.Time
+	To           time.Time
+	Designations []string
+}
+
+// Match reports whether the registration falls within the filter.
+func (f StatsFilter) Match(a Attendee) bool {
+	if !f.From.IsZero() && a.RegisteredAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !a.RegisteredAt.Before(f.To) {
+		return false
+	}
+	return f.matchDesignation(a)
+}
+
+func (f StatsFilter) matchDesignation(a Attendee) bool {
+	if len(f.Designations) == 0 {
+		return true
+	}
+	for _, designation := range f.Designations {
+		if a.Designation == designation {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the registrations that match the filter.
+func (f StatsFilter) Apply(attendees []Attendee) []Attendee {
+	matched := make([]Attendee, 0, len(attendees))
+	for _, a := range attendees {
+		if f.Match(a) {
+			matched = append(matched, a)
+		}
+	}
+	return matched
+}
+
+// NewDesignationStats counts registrations that still count, by designation,
+// largest first.
+func NewDesignationStats(attendees []Attendee) []DesignationStats {
+	counts := make(map[string]int)
+	for _, a := range attendees {
+		if !a.Cancelled() {
+			counts[a.Designation]++
+		}
+	}
+
+	stats := make([]DesignationStats, 0, len(counts))
+	for designation, count := range counts {
+		stats = append(stats, DesignationStats{Designation: designation, Count: count})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Designation < stats[j].Designation
+	})
+	return stats
+}
+
+// Funnel follows registrations from sign-up to attendance. CheckInRate is
+// the share of confirmed registrations that were checked in.
+type Funnel struct {
+	Registered  int     `json:"registered"`
+	Pending     int     `json:"pending"`
+	Confirmed   int     `json:"confirmed"`
+	Cancelled   int     `json:"cancelled"`
+	CheckedIn   int     `json:"checkedIn"`
+	CheckInRate float64 `json:"checkInRate"`
+}
+
+func NewFunnel(attendees []Attendee) Funnel {
+	var f Funnel
+	for _, a := range attendees {
+		f.Registered++
+		switch {
+		case a.Cancelled():
+			f.Cancelled++
+		case a.Confirmed():
+			f.Confirmed++
+			if a.CheckedIn() {
+				f.CheckedIn++
+			}
+		default:
+			f.Pending++
+		}
+	}
+	if f.Confirmed > 0 {
+		f.CheckInRate = float64(f.CheckedIn) / float64(f.Confirmed)
+	}
+	return f
+}
+
+// TimelineBucket counts the registrations made in one hour or day.
+// Cancelled is how many of them have since been cancelled, and Cumulative
+// the running total of registrations that still count, including those
+// made before the timeline starts.
+type TimelineBucket struct {
+	Start         time.Time `json:"start"`
+	Registrations int       `json:"registrations"`
+	Cancelled     int       `json:"cancelled"`
+	Cumulative    int       `json:"cumulative"`
+}
+
+type Timeline struct {
+	Interval string           `json:"interval"`
+	TimeZone string           `json:"timeZone"`
+	Buckets  []TimelineBucket `json:"buckets"`
+}
+
+// NewTimeline buckets the registrations matching filter by hour or by day in
+// loc. Buckets run from the filter's From, or the first registration, to its
+// To, or the last registration, with empty buckets included so the series
+// can be charted directly. Records without a registration time are left out.
+func NewTimeline(attendees []Attendee, filter StatsFilter, interval string, loc *time.Location) (Timeline, error) {
+	timeline := Timeline{Interval: interval, TimeZone: loc.String(), Buckets: []TimelineBucket{}}
+
+	var bucketStart func(time.Time) time.Time
+	var next func(time.Time) time.Time
+	switch interval {
+	case IntervalHour:
+		bucketStart = func(t time.Time) time.Time { return hourStart(t, loc) }
+		next = func(start time.Time) time.Time { return hourStart(start.Add(time.Hour), loc) }
+	case IntervalDay:
+		bucketStart = func(t time.Time) time.Time { return dayStart(t, loc) }
+		next = func(start time.Time) time.Time {
+			y, m, d := start.In(loc).Date()
+			return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		}
+	default:
+		return timeline, fmt.Errorf("interval must be %s or %s", IntervalHour, IntervalDay)
+	}
+
+	var first, last time.Time
+	var matched []Attendee
+	for _, a := range attendees {
+		if a.RegisteredAt.IsZero() || !filter.matchDesignation(a) {
+			continue
+		}
+		matched = append(matched, a)
+		if !filter.Match(a) {
+			continue
+		}
+		if first.IsZero() || a.RegisteredAt.Before(first) {
+			first = a.RegisteredAt
+		}
+		if a.RegisteredAt.After(last) {
+			last = a.RegisteredAt
+		}
+	}
+
+	from, to := filter.From, filter.To
+	if from.IsZero() {
+		from = first
+	}
+	if to.IsZero() && !last.IsZero() {
+		to = last.Add(time.Nanosecond)
+	}
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return timeline, nil
+	}
+
+	var starts []time.Time
+	for start := bucketStart(from); start.Before(to); start = next(start) {
+		if len(starts) == MaxTimelineBuckets {
+			return timeline, fmt.Errorf("the range spans more than %d %s buckets; narrow it or use a longer interval", MaxTimelineBuckets, interval)
+		}
+		starts = append(starts, start)
+	}
+
+	buckets := make([]TimelineBucket, len(starts))
+	index := make(map[int64]int, len(starts))
+	for i, start := range starts {
+		buckets[i].Start = start
+		index[start.UnixNano()] = i
+	}
+
+	base := 0
+	for _, a := range matched {
+		if a.RegisteredAt.Before(starts[0]) {
+			if !a.Cancelled() {
+				base++
+			}
+			continue
+		}
+		if !filter.Match(a) {
+			continue
+		}
+		i, ok := index[bucketStart(a.RegisteredAt).UnixNano()]
+		if !ok {
+			continue
+		}
+		buckets[i].Registrations++
+		if a.Cancelled() {
+			buckets[i].Cancelled++
+		}
+	}
+
+	total := base
+	for i := range buckets {
+		total += buckets[i].Registrations - buckets[i].Cancelled
+		buckets[i].Cumulative = total
+	}
+	timeline.Buckets = buckets
+	return timeline, nil
+}
+
+// hourStart returns the start of the local hour containing t. It works on
+// the instant rather than the wall clock, so the repeated hour when clocks
+// go back is two buckets and zones offset by half an hour are handled.
+func hourStart(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	into := time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second +
+		time.Duration(local.Nanosecond())
+	return local.Add(-into)
+}
+
+func dayStart(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
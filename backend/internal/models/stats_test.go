@@ -0,0 +1,142 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registeredAt(s string) Attendee {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return Attendee{Designation: "Engineer", RegisteredAt: t}
+}
+
+func TestStatsFilter_Match(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	filter := StatsFilter{From: from, To: from.AddDate(0, 0, 1), Designations: []string{"Engineer", "Manager"}}
+
+	assert.True(t, filter.Match(registeredAt("2026-03-01T00:00:00Z")))
+	assert.True(t, filter.Match(registeredAt("2026-03-01T23:59:59Z")))
+	assert.False(t, filter.Match(registeredAt("2026-02-28T23:59:59Z")))
+	assert.False(t, filter.Match(registeredAt("2026-03-02T00:00:00Z")), "To is exclusive")
+
+	other := registeredAt("2026-03-01T12:00:00Z")
+	other.Designation = "Designer"
+	assert.False(t, filter.Match(other))
+	assert.True(t, StatsFilter{}.Match(other))
+}
+
+func TestNewDesignationStats(t *testing.T) {
+	attendees := []Attendee{
+		{Designation: "Manager"},
+		{Designation: "Engineer"},
+		{Designation: "Engineer"},
+		{Designation: "Designer", Status: StatusCancelled},
+	}
+
+	assert.Equal(t, []DesignationStats{
+		{Designation: "Engineer", Count: 2},
+		{Designation: "Manager", Count: 1},
+	}, NewDesignationStats(attendees))
+	assert.Empty(t, NewDesignationStats(nil))
+}
+
+func TestNewFunnel(t *testing.T) {
+	now := time.Now()
+	attendees := []Attendee{
+		{},
+		{Status: StatusConfirmed, CheckedInAt: &now},
+		{Status: StatusConfirmed, CheckedInAt: &now},
+		{Status: StatusConfirmed},
+		{Status: StatusPending},
+		{Status: StatusCancelled, CheckedInAt: &now},
+	}
+
+	assert.Equal(t, Funnel{
+		Registered:  6,
+		Pending:     1,
+		Confirmed:   4,
+		Cancelled:   1,
+		CheckedIn:   2,
+		CheckInRate: 0.5,
+	}, NewFunnel(attendees))
+	assert.Equal(t, Funnel{}, NewFunnel(nil))
+}
+
+func TestNewTimeline_Daily(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	cancelled := registeredAt("2026-03-02T10:00:00Z")
+	cancelled.Status = StatusCancelled
+	attendees := []Attendee{
+		registeredAt("2026-02-27T10:00:00Z"),
+		registeredAt("2026-03-01T19:00:00Z"), // 00:30 on 2 March in Kolkata
+		cancelled,
+		registeredAt("2026-03-04T04:00:00Z"),
+		{Designation: "Engineer"}, // no registration time
+	}
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, kolkata)
+
+	timeline, err := NewTimeline(attendees, StatsFilter{From: from}, IntervalDay, kolkata)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Asia/Kolkata", timeline.TimeZone)
+	require.Len(t, timeline.Buckets, 3)
+	assert.Equal(t, TimelineBucket{Start: from, Registrations: 2, Cancelled: 1, Cumulative: 2}, timeline.Buckets[0])
+	assert.Equal(t, TimelineBucket{Start: from.AddDate(0, 0, 1), Cumulative: 2}, timeline.Buckets[1], "empty days are included")
+	assert.Equal(t, TimelineBucket{Start: from.AddDate(0, 0, 2), Registrations: 1, Cumulative: 3}, timeline.Buckets[2])
+}
+
+func TestNewTimeline_Hourly(t *testing.T) {
+	attendees := []Attendee{
+		registeredAt("2026-03-01T09:05:00Z"),
+		registeredAt("2026-03-01T09:55:00Z"),
+		registeredAt("2026-03-01T11:30:00Z"),
+	}
+
+	timeline, err := NewTimeline(attendees, StatsFilter{}, IntervalHour, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, timeline.Buckets, 3)
+	assert.Equal(t, []int{2, 0, 1}, []int{timeline.Buckets[0].Registrations, timeline.Buckets[1].Registrations, timeline.Buckets[2].Registrations})
+	assert.Equal(t, 3, timeline.Buckets[2].Cumulative)
+}
+
+func TestNewTimeline_DaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Clocks went back from 03:00 to 02:00 local time on 25 October 2026
+	attendees := []Attendee{
+		registeredAt("2026-10-25T00:30:00Z"), // 02:30 CEST
+		registeredAt("2026-10-25T01:30:00Z"), // 02:30 CET
+	}
+	timeline, err := NewTimeline(attendees, StatsFilter{}, IntervalHour, berlin)
+	require.NoError(t, err)
+	require.Len(t, timeline.Buckets, 2, "the repeated hour is two buckets")
+	assert.Equal(t, 1, timeline.Buckets[0].Registrations)
+	assert.Equal(t, 1, timeline.Buckets[1].Registrations)
+
+	daily, err := NewTimeline(attendees, StatsFilter{}, IntervalDay, berlin)
+	require.NoError(t, err)
+	require.Len(t, daily.Buckets, 1)
+	assert.Equal(t, 2, daily.Buckets[0].Registrations)
+}
+
+func TestNewTimeline_Limits(t *testing.T) {
+	_, err := NewTimeline(nil, StatsFilter{}, "week", time.UTC)
+	assert.Error(t, err)
+
+	timeline, err := NewTimeline(nil, StatsFilter{}, IntervalDay, time.UTC)
+	require.NoError(t, err)
+	assert.Empty(t, timeline.Buckets)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = NewTimeline(nil, StatsFilter{From: from, To: from.AddDate(1, 0, 0)}, IntervalHour, time.UTC)
+	assert.Error(t, err, "a year of hours is too many buckets")
+}
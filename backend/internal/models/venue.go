@@ -0,0 +1,205 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Venue is a place the workshop is held. Its rooms are stored separately
+// and listed with it.
+type Venue struct {
+	ID         string `json:"id" firestore:"-"`
+	Name       string `json:"name" firestore:"name"`
+	Address    string `json:"address" firestore:"address"`
+	Directions string `json:"directions,omitempty" firestore:"directions,omitempty"`
+	// MapURL is a map embed URL shown on the location page
+	MapURL string `json:"mapUrl,omitempty" firestore:"mapUrl,omitempty"`
+
+	Rooms []Room `json:"rooms,omitempty" firestore:"-"`
+}
+
+type VenueRequest struct {
+	Name       string `json:"name" binding:"required,max=200"`
+	Address    string `json:"address" binding:"max=500"`
+	Directions string `json:"directions" binding:"max=2000"`
+	MapURL     string `json:"mapUrl" binding:"omitempty,url,startswith=https://,max=2000"`
+}
+
+// Room is a room at a venue that sessions are held in. A capacity of zero
+// means it is not known.
+type Room struct {
+	ID            string `json:"id" firestore:"-"`
+	VenueID       string `json:"venueId" firestore:"venueId"`
+	Name          string `json:"name" firestore:"name"`
+	Capacity      int    `json:"capacity" firestore:"capacity"`
+	Accessibility string `json:"accessibility,omitempty" firestore:"accessibility,omitempty"`
+}
+
+type RoomRequest struct {
+	VenueID       string `json:"venueId" binding:"required"`
+	Name          string `json:"name" binding:"required,max=100"`
+	Capacity      int    `json:"capacity" binding:"min=0,max=100000"`
+	Accessibility string `json:"accessibility" binding:"max=1000"`
+}
+
+// Track is a themed strand of the agenda. Tracks are shown in Order, then
+// by name.
+type Track struct {
+	ID          string `json:"id" firestore:"-"`
+	Name        string `json:"name" firestore:"name"`
+	Description string `json:"description,omitempty" firestore:"description,omitempty"`
+	Color       string `json:"color,omitempty" firestore:"color,omitempty"`
+	Order       int    `json:"order" firestore:"order"`
+}
+
+type TrackRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Color       string `json:"color" binding:"omitempty,hexcolor"`
+	Order       int    `json:"order"`
+}
+
+// SortTracks orders tracks for display.
+func SortTracks(tracks []Track) {
+	sort.SliceStable(tracks, func(i, j int) bool {
+		if tracks[i].Order != tracks[j].Order {
+			return tracks[i].Order < tracks[j].Order
+		}
+		return tracks[i].Name < tracks[j].Name
+	})
+}
+
+// SortRooms orders rooms by name.
+func SortRooms(rooms []Room) {
+	sort.SliceStable(rooms, func(i, j int) bool { return rooms[i].Name < rooms[j].Name })
+}
+
+// Overlaps reports whether two scheduled sessions are on at the same time.
+// Sessions without both a start and an end never overlap.
+func (s Session) Overlaps(other Session) bool {
+	if s.StartsAt == nil || s.EndsAt == nil || other.StartsAt == nil || other.EndsAt == nil {
+		return false
+	}
+	return s.StartsAt.Before(*other.EndsAt) && other.StartsAt.Before(*s.EndsAt)
+}
+
+// AgendaGrid is the agenda laid out by day, then track, then room, for
+// rendering as a timetable. Sessions without a start time are listed
+// separately.
+type AgendaGrid struct {
+	Days        []AgendaDay `json:"days"`
+	Unscheduled []Session   `json:"unscheduled"`
+}
+
+// AgendaDay holds one day's sessions. Date is in the event time zone.
+type AgendaDay struct {
+	Date   string        `json:"date"`
+	Tracks []AgendaTrack `json:"tracks"`
+}
+
+// AgendaTrack holds a track's sessions for a day. Track is nil for sessions
+// not in a track.
+type AgendaTrack struct {
+	Track *Track       `json:"track"`
+	Rooms []AgendaRoom `json:"rooms"`
+}
+
+// AgendaRoom holds a room's sessions within a track, by start time. Room is
+// nil for sessions without a room.
+type AgendaRoom struct {
+	Room     *Room     `json:"room"`
+	Sessions []Session `json:"sessions"`
+}
+
+// NewAgendaGrid lays out sessions by day in loc, then track in display
+// order, then room by name. Sessions whose track or room no longer exists
+// are grouped with those without one, after the others. Days, tracks and
+// rooms with no sessions are left out.
+func NewAgendaGrid(sessions []Session, tracks []Track, rooms []Room, loc *time.Location) AgendaGrid {
+	tracks = append([]Track(nil), tracks...)
+	SortTracks(tracks)
+	rooms = append([]Room(nil), rooms...)
+	SortRooms(rooms)
+	sessions = append([]Session(nil), sessions...)
+	SortSessions(sessions, false)
+
+	trackRank := make(map[string]int, len(tracks))
+	for i, t := range tracks {
+		trackRank[t.ID] = i
+	}
+	roomRank := make(map[string]int, len(rooms))
+	for i, r := range rooms {
+		roomRank[r.ID] = i
+	}
+
+	// Each cell is keyed by day and the positions of its track and room;
+	// a position past the end stands for none.
+	type cell struct {
+		date        string
+		track, room int
+	}
+	var cells []cell
+	cellSessions := make(map[cell][]Session)
+	grid := AgendaGrid{Days: []AgendaDay{}, Unscheduled: []Session{}}
+	for _, session := range sessions {
+		if session.StartsAt == nil {
+			grid.Unscheduled = append(grid.Unscheduled, session)
+			continue
+		}
+		k := cell{date: session.StartsAt.In(loc).Format("2006-01-02"), track: len(tracks), room: len(rooms)}
+		if i, ok := trackRank[session.TrackID]; ok {
+			k.track = i
+		}
+		if i, ok := roomRank[session.RoomID]; ok {
+			k.room = i
+		}
+		if _, ok := cellSessions[k]; !ok {
+			cells = append(cells, k)
+		}
+		cellSessions[k] = append(cellSessions[k], session)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		a, b := cells[i], cells[j]
+		if a.date != b.date {
+			return a.date < b.date
+		}
+		if a.track != b.track {
+			return a.track < b.track
+		}
+		return a.room < b.room
+	})
+
+	for _, k := range cells {
+		if n := len(grid.Days); n == 0 || grid.Days[n-1].Date != k.date {
+			grid.Days = append(grid.Days, AgendaDay{Date: k.date, Tracks: []AgendaTrack{}})
+		}
+		day := &grid.Days[len(grid.Days)-1]
+
+		if n := len(day.Tracks); n == 0 || trackIndex(day.Tracks[n-1].Track, tracks) != k.track {
+			track := AgendaTrack{Rooms: []AgendaRoom{}}
+			if k.track < len(tracks) {
+				track.Track = &tracks[k.track]
+			}
+			day.Tracks = append(day.Tracks, track)
+		}
+		track := &day.Tracks[len(day.Tracks)-1]
+
+		room := AgendaRoom{Sessions: cellSessions[k]}
+		if k.room < len(rooms) {
+			room.Room = &rooms[k.room]
+		}
+		track.Rooms = append(track.Rooms, room)
+	}
+	return grid
+}
+
+// trackIndex returns the position of track in tracks, or len(tracks) for
+// nil.
+func trackIndex(track *Track, tracks []Track) int {
+	for i := range tracks {
+		if track == &tracks[i] {
+			return i
+		}
+	}
+	return len(tracks)
+}
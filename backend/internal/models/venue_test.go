@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scheduled(id, trackID, roomID, start string, minutes int) Session {
+	t, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		panic(err)
+	}
+	end := t.Add(time.Duration(minutes) * time.Minute)
+	return Session{ID: id, Title: id, TrackID: trackID, RoomID: roomID, StartsAt: &t, EndsAt: &end}
+}
+
+func TestSession_Overlaps(t *testing.T) {
+	keynote := scheduled("keynote", "", "hall", "2026-03-14T09:00:00Z", 60)
+
+	assert.True(t, keynote.Overlaps(scheduled("a", "", "hall", "2026-03-14T09:30:00Z", 60)))
+	assert.True(t, keynote.Overlaps(scheduled("b", "", "hall", "2026-03-14T08:00:00Z", 240)))
+	assert.False(t, keynote.Overlaps(scheduled("c", "", "hall", "2026-03-14T10:00:00Z", 60)), "back to back is fine")
+	assert.False(t, keynote.Overlaps(Session{Title: "unscheduled"}))
+}
+
+func TestNewAgendaGrid(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	tracks := []Track{{ID: "research", Name: "Research", Order: 2}, {ID: "applied", Name: "Applied", Order: 1}}
+	rooms := []Room{{ID: "b", Name: "Room B"}, {ID: "a", Name: "Room A"}}
+	sessions := []Session{
+		scheduled("late", "research", "b", "2026-03-14T20:00:00Z", 60), // 15 March in IST
+		scheduled("research-b", "research", "b", "2026-03-14T05:00:00Z", 60),
+		scheduled("applied-a", "applied", "a", "2026-03-14T04:00:00Z", 60),
+		scheduled("applied-b", "applied", "b", "2026-03-14T06:00:00Z", 60),
+		scheduled("applied-a2", "applied", "a", "2026-03-14T07:00:00Z", 60),
+		scheduled("no-track", "gone", "", "2026-03-14T03:30:00Z", 30),
+		{ID: "tba", Title: "TBA"},
+	}
+
+	grid := NewAgendaGrid(sessions, tracks, rooms, ist)
+
+	assert.Equal(t, []Session{{ID: "tba", Title: "TBA"}}, grid.Unscheduled)
+	if !assert.Len(t, grid.Days, 2) {
+		return
+	}
+	assert.Equal(t, "2026-03-14", grid.Days[0].Date)
+	assert.Equal(t, "2026-03-15", grid.Days[1].Date)
+
+	type cell struct {
+		track, room string
+		sessions    []string
+	}
+	var cells []cell
+	for _, track := range grid.Days[0].Tracks {
+		for _, room := range track.Rooms {
+			c := cell{}
+			if track.Track != nil {
+				c.track = track.Track.ID
+			}
+			if room.Room != nil {
+				c.room = room.Room.ID
+			}
+			for _, s := range room.Sessions {
+				c.sessions = append(c.sessions, s.ID)
+			}
+			cells = append(cells, c)
+		}
+	}
+	assert.Equal(t, []cell{
+		{"applied", "a", []string{"applied-a", "applied-a2"}},
+		{"applied", "b", []string{"applied-b"}},
+		{"research", "b", []string{"research-b"}},
+		{"", "", []string{"no-track"}},
+	}, cells)
+}
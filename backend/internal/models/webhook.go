@@ -0,0 +1,117 @@
+package models
+
+import "time"
+
+// Domain event types. Each is recorded in the outbox with the change it
+// describes, and webhooks can subscribe to them.
+const (
+	EventAttendeeCreated   = "attendee.created"
+	EventAttendeeUpdated   = "attendee.updated"
+	EventAttendeeCancelled = "attendee.cancelled"
+	EventSessionCreated    = "session.created"
+	EventSessionUpdated    = "session.updated"
+	EventSessionDeleted    = "session.deleted"
+	EventSpeakerCreated    = "speaker.created"
+	EventSpeakerUpdated    = "speaker.updated"
+	EventSpeakerDeleted    = "speaker.deleted"
+
+	// EventWebhookTest is only sent by the test endpoint, to one webhook.
+	EventWebhookTest = "webhook.test"
+)
+
+// WebhookEvents lists the events webhooks can subscribe to.
+var WebhookEvents = []string{
+	EventAttendeeCreated, EventAttendeeUpdated, EventAttendeeCancelled,
+	EventSessionCreated, EventSessionUpdated, EventSessionDeleted,
+	EventSpeakerCreated, EventSpeakerUpdated, EventSpeakerDeleted,
+}
+
+// Webhook is an admin-managed subscription: matching events are POSTed to
+// URL, signed with Secret. The secret is only shown when the webhook is
+// created.
+type Webhook struct {
+	ID        string    `json:"id" firestore:"-"`
+	URL       string    `json:"url" firestore:"url"`
+	Events    []string  `json:"events" firestore:"events"`
+	Active    bool      `json:"active" firestore:"active"`
+	Secret    string    `json:"secret,omitempty" firestore:"secret"`
+	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+}
+
+// IsWebhookEvent reports whether webhooks can subscribe to event.
+func IsWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribed reports whether the webhook receives event.
+func (w Webhook) Subscribed(event string) bool {
+	if !w.Active {
+		return false
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,required"`
+	Active *bool    `json:"active"`
+}
+
+// Delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookEvent is the JSON body POSTed to webhooks. ID is the same for
+// every retry of a delivery, so receivers can drop duplicates.
+type WebhookEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// WebhookDelivery is one event queued for one webhook, and the log of its
+// attempts. Pending deliveries are sent once NextAttemptAt has passed;
+// finished deliveries have no NextAttemptAt.
+type WebhookDelivery struct {
+	ID        string `json:"id" firestore:"-"`
+	WebhookID string `json:"webhookId" firestore:"webhookId"`
+	EventID   string `json:"eventId" firestore:"eventId"`
+	Event     string `json:"event" firestore:"event"`
+	// Payload is the exact body that is signed and sent
+	Payload string `json:"payload" firestore:"payload"`
+	// AttendeeID links deliveries about an attendee to them, so they are
+	// erased with the rest of their data
+	AttendeeID string `json:"attendeeId,omitempty" firestore:"attendeeId,omitempty"`
+
+	Status        string     `json:"status" firestore:"status"`
+	Attempts      int        `json:"attempts" firestore:"attempts"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty" firestore:"nextAttemptAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt" firestore:"createdAt"`
+	// LeasedUntil is set while an instance is sending the delivery
+	LeasedUntil *time.Time `json:"leasedUntil,omitempty" firestore:"leasedUntil,omitempty"`
+
+	// The outcome of the last attempt
+	LastAttemptAt  *time.Time `json:"lastAttemptAt,omitempty" firestore:"lastAttemptAt,omitempty"`
+	ResponseStatus int        `json:"responseStatus,omitempty" firestore:"responseStatus,omitempty"`
+	Error          string     `json:"error,omitempty" firestore:"error,omitempty"`
+	DurationMs     int64      `json:"durationMs,omitempty" firestore:"durationMs,omitempty"`
+}
+
+// Leased reports whether an instance is still sending the delivery at now.
+func (d WebhookDelivery) Leased(now time.Time) bool {
+	return d.LeasedUntil != nil && d.LeasedUntil.After(now)
+}
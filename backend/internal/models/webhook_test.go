@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_Subscribed(t *testing.T) {
+	webhook := Webhook{Active: true, Events: []string{EventAttendeeCreated, EventSessionUpdated}}
+	assert.True(t, webhook.Subscribed(EventAttendeeCreated))
+	assert.False(t, webhook.Subscribed(EventSpeakerDeleted))
+
+	webhook.Active = false
+	assert.False(t, webhook.Subscribed(EventAttendeeCreated))
+}
+
+func TestIsWebhookEvent(t *testing.T) {
+	assert.True(t, IsWebhookEvent(EventSpeakerDeleted))
+	assert.False(t, IsWebhookEvent(EventWebhookTest))
+	assert.False(t, IsWebhookEvent("attendee.deleted"))
+}
+
+func TestWebhookDelivery_Leased(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	later := now.Add(time.Minute)
+	earlier := now.Add(-time.Second)
+
+	assert.False(t, WebhookDelivery{}.Leased(now))
+	assert.True(t, WebhookDelivery{LeasedUntil: &later}.Leased(now))
+	assert.False(t, WebhookDelivery{LeasedUntil: &earlier}.Leased(now))
+}
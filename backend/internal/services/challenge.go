@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrChallengeFailed is returned when a submission looks automated. The
+// reason is deliberately not exposed to the client.
+var ErrChallengeFailed = errors.New("challenge verification failed")
+
+// ChallengeSubmission carries the anti-bot fields sent with a public form.
+type ChallengeSubmission struct {
+	// Token and Solution answer a proof-of-work challenge.
+	Token    string
+	Solution string
+	// CaptchaToken is the response token from an external CAPTCHA widget.
+	CaptchaToken string
+	// Honeypot is a field hidden from humans; bots tend to fill it in.
+	Honeypot string
+	RemoteIP string
+}
+
+// ChallengeVerifier decides whether a form submission came from a human.
+type ChallengeVerifier interface {
+	// Issue returns a challenge for the form to solve, or nil if the
+	// verifier does not need one.
+	Issue() (*Challenge, error)
+	Verify(ctx context.Context, sub ChallengeSubmission) error
+}
+
+// CaptchaProvider verifies a token issued by an external CAPTCHA service.
+type CaptchaProvider interface {
+	VerifyCaptcha(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// Challenge is handed to the browser, which must find a Solution such that
+// sha256(Token + ":" + Solution) starts with Difficulty zero bits.
+type Challenge struct {
+	Token          string    `json:"token"`
+	Difficulty     int       `json:"difficulty"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	CaptchaSiteKey string    `json:"captchaSiteKey,omitempty"`
+}
+
+// TokenClaims remembers one-time tokens until they expire. The rate limit
+// stores implement it, so a Firestore store shares claims between instances.
+type TokenClaims interface {
+	Claim(ctx context.Context, key string, expires time.Time) (bool, error)
+}
+
+// ProofOfWork issues and verifies self-hosted proof-of-work challenges.
+// Tokens are HMAC-signed so no state is needed to issue them; solved tokens
+// are claimed until they expire to prevent replays.
+type ProofOfWork struct {
+	secret     []byte
+	difficulty int
+	ttl        time.Duration
+	claims     TokenClaims
+	now        func() time.Time
+}
+
+func NewProofOfWork(secret []byte, difficulty int, ttl time.Duration, claims TokenClaims) *ProofOfWork {
+	return &ProofOfWork{
+		secret:     secret,
+		difficulty: difficulty,
+		ttl:        ttl,
+		claims:     claims,
+		now:        time.Now,
+	}
+}
+
+// Issue creates a new challenge.
+func (p *ProofOfWork) Issue() (Challenge, error) {
+	payload := make([]byte, 16+8+1)
+	if _, err := rand.Read(payload[:16]); err != nil {
+		return Challenge{}, err
+	}
+	issuedAt := p.now()
+	binary.BigEndian.PutUint64(payload[16:24], uint64(issuedAt.UnixMilli()))
+	payload[24] = byte(p.difficulty)
+
+	token := base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(p.sign(payload))
+	return Challenge{
+		Token:      token,
+		Difficulty: p.difficulty,
+		ExpiresAt:  issuedAt.Add(p.ttl),
+	}, nil
+}
+
+// Check validates the token signature and expiry, the solution and that the
+// token has not been used before. It returns when the challenge was issued.
+func (p *ProofOfWork) Check(ctx context.Context, token, solution string) (time.Time, error) {
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return time.Time{}, fmt.Errorf("malformed token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil || len(payload) != 25 {
+		return time.Time{}, fmt.Errorf("malformed token")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, p.sign(payload)) {
+		return time.Time{}, fmt.Errorf("invalid token signature")
+	}
+
+	issuedAt := time.UnixMilli(int64(binary.BigEndian.Uint64(payload[16:24])))
+	now := p.now()
+	if now.After(issuedAt.Add(p.ttl)) {
+		return time.Time{}, fmt.Errorf("token expired")
+	}
+	if !SolvesChallenge(token, solution, int(payload[24])) {
+		return time.Time{}, fmt.Errorf("invalid solution")
+	}
+
+	fresh, err := p.claims.Claim(ctx, "challenge:"+token, issuedAt.Add(p.ttl))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !fresh {
+		return time.Time{}, fmt.Errorf("token already used")
+	}
+
+	return issuedAt, nil
+}
+
+func (p *ProofOfWork) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// SolvesChallenge reports whether sha256(token:solution) has at least
+// difficulty leading zero bits.
+func SolvesChallenge(token, solution string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(token + ":" + solution))
+	zeros := 0
+	for _, b := range sum {
+		if b == 0 {
+			zeros += 8
+			continue
+		}
+		zeros += bits.LeadingZeros8(b)
+		break
+	}
+	return zeros >= difficulty
+}
+
+// SiteVerifyCaptcha verifies tokens against a siteverify-style endpoint as
+// offered by reCAPTCHA, hCaptcha and Cloudflare Turnstile.
+type SiteVerifyCaptcha struct {
+	VerifyURL string
+	Secret    string
+	SiteKey   string
+	Client    *http.Client
+}
+
+func (s *SiteVerifyCaptcha) VerifyCaptcha(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{
+		"secret":   {s.Secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.VerifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha verify: unexpected status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
+
+// BotGuard combines the honeypot and timing heuristics with an optional
+// proof-of-work challenge and an optional external CAPTCHA.
+type BotGuard struct {
+	PoW     *ProofOfWork
+	Captcha CaptchaProvider
+	// MinFillTime rejects forms submitted faster than a human could fill
+	// them in, measured from when the challenge was issued.
+	MinFillTime time.Duration
+}
+
+func (g *BotGuard) Verify(ctx context.Context, sub ChallengeSubmission) error {
+	if strings.TrimSpace(sub.Honeypot) != "" {
+		return fmt.Errorf("%w: honeypot filled", ErrChallengeFailed)
+	}
+
+	if g.PoW != nil {
+		issuedAt, err := g.PoW.Check(ctx, sub.Token, sub.Solution)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrChallengeFailed, err)
+		}
+		if g.MinFillTime > 0 && g.PoW.now().Sub(issuedAt) < g.MinFillTime {
+			return fmt.Errorf("%w: submitted too fast", ErrChallengeFailed)
+		}
+	}
+
+	if g.Captcha != nil {
+		ok, err := g.Captcha.VerifyCaptcha(ctx, sub.CaptchaToken, sub.RemoteIP)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: captcha rejected", ErrChallengeFailed)
+		}
+	}
+
+	return nil
+}
+
+// Issue returns a new challenge for the public form, or nil when neither
+// proof-of-work nor CAPTCHA is configured.
+func (g *BotGuard) Issue() (*Challenge, error) {
+	var challenge Challenge
+	if g.PoW != nil {
+		var err error
+		if challenge, err = g.PoW.Issue(); err != nil {
+			return nil, err
+		}
+	}
+	if captcha, ok := g.Captcha.(*SiteVerifyCaptcha); ok {
+		challenge.CaptchaSiteKey = captcha.SiteKey
+	}
+	if challenge.Token == "" && challenge.CaptchaSiteKey == "" {
+		return nil, nil
+	}
+	return &challenge, nil
+}
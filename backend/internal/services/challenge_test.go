@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func solve(t *testing.T, challenge Challenge) string {
+	for i := 0; i < 1<<20; i++ {
+		solution := strconv.Itoa(i)
+		if SolvesChallenge(challenge.Token, solution, challenge.Difficulty) {
+			return solution
+		}
+	}
+	t.Fatal("no solution found")
+	return ""
+}
+
+func TestProofOfWork(t *testing.T) {
+	ctx := context.Background()
+	claims := middleware.NewMemoryRateLimitStore()
+	pow := NewProofOfWork([]byte("secret"), 8, time.Minute, claims)
+	challenge, err := pow.Issue()
+	assert.NoError(t, err)
+
+	solution := solve(t, challenge)
+
+	_, err = pow.Check(ctx, challenge.Token, solution)
+	assert.NoError(t, err)
+
+	// Replays are rejected, also by other instances sharing the store
+	_, err = pow.Check(ctx, challenge.Token, solution)
+	assert.Error(t, err)
+	_, err = NewProofOfWork([]byte("secret"), 8, time.Minute, claims).Check(ctx, challenge.Token, solution)
+	assert.Error(t, err)
+
+	// Tokens signed with another secret are rejected
+	other := NewProofOfWork([]byte("other"), 8, time.Minute, middleware.NewMemoryRateLimitStore())
+	_, err = other.Check(ctx, challenge.Token, solution)
+	assert.Error(t, err)
+
+	// Expired tokens are rejected
+	challenge, _ = pow.Issue()
+	solution = solve(t, challenge)
+	pow.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = pow.Check(ctx, challenge.Token, solution)
+	assert.Error(t, err)
+}
+
+type stubCaptcha struct {
+	ok bool
+}
+
+func (s stubCaptcha) VerifyCaptcha(ctx context.Context, token, remoteIP string) (bool, error) {
+	return s.ok, nil
+}
+
+func TestBotGuard_Verify(t *testing.T) {
+	ctx := context.Background()
+
+	guard := &BotGuard{}
+	assert.NoError(t, guard.Verify(ctx, ChallengeSubmission{}))
+	assert.True(t, errors.Is(guard.Verify(ctx, ChallengeSubmission{Honeypot: "spam"}), ErrChallengeFailed))
+
+	guard = &BotGuard{Captcha: stubCaptcha{ok: false}}
+	assert.True(t, errors.Is(guard.Verify(ctx, ChallengeSubmission{CaptchaToken: "t"}), ErrChallengeFailed))
+	guard = &BotGuard{Captcha: stubCaptcha{ok: true}}
+	assert.NoError(t, guard.Verify(ctx, ChallengeSubmission{CaptchaToken: "t"}))
+
+	// Submissions faster than MinFillTime are rejected
+	pow := NewProofOfWork([]byte("secret"), 4, time.Minute, middleware.NewMemoryRateLimitStore())
+	guard = &BotGuard{PoW: pow, MinFillTime: 3 * time.Second}
+	challenge, _ := pow.Issue()
+	err := guard.Verify(ctx, ChallengeSubmission{Token: challenge.Token, Solution: solve(t, challenge)})
+	assert.True(t, errors.Is(err, ErrChallengeFailed))
+
+	challenge, _ = pow.Issue()
+	pow.now = func() time.Time { return time.Now().Add(5 * time.Second) }
+	err = guard.Verify(ctx, ChallengeSubmission{Token: challenge.Token, Solution: solve(t, challenge)})
+	assert.NoError(t, err)
+}
+
+func TestSiteVerifyCaptcha(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		if r.PostForm.Get("response") == "good" {
+			w.Write([]byte(`{"success": true}`))
+			return
+		}
+		w.Write([]byte(`{"success": false}`))
+	}))
+	defer server.Close()
+
+	captcha := &SiteVerifyCaptcha{VerifyURL: server.URL, Secret: "secret"}
+
+	ok, err := captcha.VerifyCaptcha(context.Background(), "good", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = captcha.VerifyCaptcha(context.Background(), "bad", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
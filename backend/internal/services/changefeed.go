@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"appdirect-ai-workshop/internal/metrics"
+	"appdirect-ai-workshop/internal/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// Kinds of document change.
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeRemoved  = "removed"
+)
+
+// AgendaChange is the payload of an EventAgenda event. Data is the session
+// or speaker as it is now, and nil when it was removed.
+type AgendaChange struct {
+	Collection string `json:"collection"`
+	Change     string `json:"change"`
+	ID         string `json:"id"`
+	Data       any    `json:"data,omitempty"`
+}
+
+// QuestionChange is the payload of EventQuestion and EventModeration
+// events. Data is a models.PublicQuestion or, for moderators, a
+// models.LiveQuestion, and nil when the question was removed. SessionID is
+// empty for removals.
+type QuestionChange struct {
+	Change    string `json:"change"`
+	ID        string `json:"id"`
+	SessionID string `json:"sessionId,omitempty"`
+	Data      any    `json:"data,omitempty"`
+}
+
+// DocumentChange is a document that was added, modified or removed between
+// two snapshots of a collection.
+type DocumentChange struct {
+	Kind string
+	ID   string
+	// Doc is nil for removals
+	Doc *firestore.DocumentSnapshot
+	// At is when the change was written, or when the removal was seen
+	At time.Time
+}
+
+// EventID identifies the change across instances: every instance sees the
+// same update time for the same write.
+func (d DocumentChange) EventID() string {
+	return fmt.Sprintf("%d-%s", d.At.UnixNano(), d.ID)
+}
+
+// versions records the update time of every document in a collection.
+type versions map[string]time.Time
+
+// diffSnapshot compares the documents of a snapshot with the versions seen
+// before, returning the changes and the new versions. Diffing whole
+// snapshots rather than using the listener's own change list means a
+// listener that restarted after an error reports what changed while it was
+// down instead of reporting every document as added.
+func diffSnapshot(prev versions, docs []*firestore.DocumentSnapshot, readTime time.Time) ([]DocumentChange, versions) {
+	next := make(versions, len(docs))
+	var changes []DocumentChange
+	for _, doc := range docs {
+		id := doc.Ref.ID
+		next[id] = doc.UpdateTime
+		seen, ok := prev[id]
+		switch {
+		case !ok:
+			changes = append(changes, DocumentChange{Kind: ChangeAdded, ID: id, Doc: doc, At: doc.UpdateTime})
+		case !seen.Equal(doc.UpdateTime):
+			changes = append(changes, DocumentChange{Kind: ChangeModified, ID: id, Doc: doc, At: doc.UpdateTime})
+		}
+	}
+	for id := range prev {
+		if _, ok := next[id]; !ok {
+			changes = append(changes, DocumentChange{Kind: ChangeRemoved, ID: id, At: readTime})
+		}
+	}
+	return changes, next
+}
+
+// Listener restart delays after a failure.
+const (
+	minListenBackoff = time.Second
+	maxListenBackoff = time.Minute
+)
+
+// ChangeFeed publishes attendee, agenda and live question changes to an EventHub as they
+// are written to Firestore, by any instance.
+type ChangeFeed struct {
+	firestore *FirestoreService
+	hub       *EventHub
+	index     *AttendeeIndex
+	metrics   *metrics.Metrics
+}
+
+// NewChangeFeed creates a ChangeFeed. index, when not nil, is kept in sync
+// with the attendees. metrics may be nil; otherwise the attendee gauge is
+// kept up to date from the feed.
+func NewChangeFeed(firestore *FirestoreService, hub *EventHub, index *AttendeeIndex, metrics *metrics.Metrics) *ChangeFeed {
+	return &ChangeFeed{firestore: firestore, hub: hub, index: index, metrics: metrics}
+}
+
+// Run listens to attendees, sessions, speakers and live questions until ctx is done,
+// restarting failed listeners with backoff.
+func (f *ChangeFeed) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	watch := func(collection string, onChange func([]DocumentChange, []*firestore.DocumentSnapshot, bool)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.watch(ctx, collection, onChange)
+		}()
+	}
+
+	watch("attendees", f.attendeesChanged)
+	watch("sessions", f.agendaChanged("sessions", func() any { return &models.Session{} }))
+	watch("speakers", f.agendaChanged("speakers", func() any { return &models.Speaker{} }))
+	watch("liveQuestions", f.questionsChanged)
+	wg.Wait()
+}
+
+// watch calls onChange for every snapshot of collection. The first
+// snapshot only establishes what exists; initial is true for it.
+func (f *ChangeFeed) watch(ctx context.Context, collection string, onChange func(changes []DocumentChange, docs []*firestore.DocumentSnapshot, initial bool)) {
+	var seen versions
+	backoff := minListenBackoff
+	for {
+		err := f.firestore.Listen(ctx, collection, func(snap *firestore.QuerySnapshot) error {
+			docs, err := snap.Documents.GetAll()
+			if err != nil {
+				return err
+			}
+			initial := seen == nil
+			var changes []DocumentChange
+			changes, seen = diffSnapshot(seen, docs, snap.ReadTime)
+			onChange(changes, docs, initial)
+			backoff = minListenBackoff
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("Change listener failed, restarting", "collection", collection, "error", err, "retryIn", backoff.String())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxListenBackoff)
+	}
+}
+
+// attendeesChanged updates the search index, and publishes the registration
+// count when it changes and every new registration to admins.
+func (f *ChangeFeed) attendeesChanged(changes []DocumentChange, docs []*firestore.DocumentSnapshot, initial bool) {
+	if f.index != nil {
+		f.index.Apply(changes)
+	}
+
+	count := 0
+	for _, doc := range docs {
+		if status, _ := doc.Data()["status"].(string); status != models.StatusCancelled {
+			count++
+		}
+	}
+	if initial || len(changes) > 0 {
+		f.metrics.SetAttendees(count)
+		f.hub.Publish(Event{Type: EventCount, Data: models.AttendeeCount{Count: count}})
+	}
+	if initial {
+		return
+	}
+
+	for _, change := range changes {
+		if change.Kind != ChangeAdded {
+			continue
+		}
+		var attendee models.Attendee
+		if err := change.Doc.DataTo(&attendee); err != nil {
+			slog.Error("Failed to decode attendee", "id", change.ID, "error", err)
+			continue
+		}
+		attendee.ID = change.ID
+		f.hub.Publish(Event{ID: change.EventID(), Type: EventRegistration, Data: attendee, AdminOnly: true})
+	}
+}
+
+// agendaChanged returns a handler publishing every change to a session or
+// speaker, decoded into the value returned by newValue.
+func (f *ChangeFeed) agendaChanged(collection string, newValue func() any) func([]DocumentChange, []*firestore.DocumentSnapshot, bool) {
+	return func(changes []DocumentChange, _ []*firestore.DocumentSnapshot, initial bool) {
+		if initial {
+			return
+		}
+		for _, change := range changes {
+			payload := AgendaChange{Collection: collection, Change: change.Kind, ID: change.ID}
+			if change.Doc != nil {
+				value := newValue()
+				if err := change.Doc.DataTo(value); err != nil {
+					slog.Error("Failed to decode agenda change", "collection", collection, "id", change.ID, "error", err)
+					continue
+				}
+				setID(value, change.ID)
+				payload.Data = value
+			}
+			f.hub.Publish(Event{ID: change.EventID(), Type: EventAgenda, Data: payload})
+		}
+	}
+}
+
+// questionsChanged publishes every change to a live question to moderators,
+// and what the audience may see of it to everyone else.
+func (f *ChangeFeed) questionsChanged(changes []DocumentChange, _ []*firestore.DocumentSnapshot, initial bool) {
+	if initial {
+		return
+	}
+	for _, change := range changes {
+		if change.Doc == nil {
+			removed := QuestionChange{Change: ChangeRemoved, ID: change.ID}
+			f.hub.Publish(Event{ID: change.EventID(), Type: EventQuestion, Data: removed})
+			f.hub.Publish(Event{ID: change.EventID() + "-moderation", Type: EventModeration, Data: removed, AdminOnly: true})
+			continue
+		}
+
+		var question models.LiveQuestion
+		if err := change.Doc.DataTo(&question); err != nil {
+			slog.Error("Failed to decode live question", "id", change.ID, "error", err)
+			continue
+		}
+		question.ID = change.ID
+		question.Votes = len(question.VoterIDs)
+
+		f.hub.Publish(Event{
+			ID:        change.EventID() + "-moderation",
+			Type:      EventModeration,
+			Data:      QuestionChange{Change: change.Kind, ID: change.ID, SessionID: question.SessionID, Data: question},
+			AdminOnly: true,
+		})
+
+		public := QuestionChange{Change: change.Kind, ID: change.ID, SessionID: question.SessionID}
+		switch {
+		case question.Visible():
+			public.Data = models.NewPublicQuestion(question)
+		case change.Kind == ChangeAdded:
+			// Never shown, so there is nothing to take down
+			continue
+		default:
+			public.Change = ChangeRemoved
+		}
+		f.hub.Publish(Event{ID: change.EventID(), Type: EventQuestion, Data: public})
+	}
+}
+
+func setID(value any, id string) {
+	switch v := value.(type) {
+	case *models.Session:
+		v.ID = id
+	case *models.Speaker:
+		v.ID = id
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshot(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	doc := func(id string, updated time.Time) *firestore.DocumentSnapshot {
+		return &firestore.DocumentSnapshot{Ref: &firestore.DocumentRef{ID: id}, UpdateTime: updated}
+	}
+	kinds := func(changes []DocumentChange) []string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, c.Kind+":"+c.ID)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	changes, seen := diffSnapshot(nil, []*firestore.DocumentSnapshot{doc("a", t0), doc("b", t0)}, t0)
+	assert.Equal(t, []string{"added:a", "added:b"}, kinds(changes))
+
+	readTime := t0.Add(time.Minute)
+	changes, seen = diffSnapshot(seen, []*firestore.DocumentSnapshot{doc("a", t0), doc("b", t0.Add(time.Second)), doc("c", t0.Add(2*time.Second))}, readTime)
+	assert.Equal(t, []string{"added:c", "modified:b"}, kinds(changes))
+
+	changes, _ = diffSnapshot(seen, []*firestore.DocumentSnapshot{doc("b", t0.Add(time.Second)), doc("c", t0.Add(2*time.Second))}, readTime)
+	assert.Equal(t, []DocumentChange{{Kind: ChangeRemoved, ID: "a", At: readTime}}, changes)
+	assert.Equal(t, "1772355660000000000-a", changes[0].EventID())
+}
+
+func TestChangeFeed_QuestionRemoved(t *testing.T) {
+	hub := NewEventHub(10, 10)
+	feed := NewChangeFeed(nil, hub, nil, nil)
+	public, _, _ := hub.Subscribe(false, "")
+	admin, _, _ := hub.Subscribe(true, "")
+
+	at := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	feed.questionsChanged([]DocumentChange{{Kind: ChangeRemoved, ID: "q1", At: at}}, nil, false)
+
+	e := <-public.C
+	assert.Equal(t, EventQuestion, e.Type)
+	assert.Equal(t, QuestionChange{Change: ChangeRemoved, ID: "q1"}, e.Data)
+	assert.Empty(t, public.C, "moderation events are for admins only")
+
+	var types []string
+	for len(admin.C) > 0 {
+		types = append(types, (<-admin.C).Type)
+	}
+	assert.Equal(t, []string{EventQuestion, EventModeration}, types)
+}
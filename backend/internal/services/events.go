@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"sync"
+)
+
+// Live event types.
+const (
+	// EventCount carries the number of registrations that still count. It
+	// is state: only the latest is kept and every new stream starts with it.
+	EventCount = "count"
+	// EventRegistration carries a new registration. Admin streams only.
+	EventRegistration = "registration"
+	// EventAgenda carries an added, changed or removed session or speaker.
+	EventAgenda = "agenda"
+	// EventQuestion carries a live question as the audience sees it. A
+	// question that is hidden or no longer approved is sent as removed.
+	EventQuestion = "question"
+	// EventModeration carries every change to a live question, with who
+	// asked it. Admin streams only.
+	EventModeration = "moderation"
+	// EventReset tells a reconnecting client that events since its
+	// Last-Event-ID are no longer available and it must reload.
+	EventReset = "reset"
+)
+
+var (
+	ErrTooManySubscribers = errors.New("too many live event subscribers")
+	ErrHubClosed          = errors.New("live events are shutting down")
+)
+
+// Event is one message on the live event stream. Events with an ID are
+// kept for replay after a reconnect; events without one are state, of which
+// only the latest per type is kept.
+type Event struct {
+	ID        string
+	Type      string
+	Data      any
+	AdminOnly bool
+}
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// it is disconnected. It reconnects with Last-Event-ID and catches up from
+// the history.
+const subscriberBuffer = 64
+
+// EventHub fans events out to live subscribers, at most maxSubscribers at a
+// time, and keeps the most recent ones so reconnecting clients can resume.
+type EventHub struct {
+	maxSubscribers int
+	historySize    int
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	history     []Event
+	state       map[string]Event
+	closed      bool
+}
+
+// NewEventHub creates an EventHub allowing maxSubscribers concurrent
+// subscribers and keeping the last historySize events for replay.
+func NewEventHub(maxSubscribers, historySize int) *EventHub {
+	return &EventHub{
+		maxSubscribers: maxSubscribers,
+		historySize:    historySize,
+		subscribers:    make(map[*Subscription]struct{}),
+		state:          make(map[string]Event),
+	}
+}
+
+// Subscription receives events until it is closed. C is closed when the
+// hub shuts down or the subscriber fell too far behind.
+type Subscription struct {
+	C     <-chan Event
+	c     chan Event
+	admin bool
+}
+
+// Subscribe registers a subscriber and returns what it must be sent before
+// live events: a reset if lastEventID is unknown, the events after
+// lastEventID, then the current state.
+func (h *EventHub) Subscribe(admin bool, lastEventID string) (*Subscription, []Event, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, nil, ErrHubClosed
+	}
+	if len(h.subscribers) >= h.maxSubscribers {
+		return nil, nil, ErrTooManySubscribers
+	}
+
+	var initial []Event
+	if lastEventID != "" {
+		found := false
+		for i, e := range h.history {
+			if e.ID == lastEventID {
+				found = true
+				for _, missed := range h.history[i+1:] {
+					if admin || !missed.AdminOnly {
+						initial = append(initial, missed)
+					}
+				}
+				break
+			}
+		}
+		if !found {
+			initial = append(initial, Event{Type: EventReset, Data: struct{}{}})
+		}
+	}
+	for _, e := range h.state {
+		if admin || !e.AdminOnly {
+			initial = append(initial, e)
+		}
+	}
+
+	c := make(chan Event, subscriberBuffer)
+	sub := &Subscription{C: c, c: c, admin: admin}
+	h.subscribers[sub] = struct{}{}
+	return sub, initial, nil
+}
+
+// Unsubscribe removes a subscriber. It is safe to call more than once.
+func (h *EventHub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+func (h *EventHub) remove(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.c)
+	}
+}
+
+// Publish sends e to every subscriber allowed to see it, without blocking.
+func (h *EventHub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	if e.ID == "" {
+		h.state[e.Type] = e
+	} else {
+		h.history = append(h.history, e)
+		if len(h.history) > h.historySize {
+			h.history = h.history[len(h.history)-h.historySize:]
+		}
+	}
+
+	for sub := range h.subscribers {
+		if e.AdminOnly && !sub.admin {
+			continue
+		}
+		select {
+		case sub.c <- e:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribers returns the number of live subscribers.
+func (h *EventHub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+// Close disconnects every subscriber and refuses new ones, so open streams
+// do not hold up a graceful shutdown.
+func (h *EventHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		h.remove(sub)
+	}
+}
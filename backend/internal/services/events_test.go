@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventTypes(events []Event) []string {
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type+":"+e.ID)
+	}
+	return types
+}
+
+func TestEventHub_Publish(t *testing.T) {
+	hub := NewEventHub(10, 10)
+	public, _, err := hub.Subscribe(false, "")
+	require.NoError(t, err)
+	admin, _, err := hub.Subscribe(true, "")
+	require.NoError(t, err)
+
+	hub.Publish(Event{ID: "1", Type: EventRegistration, AdminOnly: true})
+	hub.Publish(Event{ID: "2", Type: EventAgenda})
+
+	assert.Equal(t, "2", (<-public.C).ID)
+	assert.Equal(t, "1", (<-admin.C).ID)
+	assert.Equal(t, "2", (<-admin.C).ID)
+	assert.Empty(t, public.C)
+}
+
+func TestEventHub_Subscribe(t *testing.T) {
+	hub := NewEventHub(10, 3)
+	hub.Publish(Event{Type: EventCount, Data: 1})
+	hub.Publish(Event{ID: "1", Type: EventAgenda})
+	hub.Publish(Event{ID: "2", Type: EventRegistration, AdminOnly: true})
+	hub.Publish(Event{Type: EventCount, Data: 2})
+	hub.Publish(Event{ID: "3", Type: EventAgenda})
+	hub.Publish(Event{ID: "4", Type: EventAgenda})
+
+	tests := []struct {
+		name        string
+		admin       bool
+		lastEventID string
+		expected    []string
+	}{
+		{"fresh", false, "", []string{"count:"}},
+		{"resume", false, "2", []string{"agenda:3", "agenda:4", "count:"}},
+		{"resume admin", true, "2", []string{"agenda:3", "agenda:4", "count:"}},
+		{"resume public skips admin events", false, "3", []string{"agenda:4", "count:"}},
+		{"up to date", false, "4", []string{"count:"}},
+		{"expired", false, "1", []string{"reset:", "count:"}},
+		{"unknown", true, "nope", []string{"reset:", "count:"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, initial, err := hub.Subscribe(tt.admin, tt.lastEventID)
+			require.NoError(t, err)
+			defer hub.Unsubscribe(sub)
+
+			assert.Equal(t, tt.expected, eventTypes(initial))
+			assert.Equal(t, 2, initial[len(initial)-1].Data, "latest count")
+		})
+	}
+}
+
+func TestEventHub_MaxSubscribers(t *testing.T) {
+	hub := NewEventHub(1, 10)
+	sub, _, err := hub.Subscribe(false, "")
+	require.NoError(t, err)
+
+	_, _, err = hub.Subscribe(true, "")
+	assert.ErrorIs(t, err, ErrTooManySubscribers)
+
+	hub.Unsubscribe(sub)
+	hub.Unsubscribe(sub)
+	_, _, err = hub.Subscribe(true, "")
+	assert.NoError(t, err)
+}
+
+func TestEventHub_DropsSlowSubscribers(t *testing.T) {
+	hub := NewEventHub(10, 10)
+	sub, _, err := hub.Subscribe(false, "")
+	require.NoError(t, err)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(Event{Type: EventCount, Data: i})
+	}
+
+	assert.Equal(t, 0, hub.Subscribers())
+	received := 0
+	for range sub.C {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+}
+
+func TestEventHub_Close(t *testing.T) {
+	hub := NewEventHub(10, 10)
+	sub, _, err := hub.Subscribe(false, "")
+	require.NoError(t, err)
+
+	hub.Close()
+	_, ok := <-sub.C
+	assert.False(t, ok)
+
+	_, _, err = hub.Subscribe(false, "")
+	assert.ErrorIs(t, err, ErrHubClosed)
+	hub.Publish(Event{Type: EventCount})
+	hub.Unsubscribe(sub)
+}
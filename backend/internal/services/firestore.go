@@ -2,29 +2,36 @@ package services
 
 import (
 	"context"
+	"log/slog"
 	"os"
 	"strings"
+	"time"
+
+	"appdirect-ai-workshop/internal/config"
+	"appdirect-ai-workshop/internal/logging"
+	"appdirect-ai-workshop/internal/metrics"
+	"appdirect-ai-workshop/internal/tracing"
 
 	"cloud.google.com/go/firestore"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
 type FirestoreService struct {
-	client    *firestore.Client
-	subdocID  string
-	projectID string
+	client       *firestore.Client
+	subdocID     string
+	projectID    string
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	metrics      *metrics.Metrics
 }
 
-func NewFirestoreService() (*FirestoreService, error) {
-	projectID := os.Getenv("FIRESTORE_PROJECT_ID")
-	if projectID == "" {
-		projectID = "default-project"
-	}
-
-	subdocID := os.Getenv("FIRESTORE_SUBDOC_ID")
-	if subdocID == "" {
-		subdocID = "workshop"
-	}
+func NewFirestoreService(cfg config.FirestoreConfig) (*FirestoreService, error) {
+	projectID := cfg.ProjectID
+	subdocID := cfg.SubdocID
 
 	ctx := context.Background()
 
@@ -32,7 +39,7 @@ func NewFirestoreService() (*FirestoreService, error) {
 	var client *firestore.Client
 	var err error
 
-	credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	credentialsPath := cfg.CredentialsFile
 	
 	// For Cloud Run and GCP environments, use Application Default Credentials (ADC)
 	// Only use service account file if explicitly provided and file exists
@@ -50,9 +57,11 @@ func NewFirestoreService() (*FirestoreService, error) {
 					client, err = firestore.NewClient(ctx, projectID)
 					if err == nil {
 						return &FirestoreService{
-							client:    client,
-							subdocID:  subdocID,
-							projectID: projectID,
+							client:       client,
+							subdocID:     subdocID,
+							projectID:    projectID,
+							readTimeout:  cfg.ReadTimeout,
+							writeTimeout: cfg.WriteTimeout,
 						}, nil
 					}
 				}
@@ -79,17 +88,209 @@ func NewFirestoreService() (*FirestoreService, error) {
 	}
 
 	return &FirestoreService{
-		client:    client,
-		subdocID:  subdocID,
-		projectID: projectID,
+		client:       client,
+		subdocID:     subdocID,
+		projectID:    projectID,
+		readTimeout:  cfg.ReadTimeout,
+		writeTimeout: cfg.WriteTimeout,
 	}, nil
 }
 
+// SetMetrics enables Prometheus instrumentation of datastore operations.
+func (s *FirestoreService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
 func (s *FirestoreService) GetCollection(collectionName string) *firestore.CollectionRef {
 	docRef := s.client.Collection("workshop").Doc(s.subdocID)
 	return docRef.Collection(collectionName)
 }
 
+// Documents iterates over every document returned by q, calling fn for each.
+// Iteration stops at the first error returned by fn.
+func (s *FirestoreService) Documents(ctx context.Context, collection string, q firestore.Query, fn func(*firestore.DocumentSnapshot) error) (err error) {
+	ctx, span := s.start(ctx, "documents", collection)
+	count := 0
+	defer func() {
+		span.SetAttributes(attribute.Int("db.firestore.documents", count))
+		span.end(err)
+	}()
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		count++
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+}
+
+// All iterates over every document in collection.
+func (s *FirestoreService) All(ctx context.Context, collection string, fn func(*firestore.DocumentSnapshot) error) error {
+	return s.Documents(ctx, collection, s.GetCollection(collection).Query, fn)
+}
+
+func (s *FirestoreService) Get(ctx context.Context, collection, id string) (doc *firestore.DocumentSnapshot, err error) {
+	ctx, span := s.start(ctx, "get", collection)
+	defer func() { span.end(err) }()
+	return s.GetCollection(collection).Doc(id).Get(ctx)
+}
+
+// GetAll reads the documents with the given IDs in one round trip, in order.
+// IDs with no document are skipped.
+func (s *FirestoreService) GetAll(ctx context.Context, collection string, ids []string) (docs []*firestore.DocumentSnapshot, err error) {
+	ctx, span := s.start(ctx, "getAll", collection)
+	defer func() { span.end(err) }()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	refs := make([]*firestore.DocumentRef, len(ids))
+	for i, id := range ids {
+		refs[i] = s.GetCollection(collection).Doc(id)
+	}
+	snaps, err := s.client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+	for _, snap := range snaps {
+		if snap.Exists() {
+			docs = append(docs, snap)
+		}
+	}
+	return docs, nil
+}
+
+func (s *FirestoreService) Add(ctx context.Context, collection string, data interface{}) (ref *firestore.DocumentRef, err error) {
+	ctx, span := s.start(ctx, "add", collection)
+	defer func() { span.end(err) }()
+	ref, _, err = s.GetCollection(collection).Add(ctx, data)
+	return ref, err
+}
+
+// Create stores a document under the given ID, failing with
+// codes.AlreadyExists if there is one.
+func (s *FirestoreService) Create(ctx context.Context, collection, id string, data interface{}) (err error) {
+	ctx, span := s.start(ctx, "create", collection)
+	defer func() { span.end(err) }()
+	_, err = s.GetCollection(collection).Doc(id).Create(ctx, data)
+	return err
+}
+
+func (s *FirestoreService) Update(ctx context.Context, collection, id string, updates []firestore.Update) (err error) {
+	ctx, span := s.start(ctx, "update", collection)
+	defer func() { span.end(err) }()
+	_, err = s.GetCollection(collection).Doc(id).Update(ctx, updates)
+	return err
+}
+
+// Delete removes a document, failing with codes.NotFound if it does not exist.
+func (s *FirestoreService) Delete(ctx context.Context, collection, id string) (err error) {
+	ctx, span := s.start(ctx, "delete", collection)
+	defer func() { span.end(err) }()
+	_, err = s.GetCollection(collection).Doc(id).Delete(ctx, firestore.Exists)
+	return err
+}
+
+// Listen calls fn with a snapshot of collection every time it changes,
+// starting with its current contents, until ctx is done or the listener
+// fails. It always returns a non-nil error.
+func (s *FirestoreService) Listen(ctx context.Context, collection string, fn func(*firestore.QuerySnapshot) error) error {
+	iter := s.GetCollection(collection).Snapshots(ctx)
+	defer iter.Stop()
+
+	for {
+		snap, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if err := fn(snap); err != nil {
+			return err
+		}
+	}
+}
+
+// Ping checks that Firestore is reachable by reading at most one document.
+func (s *FirestoreService) Ping(ctx context.Context) error {
+	return s.Documents(ctx, "attendees", s.GetCollection("attendees").Limit(1), func(*firestore.DocumentSnapshot) error {
+		return nil
+	})
+}
+
+// WithDeadline bounds ctx by the configured read or write timeout. It is
+// applied on top of the request context, so a client disconnect still
+// cancels the call early. The returned function must be called when done.
+func (s *FirestoreService) WithDeadline(ctx context.Context, write bool) (context.Context, context.CancelFunc) {
+	timeout := s.readTimeout
+	if write {
+		timeout = s.writeTimeout
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// operation is an in-flight datastore call.
+type operation struct {
+	trace.Span
+	service    *FirestoreService
+	ctx        context.Context
+	cancel     context.CancelFunc
+	op         string
+	collection string
+	started    time.Time
+}
+
+// start opens a tracing span for a datastore operation and applies its
+// deadline. The returned context carries the span so the Firestore client's
+// own spans nest under it.
+func (s *FirestoreService) start(ctx context.Context, op, collection string) (context.Context, *operation) {
+	ctx, cancel := s.WithDeadline(ctx, op != "documents" && op != "get" && op != "getAll")
+	ctx, span := tracing.Tracer().Start(ctx, "firestore."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "firestore"),
+			attribute.String("db.operation", op),
+			attribute.String("db.firestore.collection", collection),
+		),
+	)
+	return ctx, &operation{Span: span, service: s, ctx: ctx, cancel: cancel, op: op, collection: collection, started: time.Now()}
+}
+
+// end finishes the span, records the operation in the metrics and logs it
+// with the request-scoped logger so failures can be correlated with the
+// request that caused them.
+func (o *operation) end(err error) {
+	defer o.cancel()
+	latency := time.Since(o.started)
+	o.service.metrics.ObserveFirestore(o.op, o.collection, latency, err)
+
+	logger := logging.FromContext(o.ctx)
+	attrs := []any{
+		slog.String("op", o.op),
+		slog.String("collection", o.collection),
+		slog.Float64("latencyMs", float64(latency.Microseconds())/1000),
+	}
+	if err != nil {
+		o.RecordError(err)
+		o.SetStatus(codes.Error, err.Error())
+		o.End()
+		logger.Error("firestore operation failed", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+	o.End()
+	logger.Debug("firestore operation", attrs...)
+}
+
 func (s *FirestoreService) Close() error {
 	return s.client.Close()
 }
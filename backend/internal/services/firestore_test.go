@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestFirestoreService_OperationSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	s := &FirestoreService{}
+
+	_, op := s.start(context.Background(), "get", "sessions")
+	op.end(nil)
+	_, op = s.start(context.Background(), "add", "attendees")
+	op.end(errors.New("boom"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "firestore.get", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "firestore.add", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestFirestoreService_Deadlines(t *testing.T) {
+	s := &FirestoreService{readTimeout: time.Second, writeTimeout: time.Minute}
+
+	ctx, op := s.start(context.Background(), "documents", "attendees")
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+	op.end(nil)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled, "ending an operation releases its context")
+
+	ctx, op = s.start(context.Background(), "update", "speakers")
+	deadline, _ = ctx.Deadline()
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 100*time.Millisecond)
+	op.end(nil)
+
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, op = s.start(parent, "get", "sessions")
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled, "request cancellation propagates")
+	op.end(ctx.Err())
+
+	ctx, done := (&FirestoreService{}).WithDeadline(context.Background(), false)
+	_, ok = ctx.Deadline()
+	assert.False(t, ok, "no timeout configured")
+	done()
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMagicLink is returned for tokens that are malformed, forged or
+// expired. Which one is deliberately not exposed to the client.
+var ErrInvalidMagicLink = errors.New("invalid or expired link")
+
+// The purpose is signed into every token so tokens issued for one purpose
+// with the same secret are never accepted for another.
+const (
+	magicLinkPurpose   = "attendee-self-service:"
+	speakerLinkPurpose = "speaker-feedback:"
+)
+
+// MagicLinks issues and verifies signed, time-limited tokens that let an
+// attendee view, update or cancel their own registration without an account.
+// Tokens are stateless: they hold the attendee ID and expiry, signed with
+// HMAC-SHA256.
+type MagicLinks struct {
+	secret   []byte
+	ttl      time.Duration
+	baseURL  string
+	purpose  string
+	fragment string
+	now      func() time.Time
+}
+
+// NewMagicLinks creates a MagicLinks whose links point at baseURL, the public
+// address of the frontend.
+func NewMagicLinks(secret []byte, ttl time.Duration, baseURL string) *MagicLinks {
+	return &MagicLinks{
+		secret:   secret,
+		ttl:      ttl,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		purpose:  magicLinkPurpose,
+		fragment: "manage",
+		now:      time.Now,
+	}
+}
+
+// SpeakerLinks returns links with the same secret that let a speaker read
+// the feedback on their sessions for ttl. They hold a speaker ID and are not
+// accepted as attendee links, nor the other way round.
+func (m *MagicLinks) SpeakerLinks(ttl time.Duration) *MagicLinks {
+	links := *m
+	links.ttl = ttl
+	links.purpose = speakerLinkPurpose
+	links.fragment = "speaker"
+	return &links
+}
+
+// Issue returns a token for id and when it expires.
+func (m *MagicLinks) Issue(id string) (string, time.Time) {
+	expiresAt := m.now().Add(m.ttl).Truncate(time.Second)
+	payload := make([]byte, 8, 8+len(id))
+	binary.BigEndian.PutUint64(payload, uint64(expiresAt.Unix()))
+	payload = append(payload, id...)
+
+	token := base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(m.sign(payload))
+	return token, expiresAt
+}
+
+// URL returns the link to send for token. The token travels in the URL
+// fragment so it never reaches server access logs or Referer headers.
+func (m *MagicLinks) URL(token string) string {
+	return m.baseURL + "/#" + m.fragment + "=" + token
+}
+
+// Verify checks the token and returns the ID it was issued for.
+func (m *MagicLinks) Verify(token string) (string, error) {
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidMagicLink
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil || len(payload) <= 8 {
+		return "", ErrInvalidMagicLink
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, m.sign(payload)) {
+		return "", ErrInvalidMagicLink
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[:8])), 0)
+	if m.now().After(expiresAt) {
+		return "", ErrInvalidMagicLink
+	}
+	return string(payload[8:]), nil
+}
+
+func (m *MagicLinks) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(m.purpose))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMagicLinks(t *testing.T) {
+	links := NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com/")
+
+	token, expiresAt := links.Issue("attendee-1")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Second)
+	assert.Equal(t, "https://workshop.example.com/#manage="+token, links.URL(token))
+
+	id, err := links.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "attendee-1", id)
+
+	// Tokens signed with another secret are rejected
+	other := NewMagicLinks([]byte("fedcba9876543210"), time.Hour, "https://workshop.example.com")
+	_, err = other.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidMagicLink)
+
+	// Tampering with the payload breaks the signature
+	forged, _ := other.Issue("attendee-2")
+	payload, _, _ := strings.Cut(forged, ".")
+	_, sig, _ := strings.Cut(token, ".")
+	_, err = links.Verify(payload + "." + sig)
+	assert.ErrorIs(t, err, ErrInvalidMagicLink)
+
+	for _, malformed := range []string{"", "abc", "abc.def", "!!.!!"} {
+		_, err = links.Verify(malformed)
+		assert.ErrorIs(t, err, ErrInvalidMagicLink, malformed)
+	}
+
+	// Expired tokens are rejected
+	links.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = links.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidMagicLink)
+}
+
+func TestMagicLinks_SpeakerLinks(t *testing.T) {
+	links := NewMagicLinks([]byte("0123456789abcdef"), time.Hour, "https://workshop.example.com")
+	speakers := links.SpeakerLinks(48 * time.Hour)
+
+	token, expiresAt := speakers.Issue("speaker-1")
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), expiresAt, time.Second)
+	assert.Equal(t, "https://workshop.example.com/#speaker="+token, speakers.URL(token))
+
+	id, err := speakers.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "speaker-1", id)
+
+	// Speaker and attendee tokens are not interchangeable
+	_, err = links.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidMagicLink)
+	attendeeToken, _ := links.Issue("attendee-1")
+	_, err = speakers.Verify(attendeeToken)
+	assert.ErrorIs(t, err, ErrInvalidMagicLink)
+}
+
+func TestSMTPMailer_RejectsHeaderInjection(t *testing.T) {
+	mailer := &SMTPMailer{Host: "localhost", Port: "25", From: "workshop@example.com"}
+	err := mailer.Send(context.Background(), Message{To: "a@example.com\r\nBcc: b@example.com", Subject: "hi"})
+	assert.Error(t, err)
+}
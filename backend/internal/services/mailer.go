@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"appdirect-ai-workshop/internal/logging"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPMailer sends email through an SMTP relay using STARTTLS when the
+// server offers it.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("invalid header value")
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	body := strings.Join([]string{
+		"From: " + m.From,
+		"To: " + msg.To,
+		"Subject: " + msg.Subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+
+	// net/smtp has no context support; bound the call by running it in the
+	// background and giving up when ctx is done.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(m.Host, m.Port), auth, m.From, []string{msg.To}, []byte(body))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// LogMailer writes email to the log instead of sending it. It is meant for
+// local development only: magic links in logs grant access to registrations.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	logging.FromContext(ctx).Info("email not sent, no SMTP server configured",
+		"to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// ErrMailQueueFull is returned when a message is dropped because the queue
+// is full.
+var ErrMailQueueFull = errors.New("mail queue full")
+
+// queuedMessage is a message waiting in a MailQueue, with the logger of the
+// request that sent it.
+type queuedMessage struct {
+	msg    Message
+	logger *slog.Logger
+}
+
+// MailQueue hands messages to a background worker, so a request takes the
+// same time whether or not it sends email. Send only fails when the queue is
+// full; delivery errors are logged by Run.
+type MailQueue struct {
+	mailer  Mailer
+	timeout time.Duration
+	queue   chan queuedMessage
+}
+
+// NewMailQueue creates a MailQueue holding up to size messages, each given
+// timeout to be delivered.
+func NewMailQueue(mailer Mailer, size int, timeout time.Duration) *MailQueue {
+	return &MailQueue{mailer: mailer, timeout: timeout, queue: make(chan queuedMessage, size)}
+}
+
+func (q *MailQueue) Send(ctx context.Context, msg Message) error {
+	select {
+	case q.queue <- queuedMessage{msg: msg, logger: logging.FromContext(ctx)}:
+		return nil
+	default:
+		return ErrMailQueueFull
+	}
+}
+
+// Run delivers queued messages until ctx is done, then tries the ones still
+// queued before returning.
+func (q *MailQueue) Run(ctx context.Context) {
+	for {
+		select {
+		case queued := <-q.queue:
+			q.deliver(context.Background(), queued)
+		case <-ctx.Done():
+			for {
+				select {
+				case queued := <-q.queue:
+					q.deliver(context.Background(), queued)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *MailQueue) deliver(ctx context.Context, queued queuedMessage) {
+	ctx, cancel := context.WithTimeout(logging.WithLogger(ctx, queued.logger), q.timeout)
+	defer cancel()
+	if err := q.mailer.Send(ctx, queued.msg); err != nil {
+		queued.logger.Error("Failed to send email", "subject", queued.msg.Subject, "error", err)
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMailer remembers what it was asked to send.
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *recordingMailer) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sent)
+}
+
+func TestMailQueue_DeliversInBackground(t *testing.T) {
+	mailer := &recordingMailer{}
+	queue := NewMailQueue(mailer, 10, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	require.NoError(t, queue.Send(context.Background(), Message{To: "jane@example.com", Subject: "Hi"}))
+	assert.Eventually(t, func() bool { return mailer.count() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestMailQueue_Full(t *testing.T) {
+	queue := NewMailQueue(&recordingMailer{}, 1, time.Second)
+
+	require.NoError(t, queue.Send(context.Background(), Message{To: "a@example.com"}))
+	assert.ErrorIs(t, queue.Send(context.Background(), Message{To: "b@example.com"}), ErrMailQueueFull)
+}
+
+func TestMailQueue_DrainsOnStop(t *testing.T) {
+	mailer := &recordingMailer{}
+	queue := NewMailQueue(mailer, 10, time.Second)
+	for _, to := range []string{"a@example.com", "b@example.com"} {
+		require.NoError(t, queue.Send(context.Background(), Message{To: to}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	queue.Run(ctx)
+
+	assert.Equal(t, 2, mailer.count())
+}
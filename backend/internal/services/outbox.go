@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	outboxCollection      = "outbox"
+	deadLettersCollection = "deadLetters"
+)
+
+// Dispatch scheduling. Subscribers that fail are retried with the same
+// backoff as webhook deliveries; once MaxDispatchAttempts have failed the
+// event is moved to the dead-letter store, once per failing subscriber.
+// Events are leased to one instance while they are dispatched.
+const (
+	MaxDispatchAttempts = 5
+	dispatchLease       = time.Minute
+	dispatchBatch       = 50
+	dispatchPoll        = 5 * time.Second
+	maxDeadLetters      = 100
+)
+
+// DomainEvent is an outbox event as handed to subscribers.
+type DomainEvent struct {
+	ID         string
+	Type       string
+	SubjectID  string
+	AttendeeID string
+	// Payload is the JSON of the entity after the change
+	Payload    json.RawMessage
+	OccurredAt time.Time
+}
+
+// Subscriber handles domain events. Delivery is at least once: a subscriber
+// that handled an event is not called with it again, unless its instance
+// died before recording that, so subscribers with effects that must not
+// repeat should key them on the event ID.
+type Subscriber func(ctx context.Context, event DomainEvent) error
+
+type subscription struct {
+	name string
+	fn   Subscriber
+}
+
+// Outbox records domain events in the same Firestore transaction as the
+// changes they describe, so an event is stored if and only if its change
+// is, and dispatches them to in-process subscribers in the background. The
+// outbox is shared by every instance; each event is dispatched by one.
+type Outbox struct {
+	firestore   *FirestoreService
+	subscribers []subscription
+	now         func() time.Time
+	wake        chan struct{}
+}
+
+func NewOutbox(firestore *FirestoreService) *Outbox {
+	return &Outbox{
+		firestore: firestore,
+		now:       time.Now,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Subscribe registers fn to handle every event. The name records which
+// events it has handled, so it must not change between deploys. Subscribers
+// are registered before Run is called.
+func (o *Outbox) Subscribe(name string, fn Subscriber) {
+	o.subscribers = append(o.subscribers, subscription{name: name, fn: fn})
+}
+
+// Tx is a Firestore transaction that can also emit domain events. As in any
+// Firestore transaction, every read must come before the first write.
+type Tx struct {
+	tx      *firestore.Transaction
+	outbox  *Outbox
+	emitted int
+}
+
+// Transact runs fn in a transaction. The events fn emits are committed with
+// its writes, or not at all. fn may be run more than once if the transaction
+// conflicts with another.
+func (o *Outbox) Transact(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	ctx, span := o.firestore.start(ctx, "transaction", outboxCollection)
+	defer func() { span.end(err) }()
+
+	emitted := 0
+	err = o.firestore.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		t := &Tx{tx: tx, outbox: o}
+		if err := fn(t); err != nil {
+			return err
+		}
+		emitted = t.emitted
+		return nil
+	})
+	if err == nil && emitted > 0 {
+		o.notify()
+	}
+	return err
+}
+
+func (t *Tx) doc(collection, id string) *firestore.DocumentRef {
+	return t.outbox.firestore.GetCollection(collection).Doc(id)
+}
+
+func (t *Tx) Get(collection, id string) (*firestore.DocumentSnapshot, error) {
+	return t.tx.Get(t.doc(collection, id))
+}
+
+// Where returns the documents in collection whose field at path compares to
+// value with op.
+func (t *Tx) Where(collection, path, op string, value any) ([]*firestore.DocumentSnapshot, error) {
+	return t.tx.Documents(t.outbox.firestore.GetCollection(collection).Where(path, op, value)).GetAll()
+}
+
+// All returns every document in collection.
+func (t *Tx) All(collection string) ([]*firestore.DocumentSnapshot, error) {
+	return t.tx.Documents(t.outbox.firestore.GetCollection(collection)).GetAll()
+}
+
+// Create adds a document with a new ID.
+func (t *Tx) Create(collection string, data any) (*firestore.DocumentRef, error) {
+	ref := t.outbox.firestore.GetCollection(collection).NewDoc()
+	return ref, t.tx.Create(ref, data)
+}
+
+// Set writes a document, replacing it if it exists.
+func (t *Tx) Set(collection, id string, data any) error {
+	return t.tx.Set(t.doc(collection, id), data)
+}
+
+// Update changes a document; the transaction fails with codes.NotFound if
+// it does not exist.
+func (t *Tx) Update(collection, id string, updates []firestore.Update) error {
+	return t.tx.Update(t.doc(collection, id), updates)
+}
+
+// Delete removes a document; the transaction fails with codes.NotFound if
+// it does not exist.
+func (t *Tx) Delete(collection, id string) error {
+	return t.tx.Delete(t.doc(collection, id), firestore.Exists)
+}
+
+// Emit records an event of the given type about subjectID. data is the
+// entity after the change.
+func (t *Tx) Emit(eventType, subjectID string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	ref := t.outbox.firestore.GetCollection(outboxCollection).NewDoc()
+	now := t.outbox.now()
+	event := models.OutboxEvent{
+		EventID:       ref.ID,
+		Type:          eventType,
+		SubjectID:     subjectID,
+		Payload:       string(payload),
+		OccurredAt:    now,
+		Handled:       []string{},
+		NextAttemptAt: &now,
+	}
+	if models.AboutAttendee(eventType) {
+		event.AttendeeID = subjectID
+	}
+	if err := t.tx.Create(ref, event); err != nil {
+		return err
+	}
+	t.emitted++
+	return nil
+}
+
+// notify dispatches now rather than at the next poll.
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispatches events until ctx is done.
+func (o *Outbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatchPoll)
+	defer ticker.Stop()
+	for {
+		if err := o.dispatchDue(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to dispatch domain events", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// dispatchDue dispatches every event whose next attempt is due, oldest
+// first.
+func (o *Outbox) dispatchDue(ctx context.Context) error {
+	query := o.firestore.GetCollection(outboxCollection).
+		Where("nextAttemptAt", "<=", o.now()).
+		OrderBy("nextAttemptAt", firestore.Asc).
+		Limit(dispatchBatch)
+	var due []string
+	err := o.firestore.Documents(ctx, outboxCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		due = append(due, doc.Ref.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range due {
+		event, ok, err := o.claim(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err := o.dispatch(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// claim leases a due event to this instance and counts the attempt. It
+// reports false if another instance got there first.
+func (o *Outbox) claim(ctx context.Context, id string) (models.OutboxEvent, bool, error) {
+	ref := o.firestore.GetCollection(outboxCollection).Doc(id)
+	ctx, cancel := o.firestore.WithDeadline(ctx, true)
+	defer cancel()
+
+	var event models.OutboxEvent
+	claimed := false
+	err := o.firestore.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&event); err != nil {
+			return err
+		}
+		event.ID = doc.Ref.ID
+
+		now := o.now()
+		if event.NextAttemptAt == nil || event.NextAttemptAt.After(now) {
+			return nil
+		}
+		lease := now.Add(dispatchLease)
+		event.Attempts++
+		event.NextAttemptAt = &lease
+		claimed = true
+		return tx.Update(ref, []firestore.Update{
+			{Path: "attempts", Value: event.Attempts},
+			{Path: "nextAttemptAt", Value: lease},
+		})
+	})
+	return event, claimed, err
+}
+
+// dispatch hands a claimed event to the subscribers that have not handled
+// it yet, recording each success as it happens. The event is removed once
+// all have handled it; otherwise it is retried, or dead-lettered when out
+// of attempts.
+func (o *Outbox) dispatch(ctx context.Context, event models.OutboxEvent) error {
+	domainEvent := DomainEvent{
+		ID:         event.EventID,
+		Type:       event.Type,
+		SubjectID:  event.SubjectID,
+		AttendeeID: event.AttendeeID,
+		Payload:    json.RawMessage(event.Payload),
+		OccurredAt: event.OccurredAt,
+	}
+
+	var failed []subscriberFailure
+	for _, sub := range o.pending(event) {
+		if err := handle(ctx, sub, domainEvent); err != nil {
+			slog.Warn("Domain event subscriber failed", "eventId", event.EventID, "event", event.Type, "subscriber", sub.name, "attempt", event.Attempts, "error", err)
+			failed = append(failed, subscriberFailure{name: sub.name, err: err})
+			continue
+		}
+		handled := []firestore.Update{{Path: "handled", Value: firestore.ArrayUnion(sub.name)}}
+		if err := o.firestore.Update(ctx, outboxCollection, event.ID, handled); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case len(failed) == 0:
+		return o.firestore.Delete(ctx, outboxCollection, event.ID)
+	case event.Attempts >= MaxDispatchAttempts:
+		return o.deadLetter(ctx, event, failed)
+	default:
+		return o.firestore.Update(ctx, outboxCollection, event.ID, []firestore.Update{
+			{Path: "nextAttemptAt", Value: o.now().Add(RetryDelay(event.Attempts))},
+			{Path: "lastError", Value: failed[0].String()},
+		})
+	}
+}
+
+type subscriberFailure struct {
+	name string
+	err  error
+}
+
+func (f subscriberFailure) String() string {
+	return truncate(f.name+": "+f.err.Error(), 500)
+}
+
+// pending returns the subscribers that still have to handle event.
+func (o *Outbox) pending(event models.OutboxEvent) []subscription {
+	var pending []subscription
+	for _, sub := range o.subscribers {
+		if event.Subscriber != "" && event.Subscriber != sub.name {
+			continue
+		}
+		handled := false
+		for _, name := range event.Handled {
+			handled = handled || name == sub.name
+		}
+		if !handled {
+			pending = append(pending, sub)
+		}
+	}
+	return pending
+}
+
+// handle calls a subscriber, turning a panic into an error so one bad
+// subscriber cannot stop the dispatcher.
+func handle(ctx context.Context, sub subscription, event DomainEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.fn(ctx, event)
+}
+
+// deadLetter moves an event that ran out of attempts to the dead-letter
+// store, once for each subscriber that failed it.
+func (o *Outbox) deadLetter(ctx context.Context, event models.OutboxEvent, failed []subscriberFailure) error {
+	ctx, cancel := o.firestore.WithDeadline(ctx, true)
+	defer cancel()
+
+	now := o.now()
+	err := o.firestore.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, f := range failed {
+			letter := models.DeadLetter{
+				EventID:    event.EventID,
+				Type:       event.Type,
+				SubjectID:  event.SubjectID,
+				AttendeeID: event.AttendeeID,
+				Payload:    event.Payload,
+				OccurredAt: event.OccurredAt,
+				Subscriber: f.name,
+				Attempts:   event.Attempts,
+				Error:      truncate(f.err.Error(), 500),
+				FailedAt:   now,
+			}
+			if err := tx.Create(o.firestore.GetCollection(deadLettersCollection).NewDoc(), letter); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(o.firestore.GetCollection(outboxCollection).Doc(event.ID))
+	})
+	if err == nil {
+		slog.Error("Domain event dead-lettered", "eventId", event.EventID, "event", event.Type, "subscribers", len(failed))
+	}
+	return err
+}
+
+// DeadLetters returns the most recent dead letters, newest first.
+func (o *Outbox) DeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	query := o.firestore.GetCollection(deadLettersCollection).
+		OrderBy("failedAt", firestore.Desc).
+		Limit(maxDeadLetters)
+	letters := []models.DeadLetter{}
+	err := o.firestore.Documents(ctx, deadLettersCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		var letter models.DeadLetter
+		if err := doc.DataTo(&letter); err != nil {
+			return err
+		}
+		letter.ID = doc.Ref.ID
+		letters = append(letters, letter)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return letters, nil
+}
+
+// Redrive puts a dead letter back in the outbox for the subscriber that
+// failed it, with a fresh set of attempts. The event keeps its ID.
+func (o *Outbox) Redrive(ctx context.Context, id string) (models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	err := o.Transact(ctx, func(tx *Tx) error {
+		doc, err := tx.Get(deadLettersCollection, id)
+		if err != nil {
+			return err
+		}
+		var letter models.DeadLetter
+		if err := doc.DataTo(&letter); err != nil {
+			return err
+		}
+		if !o.subscribed(letter.Subscriber) {
+			return status.Errorf(codes.FailedPrecondition, "no subscriber is named %q", letter.Subscriber)
+		}
+
+		now := o.now()
+		event = models.OutboxEvent{
+			ID:            id,
+			EventID:       letter.EventID,
+			Type:          letter.Type,
+			SubjectID:     letter.SubjectID,
+			AttendeeID:    letter.AttendeeID,
+			Payload:       letter.Payload,
+			OccurredAt:    letter.OccurredAt,
+			Subscriber:    letter.Subscriber,
+			Handled:       []string{},
+			NextAttemptAt: &now,
+		}
+		if err := tx.tx.Create(tx.doc(outboxCollection, id), event); err != nil {
+			return err
+		}
+		tx.emitted++
+		return tx.Delete(deadLettersCollection, id)
+	})
+	return event, err
+}
+
+func (o *Outbox) subscribed(name string) bool {
+	for _, sub := range o.subscribers {
+		if sub.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditLog is a subscriber that writes every domain event to the log.
+func AuditLog(ctx context.Context, event DomainEvent) error {
+	slog.Info("Domain event",
+		"eventId", event.ID,
+		"event", event.Type,
+		"subjectId", event.SubjectID,
+		"occurredAt", event.OccurredAt.UTC().Format(time.RFC3339),
+	)
+	return nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutbox_Pending(t *testing.T) {
+	outbox := NewOutbox(nil)
+	noop := func(context.Context, DomainEvent) error { return nil }
+	outbox.Subscribe("audit-log", noop)
+	outbox.Subscribe("webhooks", noop)
+
+	names := func(subs []subscription) []string {
+		var names []string
+		for _, sub := range subs {
+			names = append(names, sub.name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"audit-log", "webhooks"}, names(outbox.pending(models.OutboxEvent{})))
+
+	// A subscriber that handled the event is not called again
+	assert.Equal(t, []string{"webhooks"}, names(outbox.pending(models.OutboxEvent{Handled: []string{"audit-log"}})))
+	assert.Empty(t, outbox.pending(models.OutboxEvent{Handled: []string{"webhooks", "audit-log"}}))
+
+	// A redriven dead letter only goes to the subscriber that failed it
+	assert.Equal(t, []string{"webhooks"}, names(outbox.pending(models.OutboxEvent{Subscriber: "webhooks"})))
+	assert.Empty(t, outbox.pending(models.OutboxEvent{Subscriber: "webhooks", Handled: []string{"webhooks"}}))
+}
+
+func TestHandle(t *testing.T) {
+	failing := subscription{name: "failing", fn: func(context.Context, DomainEvent) error {
+		return errors.New("boom")
+	}}
+	assert.EqualError(t, handle(context.Background(), failing, DomainEvent{}), "boom")
+
+	panicking := subscription{name: "panicking", fn: func(context.Context, DomainEvent) error {
+		panic("nil map")
+	}}
+	assert.EqualError(t, handle(context.Background(), panicking, DomainEvent{}), "panic: nil map")
+}
+
+func TestSubscriberFailure(t *testing.T) {
+	f := subscriberFailure{name: "webhooks", err: errors.New("deadline exceeded")}
+	assert.Equal(t, "webhooks: deadline exceeded", f.String())
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+const privacyRequestsCollection = "privacyRequests"
+
+// maxInValues is the most values Firestore accepts in an "in" filter.
+const maxInValues = 30
+
+// PersonalData describes a collection that holds data about attendees and
+// how export and erasure find and treat it. Every collection that stores
+// anything tied to an attendee must be listed in DefaultPersonalData.
+type PersonalData struct {
+	Collection string
+
+	// Field is matched against the subject's email address, normalized by
+	// NormalizeEmail, or, when ByAttendeeID is set, against the IDs of their
+	// registrations.
+	Field        string
+	ByAttendeeID bool
+
+	// Anonymize returns the updates that strip personal data from a
+	// document while keeping what aggregate stats need. When nil, matching
+	// documents are deleted.
+	Anonymize func(now time.Time) []firestore.Update
+}
+
+// DefaultPersonalData lists the collections holding personal data.
+var DefaultPersonalData = []PersonalData{
+	{Collection: "attendees", Field: "emailNormalized", Anonymize: anonymizeAttendee},
+	{Collection: deliveriesCollection, Field: "attendeeId", ByAttendeeID: true},
+	{Collection: outboxCollection, Field: "attendeeId", ByAttendeeID: true},
+	{Collection: deadLettersCollection, Field: "attendeeId", ByAttendeeID: true},
+	{Collection: "feedback", Field: "attendeeId", ByAttendeeID: true, Anonymize: anonymizeFeedback},
+	{Collection: "liveQuestions", Field: "attendeeId", ByAttendeeID: true},
+	{Collection: "sessionAttendance", Field: "attendeeId", ByAttendeeID: true},
+}
+
+// anonymizeAttendee keeps the designation, status and registration time, so
+// counts and designation stats do not change, and drops everything that
+// identifies the person. Answers to custom questions are dropped too: free
+// text and needs such as dietary requirements can identify someone.
+func anonymizeAttendee(now time.Time) []firestore.Update {
+	return []firestore.Update{
+		{Path: "name", Value: ""},
+		{Path: "email", Value: ""},
+		{Path: "emailNormalized", Value: ""},
+		{Path: "answers", Value: firestore.Delete},
+		{Path: "listPublicly", Value: false},
+		{Path: "erasedAt", Value: now},
+	}
+}
+
+// anonymizeFeedback keeps the rating, so session averages do not change,
+// and drops the comment, which is free text.
+func anonymizeFeedback(now time.Time) []firestore.Update {
+	return []firestore.Update{
+		{Path: "attendeeId", Value: ""},
+		{Path: "comment", Value: firestore.Delete},
+		{Path: "erasedAt", Value: now},
+	}
+}
+
+// PrivacyService exports and erases everything held about an email address
+// and keeps the compliance record of each request.
+type PrivacyService struct {
+	firestore *FirestoreService
+	sources   []PersonalData
+	now       func() time.Time
+}
+
+// NewPrivacyService creates a PrivacyService covering sources.
+func NewPrivacyService(firestore *FirestoreService, sources []PersonalData) *PrivacyService {
+	return &PrivacyService{firestore: firestore, sources: sources, now: time.Now}
+}
+
+// NormalizeEmail returns the form of an address used for matching and
+// hashing.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// HashEmail identifies an address in compliance records without storing it.
+func HashEmail(email string) string {
+	sum := sha256.Sum256([]byte(NormalizeEmail(email)))
+	return hex.EncodeToString(sum[:])
+}
+
+// subjectDoc is one document held about the subject.
+type subjectDoc struct {
+	source PersonalData
+	doc    *firestore.DocumentSnapshot
+}
+
+// find returns every document held about email. Sources keyed by email are
+// read first so the attendee IDs they yield can be used for the rest.
+func (p *PrivacyService) find(ctx context.Context, email string) ([]subjectDoc, error) {
+	var found []subjectDoc
+	var attendeeIDs []string
+	seen := make(map[string]bool)
+
+	collect := func(source PersonalData, query firestore.Query) error {
+		return p.firestore.Documents(ctx, source.Collection, query, func(doc *firestore.DocumentSnapshot) error {
+			if seen[doc.Ref.Path] {
+				return nil
+			}
+			seen[doc.Ref.Path] = true
+			found = append(found, subjectDoc{source: source, doc: doc})
+			if source.Collection == "attendees" {
+				attendeeIDs = append(attendeeIDs, doc.Ref.ID)
+			}
+			return nil
+		})
+	}
+
+	for _, source := range p.sources {
+		if source.ByAttendeeID {
+			continue
+		}
+		query := p.firestore.GetCollection(source.Collection).Where(source.Field, "==", NormalizeEmail(email))
+		if err := collect(source, query); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, source := range p.sources {
+		if !source.ByAttendeeID {
+			continue
+		}
+		for start := 0; start < len(attendeeIDs); start += maxInValues {
+			ids := attendeeIDs[start:min(start+maxInValues, len(attendeeIDs))]
+			query := p.firestore.GetCollection(source.Collection).Where(source.Field, "in", ids)
+			if err := collect(source, query); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// Export returns every document held about email.
+func (p *PrivacyService) Export(ctx context.Context, email string) (models.DataExport, map[string]int, error) {
+	found, err := p.find(ctx, email)
+	if err != nil {
+		return models.DataExport{}, nil, err
+	}
+
+	export := models.DataExport{
+		Email:       NormalizeEmail(email),
+		GeneratedAt: p.now().UTC(),
+		Collections: make(map[string][]map[string]any),
+	}
+	records := make(map[string]int)
+	for _, source := range p.sources {
+		export.Collections[source.Collection] = []map[string]any{}
+	}
+	for _, f := range found {
+		data := f.doc.Data()
+		data["id"] = f.doc.Ref.ID
+		export.Collections[f.source.Collection] = append(export.Collections[f.source.Collection], data)
+		records[f.source.Collection]++
+	}
+	return export, records, nil
+}
+
+// Erase anonymizes or deletes every document held about email, and drops
+// the address from earlier compliance records. It returns how many
+// documents were erased per collection.
+func (p *PrivacyService) Erase(ctx context.Context, email string) (map[string]int, error) {
+	found, err := p.find(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now()
+	records := make(map[string]int)
+	for _, f := range found {
+		if f.source.Anonymize != nil {
+			err = p.firestore.Update(ctx, f.source.Collection, f.doc.Ref.ID, f.source.Anonymize(now))
+		} else {
+			err = p.firestore.Delete(ctx, f.source.Collection, f.doc.Ref.ID)
+		}
+		if err != nil {
+			return records, err
+		}
+		records[f.source.Collection]++
+	}
+
+	query := p.firestore.GetCollection(privacyRequestsCollection).Where("emailHash", "==", HashEmail(email))
+	var recordIDs []string
+	err = p.firestore.Documents(ctx, privacyRequestsCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		recordIDs = append(recordIDs, doc.Ref.ID)
+		return nil
+	})
+	if err != nil {
+		return records, err
+	}
+	for _, id := range recordIDs {
+		if err := p.firestore.Update(ctx, privacyRequestsCollection, id, []firestore.Update{{Path: "email", Value: ""}}); err != nil {
+			return records, err
+		}
+	}
+
+	return records, nil
+}
+
+// Record stores a new compliance record for a request about email.
+func (p *PrivacyService) Record(ctx context.Context, requestType, status, source, email string, records map[string]int) (models.PrivacyRequest, error) {
+	req := models.PrivacyRequest{
+		Type:        requestType,
+		Status:      status,
+		Source:      source,
+		Email:       NormalizeEmail(email),
+		EmailHash:   HashEmail(email),
+		RequestedAt: p.now(),
+		Records:     records,
+	}
+	if status == models.PrivacyCompleted {
+		completedAt := req.RequestedAt
+		req.CompletedAt = &completedAt
+		if requestType == models.PrivacyErasure {
+			req.Email = ""
+		}
+	}
+
+	ref, err := p.firestore.Add(ctx, privacyRequestsCollection, req)
+	if err != nil {
+		return req, err
+	}
+	req.ID = ref.ID
+	return req, nil
+}
+
+// Get returns one compliance record.
+func (p *PrivacyService) Get(ctx context.Context, id string) (models.PrivacyRequest, error) {
+	var req models.PrivacyRequest
+	doc, err := p.firestore.Get(ctx, privacyRequestsCollection, id)
+	if err != nil {
+		return req, err
+	}
+	if err := doc.DataTo(&req); err != nil {
+		return req, err
+	}
+	req.ID = doc.Ref.ID
+	return req, nil
+}
+
+// Pending returns the pending request of the given type about email, if any.
+func (p *PrivacyService) Pending(ctx context.Context, requestType, email string) (*models.PrivacyRequest, error) {
+	query := p.firestore.GetCollection(privacyRequestsCollection).
+		Where("emailHash", "==", HashEmail(email)).
+		Where("type", "==", requestType).
+		Where("status", "==", models.PrivacyPending).
+		Limit(1)
+	var pending *models.PrivacyRequest
+	err := p.firestore.Documents(ctx, privacyRequestsCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		var req models.PrivacyRequest
+		if err := doc.DataTo(&req); err != nil {
+			return err
+		}
+		req.ID = doc.Ref.ID
+		pending = &req
+		return nil
+	})
+	return pending, err
+}
+
+// Complete marks a pending request as done.
+func (p *PrivacyService) Complete(ctx context.Context, req models.PrivacyRequest, records map[string]int) (models.PrivacyRequest, error) {
+	now := p.now()
+	updates := []firestore.Update{
+		{Path: "status", Value: models.PrivacyCompleted},
+		{Path: "completedAt", Value: now},
+		{Path: "records", Value: records},
+	}
+	if req.Type == models.PrivacyErasure {
+		updates = append(updates, firestore.Update{Path: "email", Value: ""})
+		req.Email = ""
+	}
+	if err := p.firestore.Update(ctx, privacyRequestsCollection, req.ID, updates); err != nil {
+		return req, err
+	}
+	req.Status = models.PrivacyCompleted
+	req.CompletedAt = &now
+	req.Records = records
+	return req, nil
+}
+
+// Requests returns every compliance record, newest first.
+func (p *PrivacyService) Requests(ctx context.Context) ([]models.PrivacyRequest, error) {
+	query := p.firestore.GetCollection(privacyRequestsCollection).OrderBy("requestedAt", firestore.Desc)
+	requests := []models.PrivacyRequest{}
+	err := p.firestore.Documents(ctx, privacyRequestsCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		var req models.PrivacyRequest
+		if err := doc.DataTo(&req); err != nil {
+			return err
+		}
+		req.ID = doc.Ref.ID
+		requests = append(requests, req)
+		return nil
+	})
+	return requests, err
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashEmail(t *testing.T) {
+	assert.Equal(t, HashEmail("jane@example.com"), HashEmail("  Jane@Example.com "))
+	assert.NotEqual(t, HashEmail("jane@example.com"), HashEmail("john@example.com"))
+	assert.Len(t, HashEmail("jane@example.com"), 64)
+}
+
+func TestAnonymizeAttendee(t *testing.T) {
+	now := time.Now()
+	updated := make(map[string]any)
+	for _, u := range anonymizeAttendee(now) {
+		updated[u.Path] = u.Value
+	}
+
+	assert.Equal(t, "", updated["name"])
+	assert.Equal(t, "", updated["email"])
+	assert.Equal(t, "", updated["emailNormalized"])
+	assert.Equal(t, false, updated["listPublicly"])
+	assert.Equal(t, firestore.Delete, updated["answers"])
+	assert.Equal(t, now, updated["erasedAt"])
+
+	// Fields aggregate stats rely on are left alone
+	for _, kept := range []string{"designation", "status", "registeredAt"} {
+		assert.NotContains(t, updated, kept)
+	}
+}
+
+func TestAnonymizeFeedback(t *testing.T) {
+	now := time.Now()
+	updated := make(map[string]any)
+	for _, u := range anonymizeFeedback(now) {
+		updated[u.Path] = u.Value
+	}
+
+	assert.Equal(t, "", updated["attendeeId"])
+	assert.Equal(t, firestore.Delete, updated["comment"])
+	assert.Equal(t, now, updated["erasedAt"])
+	assert.NotContains(t, updated, "rating", "ratings stay in session averages")
+}
+
+func TestDefaultPersonalData(t *testing.T) {
+	collections := make(map[string]bool)
+	for _, source := range DefaultPersonalData {
+		assert.NotEmpty(t, source.Field, source.Collection)
+		assert.False(t, collections[source.Collection], "%s listed twice", source.Collection)
+		collections[source.Collection] = true
+	}
+	assert.True(t, collections["attendees"])
+	assert.True(t, collections["feedback"])
+}
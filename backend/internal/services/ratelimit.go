@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"appdirect-ai-workshop/internal/middleware"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FirestoreRateLimitStore keeps rate limit state in Firestore so that limits
+// and lockouts are shared by every instance.
+type FirestoreRateLimitStore struct {
+	firestore *FirestoreService
+}
+
+func NewFirestoreRateLimitStore(firestore *FirestoreService) *FirestoreRateLimitStore {
+	return &FirestoreRateLimitStore{firestore: firestore}
+}
+
+// doc maps a key to a document. Keys may contain characters that are not
+// allowed in document IDs (and emails), so they are hashed.
+func (s *FirestoreRateLimitStore) doc(collection, key string) *firestore.DocumentRef {
+	sum := sha256.Sum256([]byte(key))
+	return s.firestore.GetCollection(collection).Doc(hex.EncodeToString(sum[:]))
+}
+
+func (s *FirestoreRateLimitStore) Take(ctx context.Context, key string, limit middleware.Limit) (bool, time.Duration, error) {
+	ref := s.doc("rate_limits", key)
+	ctx, cancel := s.firestore.WithDeadline(ctx, true)
+	defer cancel()
+
+	var allowed bool
+	var retryAfter time.Duration
+	err := s.firestore.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var bucket middleware.TokenBucket
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&bucket); err != nil {
+				return err
+			}
+		}
+
+		allowed, retryAfter = bucket.Take(time.Now(), limit)
+		return tx.Set(ref, bucket)
+	})
+	return allowed, retryAfter, err
+}
+
+func (s *FirestoreRateLimitStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	ctx, cancel := s.firestore.WithDeadline(ctx, false)
+	defer cancel()
+
+	doc, err := s.doc("login_lockouts", key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var state middleware.LockoutState
+	if err := doc.DataTo(&state); err != nil {
+		return time.Time{}, err
+	}
+	return state.LockedUntil, nil
+}
+
+func (s *FirestoreRateLimitStore) RecordFailure(ctx context.Context, key string, policy middleware.LockoutPolicy) (time.Time, error) {
+	ref := s.doc("login_lockouts", key)
+	ctx, cancel := s.firestore.WithDeadline(ctx, true)
+	defer cancel()
+
+	var lockedUntil time.Time
+	err := s.firestore.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var state middleware.LockoutState
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&state); err != nil {
+				return err
+			}
+		}
+
+		state.Fail(time.Now(), policy)
+		lockedUntil = state.LockedUntil
+		return tx.Set(ref, state)
+	})
+	return lockedUntil, err
+}
+
+func (s *FirestoreRateLimitStore) Reset(ctx context.Context, key string) error {
+	ctx, cancel := s.firestore.WithDeadline(ctx, true)
+	defer cancel()
+
+	_, err := s.doc("login_lockouts", key).Delete(ctx)
+	return err
+}
+
+// Claim creates a document per key, which fails if another instance created
+// it first. A TTL policy on expiresAt lets Firestore delete old claims.
+func (s *FirestoreRateLimitStore) Claim(ctx context.Context, key string, expires time.Time) (bool, error) {
+	ctx, cancel := s.firestore.WithDeadline(ctx, true)
+	defer cancel()
+
+	_, err := s.doc("claims", key).Create(ctx, map[string]any{"expiresAt": expires})
+	if status.Code(err) == codes.AlreadyExists {
+		return false, nil
+	}
+	return err == nil, err
+}
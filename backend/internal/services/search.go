@@ -0,0 +1,231 @@
+package services
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Search result limits.
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// Search scores. Every term of a query must match the attendee's name or
+// email; the attendee scores the best match of each term, plus a bonus when
+// the whole query is their name or email.
+const (
+	scoreExact       = 100 // the whole query is the name or email
+	scoreNameWord    = 60  // a term is a word of the name
+	scoreNamePrefix  = 40  // a word of the name starts with a term
+	scoreEmailPrefix = 30  // the email, or its domain, starts with a term
+	scoreNameSubstr  = 15
+	scoreEmailSubstr = 10
+)
+
+// AttendeeQuery is an attendee search. Text is matched against names and
+// emails, ignoring case and accents; an empty Text matches everyone.
+// Designations, when set, keep only attendees with one of them.
+type AttendeeQuery struct {
+	Text         string
+	Designations []string
+	Limit        int
+}
+
+// AttendeeIndex is an in-memory search index over every attendee. The change
+// feed keeps it in sync with Firestore, so writes made by any instance show
+// up within moments. Erased attendees are left out.
+type AttendeeIndex struct {
+	mu      sync.RWMutex
+	entries map[string]searchEntry
+	ready   bool
+}
+
+// searchEntry is an attendee with its normalized search fields.
+type searchEntry struct {
+	attendee models.Attendee
+	name     string
+	words    []string
+	email    string
+	domain   string
+}
+
+func NewAttendeeIndex() *AttendeeIndex {
+	return &AttendeeIndex{entries: make(map[string]searchEntry)}
+}
+
+// Put adds or replaces an attendee.
+func (x *AttendeeIndex) Put(attendee models.Attendee) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.put(attendee)
+}
+
+func (x *AttendeeIndex) put(attendee models.Attendee) {
+	if attendee.Erased() {
+		delete(x.entries, attendee.ID)
+		return
+	}
+	name := normalizeSearch(attendee.Name)
+	email := normalizeSearch(attendee.Email)
+	_, domain, _ := strings.Cut(email, "@")
+	x.entries[attendee.ID] = searchEntry{
+		attendee: attendee,
+		name:     name,
+		words:    strings.Fields(name),
+		email:    email,
+		domain:   domain,
+	}
+}
+
+// Apply updates the index with attendee changes from the change feed. The
+// index is ready once it has seen the first snapshot.
+func (x *AttendeeIndex) Apply(changes []DocumentChange) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, change := range changes {
+		if change.Doc == nil {
+			delete(x.entries, change.ID)
+			continue
+		}
+		var attendee models.Attendee
+		if err := change.Doc.DataTo(&attendee); err != nil {
+			slog.Error("Failed to index attendee", "id", change.ID, "error", err)
+			continue
+		}
+		attendee.ID = change.ID
+		x.put(attendee)
+	}
+	x.ready = true
+}
+
+// Ready reports whether the index holds every attendee.
+func (x *AttendeeIndex) Ready() bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.ready
+}
+
+// Search returns the best matches for q, best first, and how many
+// attendees matched in all. Ties are broken by name.
+func (x *AttendeeIndex) Search(q AttendeeQuery) ([]models.AttendeeMatch, int) {
+	text := normalizeSearch(q.Text)
+	terms := strings.Fields(text)
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	x.mu.RLock()
+	var matches []models.AttendeeMatch
+	var names []string
+	for _, entry := range x.entries {
+		if !matchDesignation(entry.attendee.Designation, q.Designations) {
+			continue
+		}
+		score, ok := entry.score(text, terms)
+		if !ok {
+			continue
+		}
+		matches = append(matches, models.AttendeeMatch{Attendee: entry.attendee, Score: score})
+		names = append(names, entry.name)
+	}
+	x.mu.RUnlock()
+
+	sort.Sort(byScore{matches, names})
+	total := len(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	if matches == nil {
+		matches = []models.AttendeeMatch{}
+	}
+	return matches, total
+}
+
+// score rates how well the entry matches a normalized query. It reports
+// false if any term matches neither the name nor the email.
+func (e searchEntry) score(text string, terms []string) (int, bool) {
+	score := 0
+	if text != "" && (text == e.name || text == e.email) {
+		score += scoreExact
+	}
+	for _, term := range terms {
+		best := 0
+		for _, word := range e.words {
+			switch {
+			case word == term:
+				best = max(best, scoreNameWord)
+			case strings.HasPrefix(word, term):
+				best = max(best, scoreNamePrefix)
+			}
+		}
+		switch {
+		case strings.HasPrefix(e.email, term) || strings.HasPrefix(e.domain, term):
+			best = max(best, scoreEmailPrefix)
+		case strings.Contains(e.name, term):
+			best = max(best, scoreNameSubstr)
+		case strings.Contains(e.email, term):
+			best = max(best, scoreEmailSubstr)
+		}
+		if best == 0 {
+			return 0, false
+		}
+		score += best
+	}
+	return score, true
+}
+
+func matchDesignation(designation string, designations []string) bool {
+	if len(designations) == 0 {
+		return true
+	}
+	for _, d := range designations {
+		if strings.EqualFold(strings.TrimSpace(d), designation) {
+			return true
+		}
+	}
+	return false
+}
+
+// byScore sorts matches best first, then by normalized name.
+type byScore struct {
+	matches []models.AttendeeMatch
+	names   []string
+}
+
+func (s byScore) Len() int { return len(s.matches) }
+
+func (s byScore) Less(i, j int) bool {
+	if s.matches[i].Score != s.matches[j].Score {
+		return s.matches[i].Score > s.matches[j].Score
+	}
+	if s.names[i] != s.names[j] {
+		return s.names[i] < s.names[j]
+	}
+	return s.matches[i].ID < s.matches[j].ID
+}
+
+func (s byScore) Swap(i, j int) {
+	s.matches[i], s.matches[j] = s.matches[j], s.matches[i]
+	s.names[i], s.names[j] = s.names[j], s.names[i]
+}
+
+// normalizeSearch lowercases s, strips accents and collapses whitespace, so
+// "José  Núñez" and "jose nunez" compare equal.
+func normalizeSearch(s string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		folded = s
+	}
+	return strings.Join(strings.Fields(strings.ToLower(folded)), " ")
+}
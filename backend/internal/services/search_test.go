@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testIndex() *AttendeeIndex {
+	index := NewAttendeeIndex()
+	for _, a := range []models.Attendee{
+		{ID: "1", Name: "Jane Doe", Email: "jane.doe@appdirect.com", Designation: "Engineer"},
+		{ID: "2", Name: "Janet Smith", Email: "janet@example.com", Designation: "Product Manager"},
+		{ID: "3", Name: "Rajan Iyer", Email: "rajan@appdirect.com", Designation: "Engineer"},
+		{ID: "4", Name: "José Núñez", Email: "jose@example.org", Designation: "Designer"},
+	} {
+		index.Put(a)
+	}
+	return index
+}
+
+func ids(matches []models.AttendeeMatch) []string {
+	out := []string{}
+	for _, m := range matches {
+		out = append(out, m.ID)
+	}
+	return out
+}
+
+func TestAttendeeIndex_Search(t *testing.T) {
+	index := testIndex()
+
+	tests := []struct {
+		name  string
+		query AttendeeQuery
+		want  []string
+	}{
+		{"whole word ranks above prefix and substring", AttendeeQuery{Text: "jan"}, []string{"1", "2", "3"}},
+		{"prefix", AttendeeQuery{Text: "Jane"}, []string{"1", "2"}},
+		{"every term must match", AttendeeQuery{Text: "jane smith"}, []string{"2"}},
+		{"exact name first", AttendeeQuery{Text: "janet smith"}, []string{"2"}},
+		{"email domain", AttendeeQuery{Text: "appdirect"}, []string{"1", "3"}},
+		{"email substring", AttendeeQuery{Text: "doe@app"}, []string{"1"}},
+		{"case and accents are ignored", AttendeeQuery{Text: "NUNEZ"}, []string{"4"}},
+		{"designation filter", AttendeeQuery{Text: "jan", Designations: []string{"engineer"}}, []string{"1", "3"}},
+		{"empty text lists by name", AttendeeQuery{}, []string{"1", "2", "4", "3"}},
+		{"no match", AttendeeQuery{Text: "zzz"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, total := index.Search(tt.query)
+			assert.Equal(t, tt.want, ids(matches))
+			assert.Equal(t, len(tt.want), total)
+		})
+	}
+}
+
+func TestAttendeeIndex_SearchLimit(t *testing.T) {
+	matches, total := testIndex().Search(AttendeeQuery{Text: "jan", Limit: 1})
+	assert.Equal(t, []string{"1"}, ids(matches))
+	assert.Equal(t, 3, total)
+}
+
+func TestAttendeeIndex_Apply(t *testing.T) {
+	index := testIndex()
+	assert.False(t, index.Ready())
+
+	index.Apply([]DocumentChange{{Kind: ChangeRemoved, ID: "1"}})
+	assert.True(t, index.Ready())
+	matches, _ := index.Search(AttendeeQuery{Text: "jane"})
+	assert.Equal(t, []string{"2"}, ids(matches))
+
+	// Erased attendees drop out of the index
+	erasedAt := time.Now()
+	index.Put(models.Attendee{ID: "2", Designation: "Product Manager", ErasedAt: &erasedAt})
+	matches, _ = index.Search(AttendeeQuery{Designations: []string{"Product Manager"}})
+	assert.Empty(t, matches)
+}
+
+func TestNormalizeSearch(t *testing.T) {
+	assert.Equal(t, "jose nunez", normalizeSearch("  José   Núñez "))
+	assert.Equal(t, "jane@example.com", normalizeSearch("Jane@Example.com"))
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// StatsService serves registration stats from a snapshot of the attendee
+// records that is read at most once per TTL, so each dashboard refresh does
+// not read the whole collection. Stats may therefore lag writes by up to
+// the TTL.
+type StatsService struct {
+	firestore *FirestoreService
+	ttl       time.Duration
+	location  *time.Location
+	now       func() time.Time
+
+	// mu is held while the snapshot is read, so concurrent requests share
+	// one read
+	mu        sync.Mutex
+	attendees []models.Attendee
+	loadedAt  time.Time
+}
+
+// NewStatsService creates a StatsService reporting in the event time zone
+// loc. A ttl of zero reads the records on every request.
+func NewStatsService(firestore *FirestoreService, ttl time.Duration, loc *time.Location) *StatsService {
+	return &StatsService{firestore: firestore, ttl: ttl, location: loc, now: time.Now}
+}
+
+// Location returns the event time zone stats are bucketed in.
+func (s *StatsService) Location() *time.Location {
+	return s.location
+}
+
+// Attendees returns the snapshot and when it was read. Names and email
+// addresses are dropped from it; the stats do not need them.
+func (s *StatsService) Attendees(ctx context.Context) ([]models.Attendee, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && s.now().Sub(s.loadedAt) < s.ttl {
+		return s.attendees, s.loadedAt, nil
+	}
+
+	var attendees []models.Attendee
+	err := s.firestore.All(ctx, "attendees", func(doc *firestore.DocumentSnapshot) error {
+		var attendee models.Attendee
+		if err := doc.DataTo(&attendee); err != nil {
+			return err
+		}
+		attendee.ID = doc.Ref.ID
+		attendee.Name = ""
+		attendee.Email = ""
+		attendees = append(attendees, attendee)
+		return nil
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	s.attendees = attendees
+	s.loadedAt = s.now()
+	return s.attendees, s.loadedAt, nil
+}
@@ -0,0 +1,648 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	webhooksCollection   = "webhooks"
+	deliveriesCollection = "webhookDeliveries"
+)
+
+// Headers sent with every delivery.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-ID"
+)
+
+// Delivery scheduling. A delivery is retried with exponential backoff,
+// starting at firstRetryDelay, until MaxDeliveryAttempts have failed, which
+// spreads the attempts over about an hour. While an attempt is in flight
+// the delivery is leased to one instance; if that instance dies the lease
+// runs out and another instance retries it.
+const (
+	MaxDeliveryAttempts = 8
+	firstRetryDelay     = 30 * time.Second
+	maxRetryDelay       = time.Hour
+	deliveryTimeout     = 10 * time.Second
+	deliveryLease       = time.Minute
+	deliveryBatch       = 20
+	deliveryPoll        = 5 * time.Second
+	maxDeliveryLog      = 100
+)
+
+// ErrInsecureWebhook is returned when a webhook would be delivered over
+// plain http or to an address that is not public.
+var ErrInsecureWebhook = errors.New("webhooks must use https and a public address")
+
+// ErrBadSignature is returned by VerifyWebhookSignature when a delivery was
+// not signed with the secret, or was signed too long ago.
+// ErrDeliveryInFlight is returned when replaying a delivery that is being
+// sent.
+var ErrDeliveryInFlight = errors.New("the delivery is being sent")
+
+var ErrBadSignature = errors.New("invalid webhook signature")
+
+// WebhookService queues events for admin-managed webhooks in Firestore and
+// delivers them, signed, in the background. The queue is shared by every
+// instance.
+type WebhookService struct {
+	firestore *FirestoreService
+	client    *http.Client
+	insecure  bool
+	now       func() time.Time
+	wake      chan struct{}
+}
+
+// NewWebhookService creates a WebhookService. Unless allowInsecure is set,
+// deliveries must use https and may only connect to public addresses; the
+// address is checked when dialling, so a hostname that later resolves to
+// an internal address is still refused.
+func NewWebhookService(firestore *FirestoreService, allowInsecure bool) *WebhookService {
+	return &WebhookService{
+		firestore: firestore,
+		client:    newWebhookClient(allowInsecure),
+		insecure:  allowInsecure,
+		now:       time.Now,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// newWebhookClient returns the client deliveries are sent with. It ignores
+// proxy settings, so the dial check sees the receiver's own address.
+func newWebhookClient(allowInsecure bool) *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout}
+	if !allowInsecure {
+		dialer.Control = dialPublicOnly
+	}
+	return &http.Client{
+		Timeout: deliveryTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        20,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: deliveryTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("stopped after 5 redirects")
+			}
+			return CheckWebhookURL(req.URL, allowInsecure)
+		},
+	}
+}
+
+// dialPublicOnly refuses connections to addresses that are not public.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !PublicIP(ip) {
+		return fmt.Errorf("%w: %s is not public", ErrInsecureWebhook, host)
+	}
+	return nil
+}
+
+// sharedAddressSpace is 100.64.0.0/10, used for carrier-grade NAT and by
+// some cloud networks.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// PublicIP reports whether a webhook may be delivered to ip. Loopback,
+// private, link-local (including the metadata server at 169.254.169.254),
+// shared, unspecified and multicast addresses are refused.
+func PublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// CheckWebhookURL returns ErrInsecureWebhook if u does not use https or
+// names a host that is never public, unless allowInsecure is set. Hostnames
+// are only resolved when dialling.
+func CheckWebhookURL(u *url.URL, allowInsecure bool) error {
+	if allowInsecure {
+		return nil
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: %s is not https", ErrInsecureWebhook, u.Redacted())
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return fmt.Errorf("%w: %s is not public", ErrInsecureWebhook, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !PublicIP(ip) {
+		return fmt.Errorf("%w: %s is not public", ErrInsecureWebhook, host)
+	}
+	return nil
+}
+
+// NewWebhookSecret returns a random signing secret.
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// SignWebhook returns the signature header value for body sent at t:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">".
+// Including the time lets receivers reject replayed requests.
+func SignWebhook(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + webhookMAC(secret, ts, body)
+}
+
+func webhookMAC(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a signature header as a receiver would,
+// rejecting signatures older than tolerance.
+func VerifyWebhookSignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrBadSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrBadSignature
+	}
+	if !hmac.Equal([]byte(sig), []byte(webhookMAC(secret, ts, body))) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// RetryDelay is how long to wait after the given failed attempt, counting
+// from 1.
+func RetryDelay(attempt int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// Webhooks returns every webhook, secrets included.
+func (s *WebhookService) Webhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks := []models.Webhook{}
+	err := s.firestore.All(ctx, webhooksCollection, func(doc *firestore.DocumentSnapshot) error {
+		var webhook models.Webhook
+		if err := doc.DataTo(&webhook); err != nil {
+			return err
+		}
+		webhook.ID = doc.Ref.ID
+		webhooks = append(webhooks, webhook)
+		return nil
+	})
+	return webhooks, err
+}
+
+// Webhook returns one webhook, secret included.
+func (s *WebhookService) Webhook(ctx context.Context, id string) (models.Webhook, error) {
+	var webhook models.Webhook
+	doc, err := s.firestore.Get(ctx, webhooksCollection, id)
+	if err != nil {
+		return webhook, err
+	}
+	if err := doc.DataTo(&webhook); err != nil {
+		return webhook, err
+	}
+	webhook.ID = doc.Ref.ID
+	return webhook, nil
+}
+
+// CreateWebhook stores a new webhook with a fresh signing secret.
+func (s *WebhookService) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	secret, err := NewWebhookSecret()
+	if err != nil {
+		return webhook, err
+	}
+	webhook.Secret = secret
+	webhook.CreatedAt = s.now()
+
+	ref, err := s.firestore.Add(ctx, webhooksCollection, webhook)
+	if err != nil {
+		return webhook, err
+	}
+	webhook.ID = ref.ID
+	return webhook, nil
+}
+
+// UpdateWebhook changes where a webhook is sent, what it receives and
+// whether it is active. The secret is kept.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id string, updates []firestore.Update) (models.Webhook, error) {
+	if err := s.firestore.Update(ctx, webhooksCollection, id, updates); err != nil {
+		return models.Webhook{}, err
+	}
+	return s.Webhook(ctx, id)
+}
+
+// DeleteWebhook removes a webhook. Its queued deliveries fail when they
+// come up, and its delivery log is kept.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	return s.firestore.Delete(ctx, webhooksCollection, id)
+}
+
+// HandleEvent is the outbox subscriber that queues a domain event for every
+// active webhook subscribed to it. Deliveries are keyed by event and
+// webhook, so an event handled twice is still delivered once.
+func (s *WebhookService) HandleEvent(ctx context.Context, event DomainEvent) error {
+	webhooks, err := s.Webhooks(ctx)
+	if err != nil {
+		return err
+	}
+	var subscribed []models.Webhook
+	for _, webhook := range webhooks {
+		if webhook.Subscribed(event.Type) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	webhookEvent := models.WebhookEvent{ID: "evt_" + event.ID, Type: event.Type, CreatedAt: event.OccurredAt.UTC(), Data: event.Payload}
+	payload, err := json.Marshal(webhookEvent)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, webhook := range subscribed {
+		delivery := models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       webhookEvent.ID,
+			Event:         event.Type,
+			Payload:       string(payload),
+			AttendeeID:    event.AttendeeID,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		}
+		err := s.firestore.Create(ctx, deliveriesCollection, event.ID+"-"+webhook.ID, delivery)
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			return err
+		}
+	}
+
+	// Deliver now rather than at the next poll
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// newWebhookEvent builds an event with a random ID, for test deliveries.
+func newWebhookEvent(eventType string, data any, now time.Time) (models.WebhookEvent, []byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return models.WebhookEvent{}, nil, err
+	}
+	event := models.WebhookEvent{ID: "evt_" + hex.EncodeToString(id), Type: eventType, CreatedAt: now.UTC(), Data: data}
+	payload, err := json.Marshal(event)
+	return event, payload, err
+}
+
+// Run delivers queued events until ctx is done.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(deliveryPoll)
+	defer ticker.Stop()
+	for {
+		if err := s.deliverDue(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to deliver webhooks", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// deliverDue attempts every delivery whose next attempt is due.
+func (s *WebhookService) deliverDue(ctx context.Context) error {
+	query := s.firestore.GetCollection(deliveriesCollection).
+		Where("nextAttemptAt", "<=", s.now()).
+		OrderBy("nextAttemptAt", firestore.Asc).
+		Limit(deliveryBatch)
+	var due []string
+	err := s.firestore.Documents(ctx, deliveriesCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		due = append(due, doc.Ref.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range due {
+		delivery, ok, err := s.claim(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err := s.attempt(ctx, delivery); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// claim leases a due delivery to this instance and counts the attempt. It
+// reports false if another instance got there first.
+func (s *WebhookService) claim(ctx context.Context, id string) (models.WebhookDelivery, bool, error) {
+	ref := s.firestore.GetCollection(deliveriesCollection).Doc(id)
+	ctx, cancel := s.firestore.WithDeadline(ctx, true)
+	defer cancel()
+
+	var delivery models.WebhookDelivery
+	claimed := false
+	err := s.firestore.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&delivery); err != nil {
+			return err
+		}
+		delivery.ID = doc.Ref.ID
+
+		now := s.now()
+		if delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) {
+			return nil
+		}
+		lease := now.Add(deliveryLease)
+		delivery.Attempts++
+		delivery.NextAttemptAt = &lease
+		delivery.LeasedUntil = &lease
+		claimed = true
+		return tx.Update(ref, []firestore.Update{
+			{Path: "attempts", Value: delivery.Attempts},
+			{Path: "nextAttemptAt", Value: lease},
+			{Path: "leasedUntil", Value: lease},
+		})
+	})
+	return delivery, claimed, err
+}
+
+// attempt sends a claimed delivery and records the outcome, scheduling a
+// retry if it failed and attempts are left.
+func (s *WebhookService) attempt(ctx context.Context, delivery models.WebhookDelivery) error {
+	var result deliveryResult
+	webhook, err := s.Webhook(ctx, delivery.WebhookID)
+	switch {
+	case status.Code(err) == codes.NotFound:
+		result = deliveryResult{err: errors.New("the webhook was deleted"), final: true}
+	case err != nil:
+		return err
+	case !webhook.Active:
+		result = deliveryResult{err: errors.New("the webhook is disabled"), final: true}
+	default:
+		result = s.send(ctx, webhook, delivery)
+	}
+
+	updates := result.record(&delivery, s.now())
+	if result.err != nil {
+		slog.Warn("Webhook delivery failed", "deliveryId", delivery.ID, "webhookId", delivery.WebhookID, "event", delivery.Event, "attempt", delivery.Attempts, "error", result.err)
+	}
+	return s.firestore.Update(ctx, deliveriesCollection, delivery.ID, updates)
+}
+
+// deliveryResult is the outcome of one attempt. A final failure is not
+// retried.
+type deliveryResult struct {
+	status   int
+	err      error
+	duration time.Duration
+	final    bool
+}
+
+// record applies the result to delivery and returns the matching updates.
+func (r deliveryResult) record(delivery *models.WebhookDelivery, now time.Time) []firestore.Update {
+	delivery.LastAttemptAt = &now
+	delivery.LeasedUntil = nil
+	delivery.ResponseStatus = r.status
+	delivery.DurationMs = r.duration.Milliseconds()
+	delivery.Error = ""
+	if r.err != nil {
+		delivery.Error = truncate(r.err.Error(), 500)
+	}
+
+	var next any = firestore.Delete
+	switch {
+	case r.err == nil:
+		delivery.Status = models.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+	case r.final || delivery.Attempts >= MaxDeliveryAttempts:
+		delivery.Status = models.DeliveryFailed
+		delivery.NextAttemptAt = nil
+	default:
+		retryAt := now.Add(RetryDelay(delivery.Attempts))
+		delivery.Status = models.DeliveryPending
+		delivery.NextAttemptAt = &retryAt
+		next = retryAt
+	}
+
+	return []firestore.Update{
+		{Path: "status", Value: delivery.Status},
+		{Path: "nextAttemptAt", Value: next},
+		{Path: "leasedUntil", Value: firestore.Delete},
+		{Path: "lastAttemptAt", Value: now},
+		{Path: "responseStatus", Value: delivery.ResponseStatus},
+		{Path: "error", Value: delivery.Error},
+		{Path: "durationMs", Value: delivery.DurationMs},
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// send POSTs the delivery's payload to the webhook. Any 2xx response is a
+// success.
+func (s *WebhookService) send(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery) deliveryResult {
+	started := s.now()
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return deliveryResult{err: err}
+	}
+	// Webhooks saved before the check existed may still use http
+	if err := CheckWebhookURL(req.URL, s.insecure); err != nil {
+		return deliveryResult{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "appdirect-ai-workshop-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookIDHeader, delivery.EventID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(webhook.Secret, started, body))
+
+	resp, err := s.client.Do(req)
+	result := deliveryResult{duration: time.Since(started)}
+	if err != nil {
+		result.err = err
+		return result
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	result.status = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		result.err = fmt.Errorf("the receiver answered %d", resp.StatusCode)
+	}
+	return result
+}
+
+// Test sends a webhook.test event to the webhook straight away and logs it
+// as a delivery. It is not retried.
+func (s *WebhookService) Test(ctx context.Context, webhook models.Webhook) (models.WebhookDelivery, error) {
+	now := s.now()
+	event, payload, err := newWebhookEvent(models.EventWebhookTest, map[string]string{"webhookId": webhook.ID}, now)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	delivery := models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		Event:     event.Type,
+		Payload:   string(payload),
+		Attempts:  1,
+		CreatedAt: now,
+	}
+	result := s.send(ctx, webhook, delivery)
+	result.final = true
+	result.record(&delivery, s.now())
+
+	ref, err := s.firestore.Add(ctx, deliveriesCollection, delivery)
+	if err != nil {
+		return delivery, err
+	}
+	delivery.ID = ref.ID
+	return delivery, nil
+}
+
+// Replay queues a delivery to be sent again with a fresh set of attempts.
+// The event keeps its ID, so receivers that already processed it can tell.
+// A delivery that is being sent cannot be replayed until the attempt is
+// recorded or its lease runs out.
+func (s *WebhookService) Replay(ctx context.Context, webhookID, id string) (models.WebhookDelivery, error) {
+	ref := s.firestore.GetCollection(deliveriesCollection).Doc(id)
+	ctx, cancel := s.firestore.WithDeadline(ctx, true)
+	defer cancel()
+
+	var delivery models.WebhookDelivery
+	err := s.firestore.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		delivery = models.WebhookDelivery{}
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&delivery); err != nil {
+			return err
+		}
+		delivery.ID = doc.Ref.ID
+		if delivery.WebhookID != webhookID {
+			return status.Error(codes.NotFound, "the delivery is not for this webhook")
+		}
+
+		now := s.now()
+		if delivery.Leased(now) {
+			return ErrDeliveryInFlight
+		}
+		delivery.Status = models.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = &now
+		delivery.LeasedUntil = nil
+		return tx.Update(ref, []firestore.Update{
+			{Path: "status", Value: models.DeliveryPending},
+			{Path: "attempts", Value: 0},
+			{Path: "nextAttemptAt", Value: now},
+			{Path: "leasedUntil", Value: firestore.Delete},
+		})
+	})
+	if err != nil {
+		return delivery, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return delivery, nil
+}
+
+// Deliveries returns the most recent deliveries to a webhook, newest first.
+func (s *WebhookService) Deliveries(ctx context.Context, webhookID string) ([]models.WebhookDelivery, error) {
+	query := s.firestore.GetCollection(deliveriesCollection).Where("webhookId", "==", webhookID)
+	deliveries := []models.WebhookDelivery{}
+	err := s.firestore.Documents(ctx, deliveriesCollection, query, func(doc *firestore.DocumentSnapshot) error {
+		var delivery models.WebhookDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			return err
+		}
+		delivery.ID = doc.Ref.ID
+		deliveries = append(deliveries, delivery)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Sorted here rather than in the query, which would need a composite
+	// index
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if len(deliveries) > maxDeliveryLog {
+		deliveries = deliveries[:maxDeliveryLog]
+	}
+	return deliveries, nil
+}
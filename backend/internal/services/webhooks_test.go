@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"appdirect-ai-workshop/internal/models"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	now := time.Unix(1767225600, 0)
+	body := []byte(`{"type":"attendee.created"}`)
+	header := SignWebhook("whsec_test", now, body)
+	assert.Regexp(t, `^t=1767225600,v1=[0-9a-f]{64}$`, header)
+
+	assert.NoError(t, VerifyWebhookSignature("whsec_test", header, body, now.Add(time.Minute), 5*time.Minute))
+	assert.ErrorIs(t, VerifyWebhookSignature("whsec_other", header, body, now, 5*time.Minute), ErrBadSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("whsec_test", header, []byte(`{}`), now, 5*time.Minute), ErrBadSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("whsec_test", header, body, now.Add(10*time.Minute), 5*time.Minute), ErrBadSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("whsec_test", "v1=abc", body, now, 5*time.Minute), ErrBadSignature)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(1))
+	assert.Equal(t, time.Minute, RetryDelay(2))
+	assert.Equal(t, 32*time.Minute, RetryDelay(7))
+	assert.Equal(t, time.Hour, RetryDelay(8))
+	assert.Equal(t, time.Hour, RetryDelay(50))
+}
+
+func TestWebhookService_Send(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	status := http.StatusNoContent
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	s := &WebhookService{client: receiver.Client(), insecure: true, now: time.Now}
+	webhook := models.Webhook{ID: "w1", URL: receiver.URL, Secret: "whsec_test"}
+	delivery := models.WebhookDelivery{EventID: "evt_1", Event: models.EventAttendeeCreated, Payload: `{"id":"evt_1"}`}
+
+	result := s.send(context.Background(), webhook, delivery)
+	require.NoError(t, result.err)
+	assert.Equal(t, http.StatusNoContent, result.status)
+	assert.Equal(t, `{"id":"evt_1"}`, string(body))
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, models.EventAttendeeCreated, received.Header.Get(WebhookEventHeader))
+	assert.Equal(t, "evt_1", received.Header.Get(WebhookIDHeader))
+	assert.NoError(t, VerifyWebhookSignature("whsec_test", received.Header.Get(WebhookSignatureHeader), body, time.Now(), time.Minute))
+
+	status = http.StatusInternalServerError
+	result = s.send(context.Background(), webhook, delivery)
+	assert.EqualError(t, result.err, "the receiver answered 500")
+	assert.Equal(t, http.StatusInternalServerError, result.status)
+}
+
+func TestPublicIP(t *testing.T) {
+	for _, public := range []string{"203.0.113.10", "8.8.8.8", "2001:4860:4860::8888"} {
+		assert.True(t, PublicIP(net.ParseIP(public)), public)
+	}
+	for _, internal := range []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1",
+		"0.0.0.0", "224.0.0.1", "::1", "fd00::1", "fe80::1", "::ffff:127.0.0.1",
+	} {
+		assert.False(t, PublicIP(net.ParseIP(internal)), internal)
+	}
+}
+
+func TestWebhookService_RefusesInternalAddresses(t *testing.T) {
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the receiver should not be reached")
+	}))
+	defer receiver.Close()
+
+	// A hostname resolving to an internal address passes the URL check, so
+	// the client must refuse to connect on its own
+	_, err := newWebhookClient(false).Get(receiver.URL)
+	assert.ErrorIs(t, err, ErrInsecureWebhook)
+
+	s := NewWebhookService(nil, false)
+	webhook := models.Webhook{URL: strings.Replace(receiver.URL, "https:", "http:", 1), Secret: "whsec_test"}
+	result := s.send(context.Background(), webhook, models.WebhookDelivery{Payload: "{}"})
+	assert.ErrorIs(t, result.err, ErrInsecureWebhook)
+}
+
+func TestDeliveryResult_Record(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	values := func(updates []firestore.Update) map[string]any {
+		m := make(map[string]any)
+		for _, u := range updates {
+			m[u.Path] = u.Value
+		}
+		return m
+	}
+
+	lease := now.Add(time.Minute)
+	delivery := models.WebhookDelivery{Attempts: 2, LeasedUntil: &lease}
+	updates := values(deliveryResult{status: 503, err: assert.AnError}.record(&delivery, now))
+	assert.Equal(t, models.DeliveryPending, delivery.Status)
+	assert.Equal(t, now.Add(time.Minute), *delivery.NextAttemptAt)
+	assert.Equal(t, now.Add(time.Minute), updates["nextAttemptAt"])
+	assert.Equal(t, 503, updates["responseStatus"])
+	assert.Nil(t, delivery.LeasedUntil)
+	assert.Equal(t, firestore.Delete, updates["leasedUntil"])
+
+	delivery = models.WebhookDelivery{Attempts: MaxDeliveryAttempts}
+	updates = values(deliveryResult{err: assert.AnError}.record(&delivery, now))
+	assert.Equal(t, models.DeliveryFailed, delivery.Status)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.Equal(t, firestore.Delete, updates["nextAttemptAt"])
+
+	delivery = models.WebhookDelivery{Attempts: 1}
+	deliveryResult{err: assert.AnError, final: true}.record(&delivery, now)
+	assert.Equal(t, models.DeliveryFailed, delivery.Status)
+
+	delivery = models.WebhookDelivery{Attempts: 3, Error: "earlier"}
+	updates = values(deliveryResult{status: 200}.record(&delivery, now))
+	assert.Equal(t, models.DeliverySucceeded, delivery.Status)
+	assert.Equal(t, "", updates["error"])
+	assert.Equal(t, firestore.Delete, updates["nextAttemptAt"])
+}
@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	for _, exporter := range []string{"", ExporterNone, ExporterStdout} {
+		shutdown, err := Setup(context.Background(), exporter)
+		assert.NoError(t, err, exporter)
+		assert.NoError(t, shutdown(context.Background()))
+	}
+
+	_, err := Setup(context.Background(), "zipkin")
+	assert.Error(t, err)
+}
+
+func TestTraceparentPropagation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	_, err := Setup(context.Background(), ExporterNone)
+	assert.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	router := gin.New()
+	router.Use(otelgin.Middleware(ServiceName))
+	router.GET("/api/sessions", func(c *gin.Context) {
+		_, span := Tracer().Start(c.Request.Context(), "child")
+		span.End()
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/api/sessions", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	}
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
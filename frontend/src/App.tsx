@@ -6,10 +6,38 @@ import Location from './components/Location';
 import Footer from './components/Footer';
 import AdminLogin from './components/AdminLogin';
 import AdminDashboard from './components/AdminDashboard';
+import ManageRegistration from './components/ManageRegistration';
+import SpeakerFeedback from './components/SpeakerFeedback';
+
+// Magic links point at /#manage=<token> and speaker feedback links at
+// /#speaker=<token>; the fragment never reaches the server.
+const tokenFromHash = (name: string): string | null => {
+  const match = window.location.hash.match(new RegExp(`^#${name}=(.+)$`));
+  return match ? match[1] : null;
+};
+
+// Drop a link token from the address bar and history
+const clearHash = () => {
+  window.history.replaceState(null, '', window.location.pathname + window.location.search);
+};
 
 function App() {
   const [showAdminLogin, setShowAdminLogin] = useState(false);
   const [isAdminAuthenticated, setIsAdminAuthenticated] = useState(false);
+  const [manageToken, setManageToken] = useState(() => tokenFromHash('manage'));
+  const [speakerToken, setSpeakerToken] = useState(() => tokenFromHash('speaker'));
+  const [showManage, setShowManage] = useState(manageToken !== null);
+
+  const handleManageClose = () => {
+    setShowManage(false);
+    setManageToken(null);
+    clearHash();
+  };
+
+  const handleSpeakerClose = () => {
+    setSpeakerToken(null);
+    clearHash();
+  };
 
   const handleAdminLoginSuccess = () => {
     setIsAdminAuthenticated(true);
@@ -30,9 +58,13 @@ function App() {
     <div className="min-h-screen">
       <Hero />
       <SessionsSpeakers />
-      <RegistrationForm />
+      <RegistrationForm onManageClick={() => setShowManage(true)} />
       <Location />
       <Footer onAdminClick={() => setShowAdminLogin(true)} />
+      {showManage && (
+        <ManageRegistration token={manageToken} onClose={handleManageClose} />
+      )}
+      {speakerToken && <SpeakerFeedback token={speakerToken} onClose={handleSpeakerClose} />}
       {showAdminLogin && (
         <AdminLogin
           onClose={() => setShowAdminLogin(false)}
@@ -3,43 +3,102 @@ import AttendeeList from './AttendeeList';
 import SpeakerManagement from './SpeakerManagement';
 import SessionManagement from './SessionManagement';
 import PieChart from './PieChart';
-import { getAdminStats } from '../services/api';
-import type { DesignationStats } from '../types';
+import PrivacyRequests from './PrivacyRequests';
+import QuestionManagement from './QuestionManagement';
+import DesignationManagement from './DesignationManagement';
+import WebhookManagement from './WebhookManagement';
+import VenueManagement from './VenueManagement';
+import FeedbackReport from './FeedbackReport';
+import QAModeration from './QAModeration';
+import SessionCheckIn from './SessionCheckIn';
+import QuestionStatsList from './QuestionStatsList';
+import RegistrationTrend from './RegistrationTrend';
+import { getAdminStats, getDesignationOptions } from '../services/api';
+import type { DesignationStats, Funnel, QuestionStats, StatsFilters, Timeline } from '../types';
 
 interface AdminDashboardProps {
   onLogout: () => void;
 }
 
-type Tab = 'attendees' | 'speakers' | 'sessions' | 'analytics';
+type Tab =
+  | 'attendees'
+  | 'speakers'
+  | 'sessions'
+  | 'venues'
+  | 'checkin'
+  | 'feedback'
+  | 'qa'
+  | 'questions'
+  | 'designations'
+  | 'analytics'
+  | 'webhooks'
+  | 'privacy';
 
 const AdminDashboard = ({ onLogout }: AdminDashboardProps) => {
   const [activeTab, setActiveTab] = useState<Tab>('attendees');
   const [stats, setStats] = useState<DesignationStats[]>([]);
+  const [questionStats, setQuestionStats] = useState<QuestionStats[]>([]);
+  const [funnel, setFunnel] = useState<Funnel | null>(null);
+  const [timeline, setTimeline] = useState<Timeline | null>(null);
+  const [designations, setDesignations] = useState<string[]>([]);
+  const [filters, setFilters] = useState<StatsFilters>({ interval: 'day' });
   const [loadingStats, setLoadingStats] = useState(true);
+  const [statsError, setStatsError] = useState<string | null>(null);
 
   useEffect(() => {
     if (activeTab === 'analytics') {
       fetchStats();
     }
+  }, [activeTab, filters]);
+
+  useEffect(() => {
+    if (activeTab === 'analytics') {
+      getDesignationOptions()
+        .then(setDesignations)
+        .catch((err) => console.error('Failed to load designations:', err));
+    }
   }, [activeTab]);
 
   const fetchStats = async () => {
     try {
       setLoadingStats(true);
-      const data = await getAdminStats();
-      setStats(data);
-    } catch (err) {
+      setStatsError(null);
+      const data = await getAdminStats(filters);
+      setStats(data.designations);
+      setQuestionStats(data.questions);
+      setFunnel(data.funnel);
+      setTimeline(data.timeline);
+    } catch (err: any) {
+      setStatsError(err.response?.data?.error || 'Failed to load statistics');
       console.error('Failed to load stats:', err);
     } finally {
       setLoadingStats(false);
     }
   };
 
+  const funnelCards = funnel
+    ? [
+        { label: 'Registered', value: funnel.registered },
+        { label: 'Confirmed', value: funnel.confirmed },
+        { label: 'Pending', value: funnel.pending },
+        { label: 'Cancelled', value: funnel.cancelled },
+        { label: 'Checked in', value: `${funnel.checkedIn} (${Math.round(funnel.checkInRate * 100)}%)` },
+      ]
+    : [];
+
   const tabs: { id: Tab; label: string }[] = [
     { id: 'attendees', label: 'Attendees' },
     { id: 'speakers', label: 'Speakers' },
     { id: 'sessions', label: 'Sessions' },
+    { id: 'venues', label: 'Venues & Tracks' },
+    { id: 'checkin', label: 'Session Check-in' },
+    { id: 'feedback', label: 'Feedback' },
+    { id: 'qa', label: 'Live Q&A' },
+    { id: 'questions', label: 'Questions' },
+    { id: 'designations', label: 'Designations' },
     { id: 'analytics', label: 'Analytics' },
+    { id: 'webhooks', label: 'Webhooks' },
+    { id: 'privacy', label: 'Data Requests' },
   ];
 
   return (
@@ -76,21 +135,122 @@ const AdminDashboard = ({ onLogout }: AdminDashboardProps) => {
         {activeTab === 'attendees' && <AttendeeList />}
         {activeTab === 'speakers' && <SpeakerManagement />}
         {activeTab === 'sessions' && <SessionManagement />}
+        {activeTab === 'venues' && <VenueManagement />}
+        {activeTab === 'checkin' && <SessionCheckIn />}
+        {activeTab === 'feedback' && <FeedbackReport />}
+        {activeTab === 'qa' && <QAModeration />}
+        {activeTab === 'questions' && <QuestionManagement />}
+        {activeTab === 'designations' && <DesignationManagement />}
+        {activeTab === 'webhooks' && <WebhookManagement />}
+        {activeTab === 'privacy' && <PrivacyRequests />}
         {activeTab === 'analytics' && (
           <div>
-            <h3 className="text-2xl font-bold text-white mb-6">
-              Attendee Breakdown by Designation
-            </h3>
-            <div className="card">
-              {loadingStats ? (
-                <div className="text-center py-12">
-                  <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-white mx-auto"></div>
-                  <p className="mt-4 text-gray-300">Loading statistics...</p>
-                </div>
-              ) : (
-                <PieChart data={stats} />
-              )}
+            <div className="card mb-6 grid grid-cols-2 md:grid-cols-4 gap-4">
+              <div>
+                <label htmlFor="stats-from" className="block text-sm font-medium text-gray-300 mb-2">
+                  From
+                </label>
+                <input
+                  id="stats-from"
+                  type="date"
+                  value={filters.from ?? ''}
+                  onChange={(e) => setFilters({ ...filters, from: e.target.value || undefined })}
+                  className="input-field"
+                />
+              </div>
+              <div>
+                <label htmlFor="stats-to" className="block text-sm font-medium text-gray-300 mb-2">
+                  To
+                </label>
+                <input
+                  id="stats-to"
+                  type="date"
+                  value={filters.to ?? ''}
+                  onChange={(e) => setFilters({ ...filters, to: e.target.value || undefined })}
+                  className="input-field"
+                />
+              </div>
+              <div>
+                <label htmlFor="stats-designation" className="block text-sm font-medium text-gray-300 mb-2">
+                  Designation
+                </label>
+                <select
+                  id="stats-designation"
+                  value={filters.designation?.[0] ?? ''}
+                  onChange={(e) =>
+                    setFilters({ ...filters, designation: e.target.value ? [e.target.value] : undefined })
+                  }
+                  className="input-field"
+                >
+                  <option value="" className="bg-slate-800">All</option>
+                  {designations.map((designation) => (
+                    <option key={designation} value={designation} className="bg-slate-800">
+                      {designation}
+                    </option>
+                  ))}
+                </select>
+              </div>
+              <div>
+                <label htmlFor="stats-interval" className="block text-sm font-medium text-gray-300 mb-2">
+                  Interval
+                </label>
+                <select
+                  id="stats-interval"
+                  value={filters.interval}
+                  onChange={(e) => setFilters({ ...filters, interval: e.target.value as StatsFilters['interval'] })}
+                  className="input-field"
+                >
+                  <option value="day" className="bg-slate-800">Daily</option>
+                  <option value="hour" className="bg-slate-800">Hourly</option>
+                </select>
+              </div>
             </div>
+
+            {statsError && (
+              <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-6">
+                {statsError}
+              </div>
+            )}
+
+            {loadingStats ? (
+              <div className="card text-center py-12">
+                <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-white mx-auto"></div>
+                <p className="mt-4 text-gray-300">Loading statistics...</p>
+              </div>
+            ) : (
+              <>
+                <div className="grid grid-cols-2 md:grid-cols-5 gap-4 mb-10">
+                  {funnelCards.map((card) => (
+                    <div key={card.label} className="card text-center">
+                      <p className="text-gray-400 text-sm">{card.label}</p>
+                      <p className="text-2xl font-bold text-white mt-1">{card.value}</p>
+                    </div>
+                  ))}
+                </div>
+
+                <h3 className="text-2xl font-bold text-white mb-6">
+                  Registrations Over Time
+                  {timeline && <span className="text-sm font-normal text-gray-400"> ({timeline.timeZone})</span>}
+                </h3>
+                <div className="card mb-10">
+                  {timeline && <RegistrationTrend timeline={timeline} />}
+                </div>
+
+                <h3 className="text-2xl font-bold text-white mb-6">
+                  Attendee Breakdown by Designation
+                </h3>
+                <div className="card">
+                  <PieChart data={stats} />
+                </div>
+
+                {questionStats.length > 0 && (
+                  <>
+                    <h3 className="text-2xl font-bold text-white mt-10 mb-6">Registration Answers</h3>
+                    <QuestionStatsList stats={questionStats} />
+                  </>
+                )}
+              </>
+            )}
           </div>
         )}
       </div>
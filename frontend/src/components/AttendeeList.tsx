@@ -1,16 +1,62 @@
 import { useEffect, useState } from 'react';
-import { getAttendees } from '../services/api';
+import { getAttendees, checkInAttendee, searchAttendees, subscribeToEvents } from '../services/api';
 import type { Attendee } from '../types';
 
 const AttendeeList = () => {
   const [attendees, setAttendees] = useState<Attendee[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
+  const [search, setSearch] = useState('');
+  // Search results, or null to show everyone
+  const [results, setResults] = useState<Attendee[] | null>(null);
 
   useEffect(() => {
     fetchAttendees();
+
+    // New registrations appear without a refresh
+    return subscribeToEvents(
+      {
+        registration: (attendee) =>
+          setAttendees((prev) => (prev.some((a) => a.id === attendee.id) ? prev : [...prev, attendee])),
+        reset: fetchAttendees,
+      },
+      true
+    );
   }, []);
 
+  // Search as the admin types, once they pause
+  useEffect(() => {
+    const q = search.trim();
+    if (!q) {
+      setResults(null);
+      return;
+    }
+    let stale = false;
+    const timer = setTimeout(async () => {
+      try {
+        const found = await searchAttendees(q);
+        if (!stale) setResults(found.results);
+      } catch (err: any) {
+        if (!stale) setError(err.response?.data?.error || 'Search failed');
+      }
+    }, 200);
+    return () => {
+      stale = true;
+      clearTimeout(timer);
+    };
+  }, [search]);
+
+  const handleCheckIn = async (id: string) => {
+    try {
+      const updated = await checkInAttendee(id);
+      const replace = (list: Attendee[]) => list.map((attendee) => (attendee.id === id ? updated : attendee));
+      setAttendees(replace);
+      setResults((prev) => (prev ? replace(prev) : prev));
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Check-in failed');
+    }
+  };
+
   const fetchAttendees = async () => {
     try {
       setLoading(true);
@@ -49,9 +95,19 @@ const AttendeeList = () => {
     <div>
       <div className="flex justify-between items-center mb-6">
         <h3 className="text-2xl font-bold text-white">Attendees ({attendees.length})</h3>
-        <button onClick={fetchAttendees} className="btn-secondary text-sm">
-          Refresh
-        </button>
+        <div className="flex gap-3">
+          <input
+            type="search"
+            value={search}
+            onChange={(e) => setSearch(e.target.value)}
+            className="input-field w-64"
+            placeholder="Search name or email"
+            maxLength={100}
+          />
+          <button onClick={fetchAttendees} className="btn-secondary text-sm">
+            Refresh
+          </button>
+        </div>
       </div>
 
       <div className="card overflow-hidden p-0">
@@ -71,10 +127,13 @@ const AttendeeList = () => {
                 <th className="px-6 py-3 text-left text-xs font-medium text-gray-300 uppercase tracking-wider">
                   Registered At
                 </th>
+                <th className="px-6 py-3 text-left text-xs font-medium text-gray-300 uppercase tracking-wider">
+                  Check-in
+                </th>
               </tr>
             </thead>
             <tbody className="divide-y divide-white/10">
-              {attendees.map((attendee) => (
+              {(results ?? attendees).map((attendee) => (
                 <tr key={attendee.id} className="hover:bg-white/5 transition-colors">
                   <td className="px-6 py-4 whitespace-nowrap text-white font-medium">
                     {attendee.name}
@@ -88,13 +147,29 @@ const AttendeeList = () => {
                   <td className="px-6 py-4 whitespace-nowrap text-gray-400 text-sm">
                     {new Date(attendee.registeredAt).toLocaleString()}
                   </td>
+                  <td className="px-6 py-4 whitespace-nowrap text-sm">
+                    {attendee.status === 'cancelled' ? (
+                      <span className="text-gray-500">Cancelled</span>
+                    ) : attendee.checkedInAt ? (
+                      <span className="text-green-300">{new Date(attendee.checkedInAt).toLocaleTimeString()}</span>
+                    ) : (
+                      <button onClick={() => handleCheckIn(attendee.id)} className="btn-secondary text-sm">
+                        Check in
+                      </button>
+                    )}
+                  </td>
                 </tr>
               ))}
             </tbody>
           </table>
         </div>
 
-        {attendees.length === 0 && (
+        {results?.length === 0 && (
+          <div className="text-center py-12 text-gray-400">
+            No attendees match "{search.trim()}"
+          </div>
+        )}
+        {!results && attendees.length === 0 && (
           <div className="text-center py-12 text-gray-400">
             No attendees registered yet
           </div>
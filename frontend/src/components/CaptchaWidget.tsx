@@ -0,0 +1,74 @@
+import { useEffect, useRef } from 'react';
+
+// The Cloudflare Turnstile API, which the default CAPTCHA_VERIFY_URL checks
+// tokens against.
+interface Turnstile {
+  render: (
+    container: HTMLElement,
+    options: { sitekey: string; callback: (token: string) => void; 'expired-callback': () => void }
+  ) => string;
+  remove: (widgetId: string) => void;
+}
+
+declare global {
+  interface Window {
+    turnstile?: Turnstile;
+  }
+}
+
+const SCRIPT_URL = 'https://challenges.cloudflare.com/turnstile/v0/api.js?render=explicit';
+
+let scriptLoad: Promise<Turnstile> | null = null;
+
+const loadTurnstile = (): Promise<Turnstile> => {
+  if (window.turnstile) return Promise.resolve(window.turnstile);
+  if (!scriptLoad) {
+    scriptLoad = new Promise((resolve, reject) => {
+      const script = document.createElement('script');
+      script.src = SCRIPT_URL;
+      script.async = true;
+      script.onload = () => (window.turnstile ? resolve(window.turnstile) : reject(new Error('CAPTCHA unavailable')));
+      script.onerror = () => {
+        scriptLoad = null;
+        reject(new Error('CAPTCHA unavailable'));
+      };
+      document.head.appendChild(script);
+    });
+  }
+  return scriptLoad;
+};
+
+interface CaptchaWidgetProps {
+  siteKey: string;
+  // Called with a token once the visitor passes, and with null when it expires
+  onToken: (token: string | null) => void;
+}
+
+const CaptchaWidget = ({ siteKey, onToken }: CaptchaWidgetProps) => {
+  const container = useRef<HTMLDivElement>(null);
+
+  useEffect(() => {
+    let widgetId: string | null = null;
+    let cancelled = false;
+
+    loadTurnstile()
+      .then((turnstile) => {
+        if (cancelled || !container.current) return;
+        widgetId = turnstile.render(container.current, {
+          sitekey: siteKey,
+          callback: onToken,
+          'expired-callback': () => onToken(null),
+        });
+      })
+      .catch((err) => console.error('Failed to load CAPTCHA:', err));
+
+    return () => {
+      cancelled = true;
+      if (widgetId) window.turnstile?.remove(widgetId);
+    };
+  }, [siteKey, onToken]);
+
+  return <div ref={container} />;
+};
+
+export default CaptchaWidget;
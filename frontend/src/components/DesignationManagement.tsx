@@ -0,0 +1,268 @@
+import { useEffect, useState } from 'react';
+import {
+  getDesignations,
+  getDesignationOptions,
+  createDesignation,
+  updateDesignation,
+  deleteDesignation,
+  backfillDesignations,
+} from '../services/api';
+import type { Designation, DesignationBackfill } from '../types';
+
+const emptyForm = { name: '', aliases: '', order: 0 };
+
+const DesignationManagement = () => {
+  const [designations, setDesignations] = useState<Designation[]>([]);
+  // What the form offers while no designations are defined
+  const [builtIn, setBuiltIn] = useState<string[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [showForm, setShowForm] = useState(false);
+  const [editingDesignation, setEditingDesignation] = useState<Designation | null>(null);
+  const [formData, setFormData] = useState(emptyForm);
+  const [backfill, setBackfill] = useState<DesignationBackfill | null>(null);
+  const [backfilling, setBackfilling] = useState(false);
+
+  useEffect(() => {
+    fetchDesignations();
+  }, []);
+
+  const fetchDesignations = async () => {
+    try {
+      setLoading(true);
+      const data = await getDesignations();
+      setDesignations(data);
+      if (data.length === 0) {
+        setBuiltIn(await getDesignationOptions());
+      }
+      setError(null);
+    } catch (err) {
+      setError('Failed to load designations');
+      console.error(err);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError(null);
+
+    const data = {
+      name: formData.name,
+      aliases: formData.aliases.split(',').map((a) => a.trim()).filter(Boolean),
+      order: formData.order,
+    };
+
+    try {
+      if (editingDesignation) {
+        await updateDesignation(editingDesignation.id, data);
+      } else {
+        await createDesignation(data);
+      }
+      handleCancel();
+      fetchDesignations();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Operation failed');
+    }
+  };
+
+  const handleEdit = (designation: Designation) => {
+    setEditingDesignation(designation);
+    setFormData({
+      name: designation.name,
+      aliases: (designation.aliases ?? []).join(', '),
+      order: designation.order,
+    });
+    setShowForm(true);
+  };
+
+  const handleDelete = async (id: string) => {
+    if (!confirm('Delete this designation? Registrations that use it keep it.')) return;
+
+    try {
+      await deleteDesignation(id);
+      fetchDesignations();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Delete failed');
+    }
+  };
+
+  const handleCancel = () => {
+    setShowForm(false);
+    setEditingDesignation(null);
+    setFormData(emptyForm);
+  };
+
+  const handleBackfill = async () => {
+    if (!confirm('Rewrite every registration to its canonical designation?')) return;
+
+    try {
+      setBackfilling(true);
+      setError(null);
+      setBackfill(await backfillDesignations());
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Backfill failed');
+    } finally {
+      setBackfilling(false);
+    }
+  };
+
+  if (loading) {
+    return (
+      <div className="text-center py-12">
+        <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-white mx-auto"></div>
+        <p className="mt-4 text-gray-300">Loading...</p>
+      </div>
+    );
+  }
+
+  const unmatched = backfill ? Object.entries(backfill.unmatched) : [];
+
+  return (
+    <div>
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Designations ({designations.length})</h3>
+        <div className="flex gap-2">
+          <button onClick={handleBackfill} disabled={backfilling} className="btn-secondary">
+            {backfilling ? 'Re-bucketing...' : 'Re-bucket registrations'}
+          </button>
+          <button
+            onClick={() => {
+              setEditingDesignation(null);
+              setFormData(emptyForm);
+              setShowForm(true);
+            }}
+            className="btn-primary"
+          >
+            Add Designation
+          </button>
+        </div>
+      </div>
+
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-4">
+          {error}
+        </div>
+      )}
+
+      {backfill && (
+        <div className="card mb-6 text-sm text-gray-300">
+          <p>
+            Checked {backfill.scanned} registrations and updated {backfill.updated}.
+          </p>
+          {unmatched.length > 0 && (
+            <>
+              <p className="mt-2">These match no designation; add them as aliases and re-bucket again:</p>
+              <div className="flex flex-wrap gap-2 mt-2">
+                {unmatched.map(([value, count]) => (
+                  <span key={value} className="px-2 py-1 bg-red-500/20 text-red-200 rounded text-xs">
+                    {value || '(empty)'} × {count}
+                  </span>
+                ))}
+              </div>
+            </>
+          )}
+        </div>
+      )}
+
+      {showForm && (
+        <div className="card mb-6">
+          <h4 className="text-xl font-bold text-white mb-4">
+            {editingDesignation ? 'Edit Designation' : 'Add New Designation'}
+          </h4>
+          <form onSubmit={handleSubmit} className="space-y-4">
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">
+                Name *
+              </label>
+              <input
+                type="text"
+                value={formData.name}
+                onChange={(e) => setFormData({ ...formData, name: e.target.value })}
+                className="input-field"
+                required
+              />
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">
+                Aliases (comma-separated)
+              </label>
+              <input
+                type="text"
+                value={formData.aliases}
+                onChange={(e) => setFormData({ ...formData, aliases: e.target.value })}
+                className="input-field"
+                placeholder="e.g., SWE, Software Developer"
+              />
+              <p className="text-gray-400 text-xs mt-1">
+                Matching ignores case and punctuation. List an old name here after renaming, then re-bucket.
+              </p>
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">
+                Order
+              </label>
+              <input
+                type="number"
+                value={formData.order}
+                onChange={(e) => setFormData({ ...formData, order: Number(e.target.value) })}
+                className="input-field"
+              />
+            </div>
+            <div className="flex gap-3">
+              <button type="submit" className="btn-primary">
+                {editingDesignation ? 'Update' : 'Create'}
+              </button>
+              <button type="button" onClick={handleCancel} className="btn-secondary">
+                Cancel
+              </button>
+            </div>
+          </form>
+        </div>
+      )}
+
+      <div className="space-y-4">
+        {designations.map((designation) => (
+          <div key={designation.id} className="card">
+            <div className="flex justify-between items-start">
+              <div className="flex-1">
+                <h4 className="text-lg font-bold text-white mb-1">{designation.name}</h4>
+                {designation.aliases && designation.aliases.length > 0 && (
+                  <div className="flex flex-wrap gap-2 mt-2">
+                    {designation.aliases.map((alias) => (
+                      <span key={alias} className="px-2 py-1 bg-purple-500/20 text-purple-300 rounded text-xs">
+                        {alias}
+                      </span>
+                    ))}
+                  </div>
+                )}
+              </div>
+              <div className="flex gap-2 ml-4">
+                <button onClick={() => handleEdit(designation)} className="btn-secondary text-sm">
+                  Edit
+                </button>
+                <button
+                  onClick={() => handleDelete(designation.id)}
+                  className="bg-red-500/20 hover:bg-red-500/30 border border-red-500/50 rounded-lg px-4 py-2 text-red-200 text-sm transition-colors"
+                >
+                  Delete
+                </button>
+              </div>
+            </div>
+          </div>
+        ))}
+      </div>
+
+      {designations.length === 0 && !showForm && (
+        <div className="text-center py-12 text-gray-400">
+          <p>No designations defined. The registration form offers the built-in list:</p>
+          <p className="mt-2 text-gray-300">{builtIn.join(' • ')}</p>
+          <p className="mt-2">Adding a designation replaces the built-in list.</p>
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default DesignationManagement;
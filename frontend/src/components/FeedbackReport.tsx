@@ -0,0 +1,135 @@
+import { useEffect, useState } from 'react';
+import {
+  getFeedbackSummaries,
+  exportFeedback,
+  getSpeakers,
+  createSpeakerFeedbackLink,
+  downloadBlob,
+} from '../services/api';
+import FeedbackSummaries from './FeedbackSummaries';
+import type { FeedbackSummary, Speaker, SpeakerLink } from '../types';
+
+const FeedbackReport = () => {
+  const [summaries, setSummaries] = useState<FeedbackSummary[]>([]);
+  const [speakers, setSpeakers] = useState<Speaker[]>([]);
+  const [session, setSession] = useState('');
+  const [speakerId, setSpeakerId] = useState('');
+  const [link, setLink] = useState<SpeakerLink | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [working, setWorking] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    fetchSummaries();
+    getSpeakers()
+      .then(setSpeakers)
+      .catch((err) => console.error('Failed to load speakers:', err));
+  }, []);
+
+  const fetchSummaries = async () => {
+    try {
+      setLoading(true);
+      const data = await getFeedbackSummaries();
+      setSummaries(data);
+      setError(null);
+    } catch (err) {
+      setError('Failed to load feedback');
+      console.error(err);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleExport = async () => {
+    setWorking(true);
+    setError(null);
+    try {
+      const blob = await exportFeedback(session || undefined);
+      downloadBlob(blob, 'session-feedback.csv');
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Export failed');
+    } finally {
+      setWorking(false);
+    }
+  };
+
+  const handleCreateLink = async () => {
+    if (!speakerId) return;
+    setWorking(true);
+    setError(null);
+    setLink(null);
+    try {
+      setLink(await createSpeakerFeedbackLink(speakerId));
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Could not create the link');
+    } finally {
+      setWorking(false);
+    }
+  };
+
+  const shown = session ? summaries.filter((s) => s.sessionId === session) : summaries;
+
+  return (
+    <div>
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Session Feedback</h3>
+        <button onClick={fetchSummaries} className="btn-secondary text-sm">
+          Refresh
+        </button>
+      </div>
+
+      <div className="card mb-6 space-y-4">
+        <div className="flex flex-col md:flex-row gap-3">
+          <select value={session} onChange={(e) => setSession(e.target.value)} className="input-field flex-1">
+            <option value="" className="bg-slate-800">All sessions</option>
+            {summaries.map((s) => (
+              <option key={s.sessionId} value={s.sessionId} className="bg-slate-800">
+                {s.title}
+              </option>
+            ))}
+          </select>
+          <button onClick={handleExport} disabled={working} className="btn-primary">
+            Download CSV
+          </button>
+        </div>
+
+        <div className="flex flex-col md:flex-row gap-3">
+          <select value={speakerId} onChange={(e) => setSpeakerId(e.target.value)} className="input-field flex-1">
+            <option value="" className="bg-slate-800">Choose a speaker</option>
+            {speakers.map((speaker) => (
+              <option key={speaker.id} value={speaker.id} className="bg-slate-800">
+                {speaker.name}
+              </option>
+            ))}
+          </select>
+          <button onClick={handleCreateLink} disabled={working || !speakerId} className="btn-secondary">
+            Create speaker link
+          </button>
+        </div>
+        {link && (
+          <div className="text-sm text-gray-300">
+            <p className="mb-1">
+              Send this link to the speaker. It lets them read the feedback on their sessions until{' '}
+              {new Date(link.expiresAt).toLocaleString()}.
+            </p>
+            <input readOnly value={link.url} onFocus={(e) => e.target.select()} className="input-field" />
+          </div>
+        )}
+      </div>
+
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-4">
+          {error}
+        </div>
+      )}
+
+      {loading ? (
+        <p className="text-gray-400 animate-pulse">Loading...</p>
+      ) : (
+        <FeedbackSummaries summaries={shown} />
+      )}
+    </div>
+  );
+};
+
+export default FeedbackReport;
@@ -0,0 +1,66 @@
+import type { FeedbackSummary } from '../types';
+
+interface FeedbackSummariesProps {
+  summaries: FeedbackSummary[];
+}
+
+// FeedbackSummaries shows each session's ratings and comments. It is shared
+// by the admin report and the speakers' view, so it never shows who rated.
+const FeedbackSummaries = ({ summaries }: FeedbackSummariesProps) => {
+  if (summaries.length === 0) {
+    return <p className="text-gray-400">No sessions yet.</p>;
+  }
+
+  return (
+    <div className="space-y-4">
+      {summaries.map((summary) => {
+        const most = Math.max(1, ...summary.distribution);
+        return (
+          <div key={summary.sessionId} className="card">
+            <div className="flex justify-between items-start gap-4 mb-4">
+              <h4 className="text-lg font-semibold text-white">{summary.title}</h4>
+              <div className="text-right shrink-0">
+                <p className="text-2xl font-bold text-white">
+                  {summary.count > 0 ? summary.average.toFixed(1) : '–'}
+                </p>
+                <p className="text-gray-400 text-xs">
+                  {summary.count} {summary.count === 1 ? 'rating' : 'ratings'}
+                </p>
+              </div>
+            </div>
+
+            <div className="space-y-1 mb-4">
+              {[5, 4, 3, 2, 1].map((rating) => (
+                <div key={rating} className="flex items-center gap-2 text-sm">
+                  <span className="w-4 text-gray-300">{rating}</span>
+                  <div className="flex-1 h-2 bg-white/10 rounded">
+                    <div
+                      className="h-2 bg-purple-400 rounded"
+                      style={{ width: `${(summary.distribution[rating - 1] / most) * 100}%` }}
+                    />
+                  </div>
+                  <span className="w-8 text-right text-gray-400">{summary.distribution[rating - 1]}</span>
+                </div>
+              ))}
+            </div>
+
+            {summary.comments.length > 0 && (
+              <ul className="space-y-2">
+                {summary.comments.map((comment, i) => (
+                  <li key={i} className="bg-white/5 rounded-lg p-3 text-sm">
+                    <p className="text-gray-200 whitespace-pre-line">{comment.comment}</p>
+                    <p className="text-gray-500 text-xs mt-1">
+                      {comment.rating}/5 · {new Date(comment.submittedAt).toLocaleString()}
+                    </p>
+                  </li>
+                ))}
+              </ul>
+            )}
+          </div>
+        );
+      })}
+    </div>
+  );
+};
+
+export default FeedbackSummaries;
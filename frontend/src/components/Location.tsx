@@ -1,6 +1,30 @@
+import { useEffect, useState } from 'react';
 import { motion } from 'framer-motion';
+import { getVenues } from '../services/api';
+import type { Venue } from '../types';
+
+// Shown until an admin adds a venue
+const defaultVenue: Venue = {
+  id: '',
+  name: 'AppDirect India',
+  address: 'Pune, Maharashtra, India',
+  mapUrl:
+    'https://www.google.com/maps/embed?pb=!1m18!1m12!1m3!1d3930.310034205593!2d73.92600257972352!3d18.515585566381823!2m3!1f0!2f0!3f0!3m2!1i1024!2i768!4f13.1!3m3!1m2!1s0x3bc2c18cf4eaad8d%3A0xc5835f1d9e3a91d3!2sAppDirect%20India!5e0!3m2!1sen!2sin!4v1762854087901!5m2!1sen!2sin',
+};
 
 const Location = () => {
+  const [venue, setVenue] = useState<Venue>(defaultVenue);
+
+  useEffect(() => {
+    getVenues()
+      .then((venues) => {
+        if (venues.length > 0) setVenue(venues[0]);
+      })
+      .catch((err) => console.error(err));
+  }, []);
+
+  const rooms = venue.rooms ?? [];
+
   return (
     <section id="location" className="py-20 px-4">
       <div className="max-w-6xl mx-auto">
@@ -15,7 +39,7 @@ const Location = () => {
             Event Location
           </h2>
           <p className="text-xl text-gray-300">
-            Join us at AppDirect India office
+            Join us at {venue.name}
           </p>
         </motion.div>
 
@@ -39,11 +63,24 @@ const Location = () => {
               </div>
               <div>
                 <h4 className="text-purple-300 font-semibold mb-1">Venue</h4>
-                <p className="text-gray-300">AppDirect India</p>
-                <p className="text-gray-400 text-sm mt-1">
-                  Pune, Maharashtra, India
-                </p>
+                <p className="text-gray-300">{venue.name}</p>
+                {venue.address && <p className="text-gray-400 text-sm mt-1">{venue.address}</p>}
+                {venue.directions && <p className="text-gray-400 text-sm mt-1 whitespace-pre-line">{venue.directions}</p>}
               </div>
+              {rooms.length > 0 && (
+                <div>
+                  <h4 className="text-purple-300 font-semibold mb-1">Rooms</h4>
+                  <ul className="space-y-1">
+                    {rooms.map((room) => (
+                      <li key={room.id} className="text-gray-300">
+                        {room.name}
+                        {room.capacity > 0 && <span className="text-gray-400 text-sm"> · {room.capacity} seats</span>}
+                        {room.accessibility && <p className="text-gray-400 text-sm">{room.accessibility}</p>}
+                      </li>
+                    ))}
+                  </ul>
+                </div>
+              )}
               <div>
                 <h4 className="text-purple-300 font-semibold mb-1">Contact</h4>
                 <p className="text-gray-300">For inquiries, please contact the event organizers</p>
@@ -59,16 +96,18 @@ const Location = () => {
             className="card p-0 overflow-hidden"
           >
             <div className="w-full h-full min-h-[400px]">
-              <iframe
-                src="https://www.google.com/maps/embed?pb=!1m18!1m12!1m3!1d3930.310034205593!2d73.92600257972352!3d18.515585566381823!2m3!1f0!2f0!3f0!3m2!1i1024!2i768!4f13.1!3m3!1m2!1s0x3bc2c18cf4eaad8d%3A0xc5835f1d9e3a91d3!2sAppDirect%20India!5e0!3m2!1sen!2sin!4v1762854087901!5m2!1sen!2sin"
-                width="100%"
-                height="100%"
-                style={{ border: 0 }}
-                allowFullScreen
-                loading="lazy"
-                referrerPolicy="no-referrer-when-downgrade"
-                className="w-full h-full"
-              />
+              {venue.mapUrl && (
+                <iframe
+                  src={venue.mapUrl}
+                  width="100%"
+                  height="100%"
+                  style={{ border: 0 }}
+                  allowFullScreen
+                  loading="lazy"
+                  referrerPolicy="no-referrer-when-downgrade"
+                  className="w-full h-full"
+                />
+              )}
             </div>
           </motion.div>
         </div>
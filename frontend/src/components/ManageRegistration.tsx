@@ -0,0 +1,332 @@
+import { useState, useEffect } from 'react';
+import { motion } from 'framer-motion';
+import {
+  requestMagicLink,
+  getMyRegistration,
+  getRegistrationForm,
+  getDesignationOptions,
+  updateMyRegistration,
+  cancelMyRegistration,
+  exportMyData,
+  requestMyErasure,
+  downloadJSON,
+} from '../services/api';
+import QuestionField from './QuestionField';
+import SessionFeedback from './SessionFeedback';
+import SessionQA from './SessionQA';
+import type { Attendee, Answers, Question } from '../types';
+
+interface ManageRegistrationProps {
+  // Token from the #manage= link fragment; without one the attendee is
+  // asked for their email to receive a link.
+  token: string | null;
+  onClose: () => void;
+}
+
+const ManageRegistration = ({ token, onClose }: ManageRegistrationProps) => {
+  const [email, setEmail] = useState('');
+  const [linkRequested, setLinkRequested] = useState(false);
+  const [attendee, setAttendee] = useState<Attendee | null>(null);
+  const [formData, setFormData] = useState({ name: '', designation: '', listPublicly: false });
+  const [questions, setQuestions] = useState<Question[]>([]);
+  const [designations, setDesignations] = useState<string[]>([]);
+  const [answers, setAnswers] = useState<Answers>({});
+  const [loading, setLoading] = useState(false);
+  const [message, setMessage] = useState<string | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    if (!token) return;
+
+    const load = async () => {
+      setLoading(true);
+      try {
+        const [registration, form, options] = await Promise.all([
+          getMyRegistration(token),
+          getRegistrationForm(),
+          getDesignationOptions(),
+        ]);
+        setAttendee(registration);
+        setQuestions(form);
+        setDesignations(options);
+        // Answers to questions since removed from the form are not resubmitted
+        const current = new Set(form.map((q) => q.key));
+        setAnswers(
+          Object.fromEntries(
+            Object.entries(registration.answers ?? {}).filter(([key]) => current.has(key))
+          )
+        );
+        setFormData({
+          name: registration.name,
+          designation: registration.designation,
+          listPublicly: registration.listPublicly,
+        });
+      } catch (err: any) {
+        setError(err.response?.data?.error || 'Could not load your registration.');
+      } finally {
+        setLoading(false);
+      }
+    };
+
+    load();
+  }, [token]);
+
+  const handleRequestLink = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError(null);
+    setLoading(true);
+    try {
+      await requestMagicLink(email);
+      setLinkRequested(true);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Could not send the link. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleUpdate = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!token) return;
+    setError(null);
+    setMessage(null);
+    setLoading(true);
+    try {
+      const updated = await updateMyRegistration(token, { ...formData, answers });
+      setAttendee(updated);
+      setMessage('Your registration has been updated.');
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Update failed. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleCancel = async () => {
+    if (!token || !window.confirm('Cancel your registration? Your place will be released.')) return;
+    setError(null);
+    setMessage(null);
+    setLoading(true);
+    try {
+      await cancelMyRegistration(token);
+      setAttendee(attendee && { ...attendee, status: 'cancelled', listPublicly: false });
+      setMessage('Your registration has been cancelled.');
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Cancellation failed. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleExport = async () => {
+    if (!token) return;
+    setError(null);
+    setLoading(true);
+    try {
+      const data = await exportMyData(token);
+      downloadJSON(data, 'workshop-data-export.json');
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Export failed. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleErasure = async () => {
+    if (!token || !window.confirm('Ask us to erase all your data? This cannot be undone.')) return;
+    setError(null);
+    setMessage(null);
+    setLoading(true);
+    try {
+      await requestMyErasure(token);
+      setMessage('Your erasure request has been received. We will process it shortly.');
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Request failed. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const cancelled = attendee?.status === 'cancelled';
+
+  return (
+    <div className="fixed inset-0 flex items-center justify-center z-50 p-4">
+      <div
+        className="absolute inset-0 bg-black/70 backdrop-blur-sm"
+        onClick={onClose}
+      />
+      <motion.div
+        initial={{ opacity: 0, scale: 0.9 }}
+        animate={{ opacity: 1, scale: 1 }}
+        className="relative glass-strong rounded-2xl p-8 max-w-md w-full max-h-[90vh] overflow-y-auto"
+      >
+        <button
+          onClick={onClose}
+          className="absolute top-4 right-4 text-gray-400 hover:text-white transition-colors"
+        >
+          <svg
+            className="w-6 h-6"
+            fill="none"
+            stroke="currentColor"
+            viewBox="0 0 24 24"
+          >
+            <path
+              strokeLinecap="round"
+              strokeLinejoin="round"
+              strokeWidth={2}
+              d="M6 18L18 6M6 6l12 12"
+            />
+          </svg>
+        </button>
+
+        <h2 className="text-3xl font-bold text-white mb-2">Your Registration</h2>
+
+        {!token && (
+          linkRequested ? (
+            <p className="text-gray-300">
+              If that email is registered, a link to manage your registration is on its way.
+            </p>
+          ) : (
+            <form onSubmit={handleRequestLink} className="space-y-4">
+              <p className="text-gray-400">
+                Enter the email you registered with and we'll send you a link.
+              </p>
+              <input
+                id="manage-email"
+                type="email"
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                className="input-field"
+                placeholder="your.email@example.com"
+                required
+                autoFocus
+              />
+              {error && (
+                <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm">
+                  {error}
+                </div>
+              )}
+              <button type="submit" disabled={loading} className="btn-primary w-full">
+                {loading ? 'Sending...' : 'Send me a link'}
+              </button>
+            </form>
+          )
+        )}
+
+        {token && (
+          <div className="space-y-4">
+            {loading && !attendee && <p className="text-gray-400 animate-pulse">Loading...</p>}
+
+            {attendee && (
+              cancelled ? (
+                <p className="text-gray-300">
+                  The registration for {attendee.email} is cancelled.
+                </p>
+              ) : (
+                <form onSubmit={handleUpdate} className="space-y-4">
+                  <p className="text-gray-400">Registered as {attendee.email}</p>
+                  <div>
+                    <label htmlFor="manage-name" className="block text-sm font-medium text-gray-300 mb-2">
+                      Full Name
+                    </label>
+                    <input
+                      id="manage-name"
+                      type="text"
+                      value={formData.name}
+                      onChange={(e) => setFormData({ ...formData, name: e.target.value })}
+                      className="input-field"
+                      required
+                    />
+                  </div>
+                  <div>
+                    <label htmlFor="manage-designation" className="block text-sm font-medium text-gray-300 mb-2">
+                      Designation
+                    </label>
+                    <select
+                      id="manage-designation"
+                      value={formData.designation}
+                      onChange={(e) => setFormData({ ...formData, designation: e.target.value })}
+                      className="input-field"
+                      required
+                    >
+                      {/* A designation no longer offered can be kept */}
+                      {!designations.includes(formData.designation) && (
+                        <option value={formData.designation} className="bg-slate-800">
+                          {formData.designation}
+                        </option>
+                      )}
+                      {designations.map((designation) => (
+                        <option key={designation} value={designation} className="bg-slate-800">
+                          {designation}
+                        </option>
+                      ))}
+                    </select>
+                  </div>
+                  {questions.map((question) => (
+                    <QuestionField
+                      key={question.id}
+                      idPrefix="manage"
+                      question={question}
+                      value={answers[question.key]}
+                      onChange={(value) => setAnswers({ ...answers, [question.key]: value })}
+                    />
+                  ))}
+                  <label htmlFor="manage-listPublicly" className="flex items-start gap-3 text-sm text-gray-300">
+                    <input
+                      id="manage-listPublicly"
+                      type="checkbox"
+                      checked={formData.listPublicly}
+                      onChange={(e) => setFormData({ ...formData, listPublicly: e.target.checked })}
+                      className="mt-1"
+                    />
+                    <span>Show my first name and designation in the public attendee list</span>
+                  </label>
+                  <div className="flex gap-3">
+                    <button
+                      type="button"
+                      onClick={handleCancel}
+                      disabled={loading}
+                      className="btn-secondary flex-1"
+                    >
+                      Cancel registration
+                    </button>
+                    <button type="submit" disabled={loading} className="btn-primary flex-1">
+                      {loading ? 'Saving...' : 'Save changes'}
+                    </button>
+                  </div>
+                </form>
+              )
+            )}
+
+            {attendee && !cancelled && <SessionFeedback token={token} />}
+            {attendee && attendee.status !== 'pending' && !cancelled && <SessionQA token={token} />}
+
+            {attendee && (
+              <div className="pt-4 border-t border-white/20 flex gap-3 text-sm">
+                <button type="button" onClick={handleExport} disabled={loading} className="text-purple-300 hover:text-white underline">
+                  Download my data
+                </button>
+                <button type="button" onClick={handleErasure} disabled={loading} className="text-purple-300 hover:text-white underline">
+                  Erase my data
+                </button>
+              </div>
+            )}
+
+            {message && (
+              <div className="bg-green-500/20 border border-green-500/50 rounded-lg p-3 text-green-200 text-sm">
+                {message}
+              </div>
+            )}
+            {error && (
+              <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm">
+                {error}
+              </div>
+            )}
+          </div>
+        )}
+      </motion.div>
+    </div>
+  );
+};
+
+export default ManageRegistration;
@@ -0,0 +1,175 @@
+import { useEffect, useState } from 'react';
+import {
+  getPrivacyRequests,
+  exportPersonalData,
+  erasePersonalData,
+  downloadJSON,
+} from '../services/api';
+import type { PrivacyRequest } from '../types';
+
+const PrivacyRequests = () => {
+  const [requests, setRequests] = useState<PrivacyRequest[]>([]);
+  const [email, setEmail] = useState('');
+  const [loading, setLoading] = useState(true);
+  const [working, setWorking] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    fetchRequests();
+  }, []);
+
+  const fetchRequests = async () => {
+    try {
+      setLoading(true);
+      const data = await getPrivacyRequests();
+      setRequests(data);
+      setError(null);
+    } catch (err) {
+      setError('Failed to load privacy requests');
+      console.error(err);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleExport = async () => {
+    if (!email) return;
+    setWorking(true);
+    setError(null);
+    try {
+      const data = await exportPersonalData(email);
+      downloadJSON(data, 'workshop-data-export.json');
+      await fetchRequests();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Export failed');
+    } finally {
+      setWorking(false);
+    }
+  };
+
+  const erase = async (subject: { email: string } | { requestId: string }, label: string) => {
+    if (!window.confirm(`Erase all personal data for ${label}? This cannot be undone.`)) return;
+    setWorking(true);
+    setError(null);
+    try {
+      await erasePersonalData(subject);
+      setEmail('');
+      await fetchRequests();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Erasure failed');
+    } finally {
+      setWorking(false);
+    }
+  };
+
+  return (
+    <div>
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Data Requests</h3>
+        <button onClick={fetchRequests} className="btn-secondary text-sm">
+          Refresh
+        </button>
+      </div>
+
+      <div className="card mb-6">
+        <p className="text-gray-300 text-sm mb-4">
+          Export or erase everything held about an email address. Erasure anonymizes
+          registrations, so counts and statistics are unchanged.
+        </p>
+        <div className="flex flex-col md:flex-row gap-3">
+          <input
+            type="email"
+            value={email}
+            onChange={(e) => setEmail(e.target.value)}
+            className="input-field flex-1"
+            placeholder="attendee@example.com"
+          />
+          <button onClick={handleExport} disabled={working || !email} className="btn-secondary">
+            Export
+          </button>
+          <button
+            onClick={() => erase({ email }, email)}
+            disabled={working || !email}
+            className="btn-primary"
+          >
+            Erase
+          </button>
+        </div>
+        {error && (
+          <div className="mt-4 bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm">
+            {error}
+          </div>
+        )}
+      </div>
+
+      <div className="card overflow-hidden p-0">
+        {loading ? (
+          <div className="text-center py-12">
+            <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-white mx-auto"></div>
+            <p className="mt-4 text-gray-300">Loading requests...</p>
+          </div>
+        ) : (
+          <div className="overflow-x-auto">
+            <table className="w-full">
+              <thead className="bg-white/10">
+                <tr>
+                  <th className="px-6 py-3 text-left text-xs font-medium text-gray-300 uppercase tracking-wider">
+                    Type
+                  </th>
+                  <th className="px-6 py-3 text-left text-xs font-medium text-gray-300 uppercase tracking-wider">
+                    Subject
+                  </th>
+                  <th className="px-6 py-3 text-left text-xs font-medium text-gray-300 uppercase tracking-wider">
+                    Source
+                  </th>
+                  <th className="px-6 py-3 text-left text-xs font-medium text-gray-300 uppercase tracking-wider">
+                    Requested At
+                  </th>
+                  <th className="px-6 py-3 text-left text-xs font-medium text-gray-300 uppercase tracking-wider">
+                    Status
+                  </th>
+                </tr>
+              </thead>
+              <tbody className="divide-y divide-white/10">
+                {requests.map((request) => (
+                  <tr key={request.id} className="hover:bg-white/5 transition-colors">
+                    <td className="px-6 py-4 whitespace-nowrap text-white font-medium capitalize">
+                      {request.type}
+                    </td>
+                    <td className="px-6 py-4 whitespace-nowrap text-gray-300">
+                      {request.email || <span className="text-gray-500">erased ({request.emailHash.slice(0, 12)})</span>}
+                    </td>
+                    <td className="px-6 py-4 whitespace-nowrap text-gray-300">{request.source}</td>
+                    <td className="px-6 py-4 whitespace-nowrap text-gray-400 text-sm">
+                      {new Date(request.requestedAt).toLocaleString()}
+                    </td>
+                    <td className="px-6 py-4 whitespace-nowrap text-gray-300">
+                      {request.status === 'pending' ? (
+                        <button
+                          onClick={() => erase({ requestId: request.id }, request.email || 'this request')}
+                          disabled={working}
+                          className="btn-primary text-sm"
+                        >
+                          Erase now
+                        </button>
+                      ) : (
+                        <span className="text-sm">
+                          Completed {request.completedAt && new Date(request.completedAt).toLocaleString()}
+                        </span>
+                      )}
+                    </td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+            {requests.length === 0 && (
+              <div className="text-center py-12 text-gray-400">No data requests yet</div>
+            )}
+          </div>
+        )}
+      </div>
+    </div>
+  );
+};
+
+export default PrivacyRequests;
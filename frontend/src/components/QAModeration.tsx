@@ -0,0 +1,165 @@
+import { useEffect, useState } from 'react';
+import { getSessions, getModerationQueue, moderateQuestion, subscribeToEvents } from '../services/api';
+import { sortQuestions } from './SessionQA';
+import type { LiveQuestion, Session } from '../types';
+
+const statusStyles: Record<LiveQuestion['status'], string> = {
+  pending: 'bg-yellow-500/20 text-yellow-300',
+  approved: 'bg-green-500/20 text-green-300',
+  hidden: 'bg-gray-500/20 text-gray-400',
+};
+
+const QAModeration = () => {
+  const [sessions, setSessions] = useState<Session[]>([]);
+  const [sessionId, setSessionId] = useState('');
+  const [status, setStatus] = useState('');
+  const [questions, setQuestions] = useState<LiveQuestion[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    getSessions()
+      .then((all) => {
+        setSessions(all);
+        if (all.length > 0) setSessionId(all[0].id);
+      })
+      .catch((err) => console.error('Failed to load sessions:', err));
+  }, []);
+
+  useEffect(() => {
+    if (!sessionId) return;
+
+    const fetchQuestions = async () => {
+      try {
+        setLoading(true);
+        setQuestions(await getModerationQueue(sessionId, status));
+        setError(null);
+      } catch (err: any) {
+        setError(err.response?.data?.error || 'Failed to load questions');
+      } finally {
+        setLoading(false);
+      }
+    };
+
+    fetchQuestions();
+
+    // New and changed questions appear as they happen
+    return subscribeToEvents(
+      {
+        moderation: (change) => {
+          if (change.sessionId && change.sessionId !== sessionId) return;
+          setQuestions((prev) => {
+            const rest = prev.filter((q) => q.id !== change.id);
+            if (!change.data || (status && change.data.status !== status)) return rest;
+            return sortQuestions([...rest, change.data]);
+          });
+        },
+        reset: fetchQuestions,
+      },
+      true
+    );
+  }, [sessionId, status]);
+
+  const handleModerate = async (
+    question: LiveQuestion,
+    data: { status?: 'approved' | 'hidden'; pinned?: boolean; answered?: boolean }
+  ) => {
+    setError(null);
+    try {
+      const updated = await moderateQuestion(sessionId, question.id, data);
+      setQuestions((prev) =>
+        sortQuestions(
+          status && updated.status !== status
+            ? prev.filter((q) => q.id !== updated.id)
+            : prev.map((q) => (q.id === updated.id ? updated : q))
+        )
+      );
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Could not update the question');
+    }
+  };
+
+  return (
+    <div>
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Live Q&amp;A</h3>
+      </div>
+
+      <div className="card mb-6 flex flex-col md:flex-row gap-3">
+        <select value={sessionId} onChange={(e) => setSessionId(e.target.value)} className="input-field flex-1">
+          {sessions.map((session) => (
+            <option key={session.id} value={session.id} className="bg-slate-800">
+              {session.title}
+            </option>
+          ))}
+        </select>
+        <select value={status} onChange={(e) => setStatus(e.target.value)} className="input-field md:w-48">
+          <option value="" className="bg-slate-800">All questions</option>
+          <option value="pending" className="bg-slate-800">Pending</option>
+          <option value="approved" className="bg-slate-800">Approved</option>
+          <option value="hidden" className="bg-slate-800">Hidden</option>
+        </select>
+      </div>
+
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-4">
+          {error}
+        </div>
+      )}
+
+      {loading ? (
+        <p className="text-gray-400 animate-pulse">Loading...</p>
+      ) : questions.length === 0 ? (
+        <p className="text-gray-400">No questions yet.</p>
+      ) : (
+        <div className="space-y-3">
+          {questions.map((question) => (
+            <div key={question.id} className="card">
+              <div className="flex justify-between items-start gap-4">
+                <div className="flex-1">
+                  <p className="text-white">
+                    {question.pinned && <span className="text-yellow-400 mr-1">📌</span>}
+                    {question.text}
+                  </p>
+                  <p className="text-gray-400 text-sm mt-1">
+                    {question.authorName || 'Unknown'}
+                    {question.anonymous && ' (anonymous)'} · {question.votes} votes ·{' '}
+                    {new Date(question.createdAt).toLocaleTimeString()}
+                    {question.answeredAt && ' · Answered'}
+                  </p>
+                </div>
+                <span className={`text-xs px-2 py-1 rounded ${statusStyles[question.status]}`}>{question.status}</span>
+              </div>
+              <div className="flex flex-wrap gap-2 mt-3 text-sm">
+                {question.status !== 'approved' && (
+                  <button onClick={() => handleModerate(question, { status: 'approved' })} className="btn-secondary text-sm">
+                    Approve
+                  </button>
+                )}
+                {question.status !== 'hidden' && (
+                  <button onClick={() => handleModerate(question, { status: 'hidden' })} className="btn-secondary text-sm">
+                    Hide
+                  </button>
+                )}
+                <button
+                  onClick={() => handleModerate(question, { pinned: !question.pinned })}
+                  className="btn-secondary text-sm"
+                >
+                  {question.pinned ? 'Unpin' : 'Pin'}
+                </button>
+                <button
+                  onClick={() => handleModerate(question, { answered: !question.answeredAt })}
+                  className="btn-secondary text-sm"
+                >
+                  {question.answeredAt ? 'Mark open' : 'Mark answered'}
+                </button>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default QAModeration;
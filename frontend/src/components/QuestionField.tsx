@@ -0,0 +1,96 @@
+import type { Question, AnswerValue } from '../types';
+
+interface QuestionFieldProps {
+  question: Question;
+  value: AnswerValue | undefined;
+  onChange: (value: AnswerValue) => void;
+  idPrefix?: string;
+}
+
+// Renders the input for one custom registration question. The server
+// validates answers again; the attributes here only give early feedback.
+const QuestionField = ({ question, value, onChange, idPrefix = 'question' }: QuestionFieldProps) => {
+  const id = `${idPrefix}-${question.key}`;
+  const label = `${question.label}${question.required ? ' *' : ''}`;
+
+  if (question.type === 'boolean') {
+    return (
+      <label htmlFor={id} className="flex items-start gap-3 text-sm text-gray-300">
+        <input
+          id={id}
+          type="checkbox"
+          checked={value === true}
+          onChange={(e) => onChange(e.target.checked)}
+          className="mt-1"
+        />
+        <span>
+          {label}
+          {question.helpText && <span className="block text-gray-400 text-xs">{question.helpText}</span>}
+        </span>
+      </label>
+    );
+  }
+
+  return (
+    <div>
+      <label htmlFor={id} className="block text-sm font-medium text-gray-300 mb-2">
+        {label}
+      </label>
+
+      {question.type === 'text' && (
+        <input
+          id={id}
+          type="text"
+          value={typeof value === 'string' ? value : ''}
+          onChange={(e) => onChange(e.target.value)}
+          className="input-field"
+          required={question.required}
+          minLength={question.minLength || undefined}
+          maxLength={question.maxLength || 1000}
+          pattern={question.pattern || undefined}
+        />
+      )}
+
+      {question.type === 'single_choice' && (
+        <select
+          id={id}
+          value={typeof value === 'string' ? value : ''}
+          onChange={(e) => onChange(e.target.value)}
+          className="input-field"
+          required={question.required}
+        >
+          <option value="">Select an option</option>
+          {question.options?.map((option) => (
+            <option key={option} value={option} className="bg-slate-800">
+              {option}
+            </option>
+          ))}
+        </select>
+      )}
+
+      {question.type === 'multi_choice' && (
+        <div id={id} className="flex flex-wrap gap-3">
+          {question.options?.map((option) => {
+            const selected = Array.isArray(value) ? value : [];
+            return (
+              <label key={option} className="flex items-center gap-2 text-sm text-gray-300">
+                <input
+                  type="checkbox"
+                  checked={selected.includes(option)}
+                  onChange={(e) =>
+                    onChange(e.target.checked ? [...selected, option] : selected.filter((o) => o !== option))
+                  }
+                />
+                {option}
+              </label>
+            );
+          })}
+        </div>
+      )}
+
+      {question.helpText && <p className="text-gray-400 text-xs mt-1">{question.helpText}</p>}
+    </div>
+  );
+};
+
+export default QuestionField;
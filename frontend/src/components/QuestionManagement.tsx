@@ -0,0 +1,337 @@
+import { useEffect, useState } from 'react';
+import { getQuestions, createQuestion, updateQuestion, deleteQuestion } from '../services/api';
+import type { Question, QuestionType } from '../types';
+
+const TYPE_LABELS: Record<QuestionType, string> = {
+  text: 'Text',
+  single_choice: 'Single choice',
+  multi_choice: 'Multiple choice',
+  boolean: 'Yes / No',
+};
+
+const emptyForm = {
+  key: '',
+  label: '',
+  helpText: '',
+  type: 'text' as QuestionType,
+  required: false,
+  options: '',
+  order: 0,
+  minLength: 0,
+  maxLength: 0,
+  pattern: '',
+  minSelections: 0,
+  maxSelections: 0,
+};
+
+const QuestionManagement = () => {
+  const [questions, setQuestions] = useState<Question[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [showForm, setShowForm] = useState(false);
+  const [editingQuestion, setEditingQuestion] = useState<Question | null>(null);
+  const [formData, setFormData] = useState(emptyForm);
+
+  useEffect(() => {
+    fetchQuestions();
+  }, []);
+
+  const fetchQuestions = async () => {
+    try {
+      setLoading(true);
+      const data = await getQuestions();
+      setQuestions(data);
+      setError(null);
+    } catch (err) {
+      setError('Failed to load questions');
+      console.error(err);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const isChoice = formData.type === 'single_choice' || formData.type === 'multi_choice';
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError(null);
+
+    const definition = {
+      label: formData.label,
+      helpText: formData.helpText,
+      required: formData.required,
+      options: isChoice
+        ? formData.options.split('\n').map((o) => o.trim()).filter(Boolean)
+        : [],
+      order: formData.order,
+      minLength: formData.type === 'text' ? formData.minLength : 0,
+      maxLength: formData.type === 'text' ? formData.maxLength : 0,
+      pattern: formData.type === 'text' ? formData.pattern : '',
+      minSelections: formData.type === 'multi_choice' ? formData.minSelections : 0,
+      maxSelections: formData.type === 'multi_choice' ? formData.maxSelections : 0,
+    };
+
+    try {
+      if (editingQuestion) {
+        await updateQuestion(editingQuestion.id, definition);
+      } else {
+        await createQuestion({ ...definition, key: formData.key, type: formData.type });
+      }
+      handleCancel();
+      fetchQuestions();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Operation failed');
+    }
+  };
+
+  const handleEdit = (question: Question) => {
+    setEditingQuestion(question);
+    setFormData({
+      key: question.key,
+      label: question.label,
+      helpText: question.helpText ?? '',
+      type: question.type,
+      required: question.required,
+      options: (question.options ?? []).join('\n'),
+      order: question.order,
+      minLength: question.minLength ?? 0,
+      maxLength: question.maxLength ?? 0,
+      pattern: question.pattern ?? '',
+      minSelections: question.minSelections ?? 0,
+      maxSelections: question.maxSelections ?? 0,
+    });
+    setShowForm(true);
+  };
+
+  const handleDelete = async (id: string) => {
+    if (!confirm('Delete this question? Answers already given are kept on the attendee records.')) return;
+
+    try {
+      await deleteQuestion(id);
+      fetchQuestions();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Delete failed');
+    }
+  };
+
+  const handleCancel = () => {
+    setShowForm(false);
+    setEditingQuestion(null);
+    setFormData(emptyForm);
+  };
+
+  const numberField = (field: 'order' | 'minLength' | 'maxLength' | 'minSelections' | 'maxSelections', label: string) => (
+    <div>
+      <label className="block text-sm font-medium text-gray-300 mb-2">{label}</label>
+      <input
+        type="number"
+        min={field === 'order' ? undefined : 0}
+        value={formData[field]}
+        onChange={(e) => setFormData({ ...formData, [field]: Number(e.target.value) })}
+        className="input-field"
+      />
+    </div>
+  );
+
+  if (loading) {
+    return (
+      <div className="text-center py-12">
+        <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-white mx-auto"></div>
+        <p className="mt-4 text-gray-300">Loading...</p>
+      </div>
+    );
+  }
+
+  return (
+    <div>
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Registration Questions ({questions.length})</h3>
+        <button
+          onClick={() => {
+            setEditingQuestion(null);
+            setFormData(emptyForm);
+            setShowForm(true);
+          }}
+          className="btn-primary"
+        >
+          Add Question
+        </button>
+      </div>
+
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-4">
+          {error}
+        </div>
+      )}
+
+      {showForm && (
+        <div className="card mb-6">
+          <h4 className="text-xl font-bold text-white mb-4">
+            {editingQuestion ? 'Edit Question' : 'Add New Question'}
+          </h4>
+          <form onSubmit={handleSubmit} className="space-y-4">
+            <div className="grid grid-cols-2 gap-4">
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">
+                  Key *
+                </label>
+                <input
+                  type="text"
+                  value={formData.key}
+                  onChange={(e) => setFormData({ ...formData, key: e.target.value })}
+                  className="input-field"
+                  placeholder="e.g., company"
+                  pattern="[a-z][a-z0-9_]{0,39}"
+                  disabled={editingQuestion !== null}
+                  required
+                />
+              </div>
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">
+                  Type *
+                </label>
+                <select
+                  value={formData.type}
+                  onChange={(e) => setFormData({ ...formData, type: e.target.value as QuestionType })}
+                  className="input-field"
+                  disabled={editingQuestion !== null}
+                >
+                  {Object.entries(TYPE_LABELS).map(([type, label]) => (
+                    <option key={type} value={type} className="bg-slate-800">
+                      {label}
+                    </option>
+                  ))}
+                </select>
+              </div>
+            </div>
+            {editingQuestion && (
+              <p className="text-gray-400 text-xs">
+                Key and type cannot change once created, so existing answers keep their meaning.
+              </p>
+            )}
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">
+                Label *
+              </label>
+              <input
+                type="text"
+                value={formData.label}
+                onChange={(e) => setFormData({ ...formData, label: e.target.value })}
+                className="input-field"
+                required
+              />
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">
+                Help text
+              </label>
+              <input
+                type="text"
+                value={formData.helpText}
+                onChange={(e) => setFormData({ ...formData, helpText: e.target.value })}
+                className="input-field"
+              />
+            </div>
+            {isChoice && (
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">
+                  Options * (one per line)
+                </label>
+                <textarea
+                  value={formData.options}
+                  onChange={(e) => setFormData({ ...formData, options: e.target.value })}
+                  className="input-field"
+                  rows={4}
+                  required
+                />
+              </div>
+            )}
+            <div className="grid grid-cols-2 gap-4">
+              {numberField('order', 'Order')}
+              {formData.type === 'text' && numberField('maxLength', 'Max length (0 = 1000)')}
+              {formData.type === 'text' && numberField('minLength', 'Min length')}
+              {formData.type === 'multi_choice' && numberField('minSelections', 'Min selections')}
+              {formData.type === 'multi_choice' && numberField('maxSelections', 'Max selections (0 = any)')}
+            </div>
+            {formData.type === 'text' && (
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">
+                  Pattern (regular expression)
+                </label>
+                <input
+                  type="text"
+                  value={formData.pattern}
+                  onChange={(e) => setFormData({ ...formData, pattern: e.target.value })}
+                  className="input-field"
+                  placeholder="e.g., ^\d+$"
+                />
+              </div>
+            )}
+            <label className="flex items-center gap-3 text-sm text-gray-300">
+              <input
+                type="checkbox"
+                checked={formData.required}
+                onChange={(e) => setFormData({ ...formData, required: e.target.checked })}
+              />
+              Required
+            </label>
+            <div className="flex gap-3">
+              <button type="submit" className="btn-primary">
+                {editingQuestion ? 'Update' : 'Create'}
+              </button>
+              <button type="button" onClick={handleCancel} className="btn-secondary">
+                Cancel
+              </button>
+            </div>
+          </form>
+        </div>
+      )}
+
+      <div className="space-y-4">
+        {questions.map((question) => (
+          <div key={question.id} className="card">
+            <div className="flex justify-between items-start">
+              <div className="flex-1">
+                <h4 className="text-lg font-bold text-white mb-1">
+                  {question.label}
+                  {question.required && <span className="text-purple-300"> *</span>}
+                </h4>
+                <p className="text-purple-300 text-sm">
+                  {TYPE_LABELS[question.type]} • <code>{question.key}</code> • order {question.order}
+                </p>
+                {question.options && question.options.length > 0 && (
+                  <div className="flex flex-wrap gap-2 mt-3">
+                    {question.options.map((option) => (
+                      <span key={option} className="px-2 py-1 bg-purple-500/20 text-purple-300 rounded text-xs">
+                        {option}
+                      </span>
+                    ))}
+                  </div>
+                )}
+              </div>
+              <div className="flex gap-2 ml-4">
+                <button onClick={() => handleEdit(question)} className="btn-secondary text-sm">
+                  Edit
+                </button>
+                <button
+                  onClick={() => handleDelete(question.id)}
+                  className="bg-red-500/20 hover:bg-red-500/30 border border-red-500/50 rounded-lg px-4 py-2 text-red-200 text-sm transition-colors"
+                >
+                  Delete
+                </button>
+              </div>
+            </div>
+          </div>
+        ))}
+      </div>
+
+      {questions.length === 0 && !showForm && (
+        <div className="text-center py-12 text-gray-400">
+          No custom questions yet. Registrations ask for name, email and designation only.
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default QuestionManagement;
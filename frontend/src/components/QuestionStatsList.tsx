@@ -0,0 +1,45 @@
+import type { QuestionStats } from '../types';
+
+interface QuestionStatsListProps {
+  stats: QuestionStats[];
+}
+
+// Shows how confirmed attendees answered each custom question, as bars per
+// option for choice and yes/no questions.
+const QuestionStatsList = ({ stats }: QuestionStatsListProps) => {
+  return (
+    <div className="grid md:grid-cols-2 gap-6">
+      {stats.map((question) => {
+        const max = Math.max(1, ...(question.counts ?? []).map((c) => c.count));
+        return (
+          <div key={question.key} className="card">
+            <h4 className="text-lg font-bold text-white mb-1">{question.label}</h4>
+            <p className="text-gray-400 text-sm mb-4">{question.answered} answered</p>
+            {question.counts && (
+              <div className="space-y-2">
+                {question.counts.map((count) => (
+                  <div key={count.value}>
+                    <div className="flex justify-between text-sm text-gray-300">
+                      <span>
+                        {question.type === 'boolean' ? (count.value === 'true' ? 'Yes' : 'No') : count.value}
+                      </span>
+                      <span>{count.count}</span>
+                    </div>
+                    <div className="h-2 bg-white/10 rounded">
+                      <div
+                        className="h-2 rounded bg-gradient-to-r from-blue-500 to-purple-500"
+                        style={{ width: `${(count.count / max) * 100}%` }}
+                      />
+                    </div>
+                  </div>
+                ))}
+              </div>
+            )}
+          </div>
+        );
+      })}
+    </div>
+  );
+};
+
+export default QuestionStatsList;
@@ -1,32 +1,43 @@
-import { useState, useEffect } from 'react';
+import { useState, useEffect, useRef } from 'react';
 import { motion, AnimatePresence } from 'framer-motion';
-import { createAttendee, getAttendeeCount } from '../services/api';
-
-const DESIGNATIONS = [
-  'Software Engineer',
-  'Senior Software Engineer',
-  'Tech Lead',
-  'Engineering Manager',
-  'Product Manager',
-  'Data Scientist',
-  'ML Engineer',
-  'DevOps Engineer',
-  'Architect',
-  'Director',
-  'VP',
-  'Other',
-];
-
-const RegistrationForm = () => {
+import {
+  createAttendee,
+  getAttendeeCount,
+  getRegistrationForm,
+  getDesignationOptions,
+  getChallenge,
+  subscribeToEvents,
+} from '../services/api';
+import { solveChallenge } from '../services/challenge';
+import QuestionField from './QuestionField';
+import CaptchaWidget from './CaptchaWidget';
+import type { Question, Answers, RegistrationChallenge } from '../types';
+
+interface RegistrationFormProps {
+  onManageClick?: () => void;
+}
+
+const RegistrationForm = ({ onManageClick }: RegistrationFormProps) => {
   const [formData, setFormData] = useState({
     name: '',
     email: '',
     designation: '',
+    listPublicly: false,
   });
+  const [questions, setQuestions] = useState<Question[]>([]);
+  const [designations, setDesignations] = useState<string[]>([]);
+  const [answers, setAnswers] = useState<Answers>({});
   const [count, setCount] = useState<number | null>(null);
   const [submitting, setSubmitting] = useState(false);
   const [showSuccess, setShowSuccess] = useState(false);
   const [error, setError] = useState<string | null>(null);
+  // Bot protection. A challenge can be used once, so a fresh one is fetched
+  // after every attempt; its proof-of-work is solved while the form is filled.
+  const [challenge, setChallenge] = useState<RegistrationChallenge | null>(null);
+  const [challengeRound, setChallengeRound] = useState(0);
+  const solution = useRef<Promise<string> | null>(null);
+  const [captchaToken, setCaptchaToken] = useState<string | null>(null);
+  const [website, setWebsite] = useState('');
 
   useEffect(() => {
     const fetchCount = async () => {
@@ -39,11 +50,44 @@ const RegistrationForm = () => {
     };
 
     fetchCount();
-    // Poll for count updates every 10 seconds
-    const interval = setInterval(fetchCount, 10000);
-    return () => clearInterval(interval);
+    // The count is pushed as it changes; poll every 10 seconds only if the
+    // live stream is unavailable
+    let interval: ReturnType<typeof setInterval> | undefined;
+    const unsubscribe = subscribeToEvents({
+      count: setCount,
+      onUnavailable: () => {
+        if (!interval) interval = setInterval(fetchCount, 10000);
+      },
+    });
+    return () => {
+      unsubscribe();
+      clearInterval(interval);
+    };
   }, []);
 
+  useEffect(() => {
+    getRegistrationForm()
+      .then(setQuestions)
+      .catch((err) => console.error('Failed to load registration questions:', err));
+    getDesignationOptions()
+      .then(setDesignations)
+      .catch((err) => console.error('Failed to load designations:', err));
+  }, []);
+
+  useEffect(() => {
+    const controller = new AbortController();
+    getChallenge()
+      .then((c) => {
+        setChallenge(c);
+        setCaptchaToken(null);
+        solution.current = c?.token ? solveChallenge(c.token, c.difficulty, controller.signal) : null;
+        // Rejected only when abandoned for a newer challenge
+        solution.current?.catch(() => {});
+      })
+      .catch((err) => console.error('Failed to load registration challenge:', err));
+    return () => controller.abort();
+  }, [challengeRound]);
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
     setError(null);
@@ -53,11 +97,24 @@ const RegistrationForm = () => {
       return;
     }
 
+    if (challenge?.captchaSiteKey && !captchaToken) {
+      setError('Please complete the verification');
+      return;
+    }
+
     setSubmitting(true);
     try {
-      await createAttendee(formData);
+      await createAttendee({
+        ...formData,
+        answers,
+        challengeToken: challenge?.token || undefined,
+        challengeSolution: challenge?.token && solution.current ? await solution.current : undefined,
+        captchaToken: captchaToken ?? undefined,
+        website,
+      });
       setShowSuccess(true);
-      setFormData({ name: '', email: '', designation: '' });
+      setFormData({ name: '', email: '', designation: '', listPublicly: false });
+      setAnswers({});
       // Refresh count
       const newCount = await getAttendeeCount();
       setCount(newCount);
@@ -67,9 +124,17 @@ const RegistrationForm = () => {
         setShowSuccess(false);
       }, 3000);
     } catch (err: any) {
-      setError(err.response?.data?.error || 'Registration failed. Please try again.');
+      const details: { field: string; message: string }[] = err.response?.data?.details ?? [];
+      const labelFor = (field: string) =>
+        questions.find((q) => `answers.${q.key}` === field)?.label ?? field;
+      setError(
+        details.length > 0
+          ? details.map((d) => `${labelFor(d.field)} ${d.message}`).join('. ')
+          : err.response?.data?.error || 'Registration failed. Please try again.'
+      );
     } finally {
       setSubmitting(false);
+      setChallengeRound((round) => round + 1);
     }
   };
 
@@ -173,7 +238,7 @@ const RegistrationForm = () => {
                   required
                 >
                   <option value="">Select your designation</option>
-                  {DESIGNATIONS.map((designation) => (
+                  {designations.map((designation) => (
                     <option key={designation} value={designation} className="bg-slate-800">
                       {designation}
                     </option>
@@ -181,6 +246,45 @@ const RegistrationForm = () => {
                 </select>
               </div>
 
+              {questions.map((question) => (
+                <QuestionField
+                  key={question.id}
+                  question={question}
+                  value={answers[question.key]}
+                  onChange={(value) => setAnswers({ ...answers, [question.key]: value })}
+                />
+              ))}
+
+              <label htmlFor="listPublicly" className="flex items-start gap-3 text-sm text-gray-300">
+                <input
+                  id="listPublicly"
+                  type="checkbox"
+                  checked={formData.listPublicly}
+                  onChange={(e) => setFormData({ ...formData, listPublicly: e.target.checked })}
+                  className="mt-1"
+                />
+                <span>
+                  Show my first name and designation in the public attendee list
+                </span>
+              </label>
+
+              {/* Hidden from people; bots that fill in every field give themselves away */}
+              <div aria-hidden="true" className="absolute -left-[9999px] w-px h-px overflow-hidden">
+                <label htmlFor="website">Website</label>
+                <input
+                  id="website"
+                  type="text"
+                  tabIndex={-1}
+                  autoComplete="off"
+                  value={website}
+                  onChange={(e) => setWebsite(e.target.value)}
+                />
+              </div>
+
+              {challenge?.captchaSiteKey && (
+                <CaptchaWidget key={challengeRound} siteKey={challenge.captchaSiteKey} onToken={setCaptchaToken} />
+              )}
+
               {error && (
                 <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm">
                   {error}
@@ -194,6 +298,15 @@ const RegistrationForm = () => {
               >
                 {submitting ? 'Registering...' : 'Register'}
               </button>
+
+              {onManageClick && (
+                <p className="text-center text-sm text-gray-400">
+                  Already registered?{' '}
+                  <button type="button" onClick={onManageClick} className="text-purple-300 hover:text-white underline">
+                    Manage your registration
+                  </button>
+                </p>
+              )}
             </form>
           </motion.div>
         </div>
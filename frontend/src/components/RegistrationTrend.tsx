@@ -0,0 +1,67 @@
+import {
+  ComposedChart,
+  Bar,
+  Line,
+  XAxis,
+  YAxis,
+  CartesianGrid,
+  Tooltip,
+  Legend,
+  ResponsiveContainer,
+} from 'recharts';
+import type { Timeline } from '../types';
+
+interface RegistrationTrendProps {
+  timeline: Timeline;
+}
+
+// Bars are new registrations per bucket; the line is cumulative growth.
+// Bucket labels are shown in the event time zone.
+const RegistrationTrend = ({ timeline }: RegistrationTrendProps) => {
+  if (timeline.buckets.length === 0) {
+    return (
+      <div className="flex items-center justify-center h-64 text-gray-400">
+        No data available
+      </div>
+    );
+  }
+
+  const format = new Intl.DateTimeFormat(undefined, {
+    timeZone: timeline.timeZone,
+    month: 'short',
+    day: 'numeric',
+    ...(timeline.interval === 'hour' ? { hour: 'numeric' } : {}),
+  });
+  const data = timeline.buckets.map((bucket) => ({
+    label: format.format(new Date(bucket.start)),
+    registrations: bucket.registrations - bucket.cancelled,
+    cancelled: bucket.cancelled,
+    cumulative: bucket.cumulative,
+  }));
+
+  return (
+    <div className="w-full h-96">
+      <ResponsiveContainer width="100%" height="100%">
+        <ComposedChart data={data}>
+          <CartesianGrid strokeDasharray="3 3" stroke="rgba(255, 255, 255, 0.1)" />
+          <XAxis dataKey="label" stroke="#9ca3af" fontSize={12} />
+          <YAxis yAxisId="new" stroke="#9ca3af" fontSize={12} allowDecimals={false} />
+          <YAxis yAxisId="total" orientation="right" stroke="#9ca3af" fontSize={12} allowDecimals={false} />
+          <Tooltip
+            contentStyle={{
+              backgroundColor: 'rgba(0, 0, 0, 0.8)',
+              border: '1px solid rgba(255, 255, 255, 0.2)',
+              borderRadius: '8px',
+            }}
+          />
+          <Legend formatter={(value) => <span style={{ color: '#fff' }}>{value}</span>} />
+          <Bar yAxisId="new" dataKey="registrations" name="New" stackId="new" fill="#8b5cf6" />
+          <Bar yAxisId="new" dataKey="cancelled" name="Since cancelled" stackId="new" fill="#ef4444" />
+          <Line yAxisId="total" dataKey="cumulative" name="Total" stroke="#3b82f6" strokeWidth={2} dot={false} />
+        </ComposedChart>
+      </ResponsiveContainer>
+    </div>
+  );
+};
+
+export default RegistrationTrend;
@@ -0,0 +1,166 @@
+import { useEffect, useState } from 'react';
+import {
+  getSessions,
+  getSessionAttendance,
+  checkInToSession,
+  removeSessionAttendance,
+  searchAttendees,
+} from '../services/api';
+import type { Attendee, Session, SessionAttendance } from '../types';
+
+// SessionCheckIn records who is at each session. Only attendees checked in
+// to a session can rate it.
+const SessionCheckIn = () => {
+  const [sessions, setSessions] = useState<Session[]>([]);
+  const [sessionId, setSessionId] = useState('');
+  const [attendance, setAttendance] = useState<SessionAttendance[]>([]);
+  const [search, setSearch] = useState('');
+  const [results, setResults] = useState<Attendee[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    getSessions()
+      .then((all) => {
+        setSessions(all);
+        if (all.length > 0) setSessionId(all[0].id);
+      })
+      .catch((err) => console.error('Failed to load sessions:', err));
+  }, []);
+
+  useEffect(() => {
+    if (!sessionId) return;
+    let stale = false;
+    setLoading(true);
+    getSessionAttendance(sessionId)
+      .then((data) => {
+        if (stale) return;
+        setAttendance(data);
+        setError(null);
+      })
+      .catch((err) => !stale && setError(err.response?.data?.error || 'Failed to load check-ins'))
+      .finally(() => !stale && setLoading(false));
+    return () => {
+      stale = true;
+    };
+  }, [sessionId]);
+
+  // Search as the admin types, once they pause
+  useEffect(() => {
+    const q = search.trim();
+    if (!q) {
+      setResults([]);
+      return;
+    }
+    let stale = false;
+    const timer = setTimeout(async () => {
+      try {
+        const found = await searchAttendees(q);
+        if (!stale) setResults(found.results.filter((a) => a.status !== 'cancelled'));
+      } catch (err: any) {
+        if (!stale) setError(err.response?.data?.error || 'Search failed');
+      }
+    }, 200);
+    return () => {
+      stale = true;
+      clearTimeout(timer);
+    };
+  }, [search]);
+
+  const handleCheckIn = async (attendee: Attendee) => {
+    setError(null);
+    try {
+      const record = await checkInToSession(sessionId, attendee.id);
+      setAttendance((prev) => (prev.some((a) => a.id === record.id) ? prev : [...prev, record]));
+      setSearch('');
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Check-in failed');
+    }
+  };
+
+  const handleRemove = async (record: SessionAttendance) => {
+    setError(null);
+    try {
+      await removeSessionAttendance(sessionId, record.attendeeId);
+      setAttendance((prev) => prev.filter((a) => a.id !== record.id));
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Could not remove the check-in');
+    }
+  };
+
+  const checkedIn = new Set(attendance.map((a) => a.attendeeId));
+
+  return (
+    <div>
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Session Check-in ({attendance.length})</h3>
+      </div>
+
+      <div className="card mb-6 flex flex-col md:flex-row gap-3">
+        <select value={sessionId} onChange={(e) => setSessionId(e.target.value)} className="input-field flex-1">
+          {sessions.map((session) => (
+            <option key={session.id} value={session.id} className="bg-slate-800">
+              {session.title}
+            </option>
+          ))}
+        </select>
+        <input
+          type="search"
+          value={search}
+          onChange={(e) => setSearch(e.target.value)}
+          className="input-field md:w-64"
+          placeholder="Find an attendee to check in"
+          maxLength={100}
+        />
+      </div>
+
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-4">
+          {error}
+        </div>
+      )}
+
+      {results.length > 0 && (
+        <div className="card mb-6 space-y-2">
+          {results.map((attendee) => (
+            <div key={attendee.id} className="flex justify-between items-center gap-4">
+              <div>
+                <p className="text-white">{attendee.name}</p>
+                <p className="text-gray-400 text-sm">{attendee.email}</p>
+              </div>
+              {checkedIn.has(attendee.id) ? (
+                <span className="text-green-400 text-sm">Checked in</span>
+              ) : (
+                <button onClick={() => handleCheckIn(attendee)} className="btn-secondary text-sm">
+                  Check in
+                </button>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+
+      {loading ? (
+        <p className="text-gray-400 animate-pulse">Loading...</p>
+      ) : attendance.length === 0 ? (
+        <p className="text-gray-400">Nobody is checked in to this session yet.</p>
+      ) : (
+        <div className="card space-y-2">
+          {attendance.map((record) => (
+            <div key={record.id} className="flex justify-between items-center gap-4">
+              <p className="text-white">
+                {record.name || 'Unknown'}
+                <span className="text-gray-400 text-sm ml-2">{new Date(record.checkedInAt).toLocaleTimeString()}</span>
+              </p>
+              <button onClick={() => handleRemove(record)} className="btn-secondary text-sm">
+                Undo
+              </button>
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default SessionCheckIn;
@@ -0,0 +1,133 @@
+import { useEffect, useState } from 'react';
+import { getSessions, getMyAttendance, getMyFeedback, submitFeedback } from '../services/api';
+import type { Feedback, Session } from '../types';
+
+interface SessionFeedbackProps {
+  // Self-service token of the attendee giving feedback
+  token: string;
+}
+
+interface Draft {
+  rating: number;
+  comment: string;
+}
+
+// SessionFeedback lets an attendee rate the sessions they were checked in to
+// once they have started.
+const SessionFeedback = ({ token }: SessionFeedbackProps) => {
+  const [sessions, setSessions] = useState<Session[]>([]);
+  const [feedback, setFeedback] = useState<Record<string, Feedback>>({});
+  const [drafts, setDrafts] = useState<Record<string, Draft>>({});
+  const [saving, setSaving] = useState<string | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    const load = async () => {
+      try {
+        const [all, attended, mine] = await Promise.all([
+          getSessions(),
+          getMyAttendance(token),
+          getMyFeedback(token),
+        ]);
+        const now = Date.now();
+        const present = new Set(attended.map((a) => a.sessionId));
+        setSessions(all.filter((s) => present.has(s.id) && s.startsAt && new Date(s.startsAt).getTime() <= now));
+        setFeedback(Object.fromEntries(mine.map((f) => [f.sessionId, f])));
+        setDrafts(
+          Object.fromEntries(mine.map((f) => [f.sessionId, { rating: f.rating, comment: f.comment ?? '' }]))
+        );
+      } catch (err: any) {
+        setError(err.response?.data?.error || 'Could not load sessions to rate.');
+      }
+    };
+
+    load();
+  }, [token]);
+
+  const editable = (sessionId: string) => {
+    const existing = feedback[sessionId];
+    return !existing?.editableUntil || new Date(existing.editableUntil).getTime() > Date.now();
+  };
+
+  const handleSave = async (sessionId: string) => {
+    const draft = drafts[sessionId];
+    if (!draft?.rating) return;
+    setSaving(sessionId);
+    setError(null);
+    try {
+      const saved = await submitFeedback(token, sessionId, draft);
+      setFeedback({ ...feedback, [sessionId]: saved });
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Could not save your feedback.');
+    } finally {
+      setSaving(null);
+    }
+  };
+
+  if (sessions.length === 0 && !error) return null;
+
+  return (
+    <div className="pt-4 border-t border-white/20 space-y-4">
+      <h3 className="text-lg font-semibold text-white">Rate the sessions</h3>
+      {sessions.map((session) => {
+        const draft = drafts[session.id] ?? { rating: 0, comment: '' };
+        const canEdit = editable(session.id);
+        const existing = feedback[session.id];
+        return (
+          <div key={session.id} className="space-y-2">
+            <p className="text-gray-200 text-sm font-medium">{session.title}</p>
+            <div className="flex gap-1" role="radiogroup" aria-label={`Rating for ${session.title}`}>
+              {[1, 2, 3, 4, 5].map((rating) => (
+                <button
+                  key={rating}
+                  type="button"
+                  role="radio"
+                  aria-checked={draft.rating === rating}
+                  aria-label={`${rating} of 5`}
+                  disabled={!canEdit}
+                  onClick={() => setDrafts({ ...drafts, [session.id]: { ...draft, rating } })}
+                  className={`text-2xl ${rating <= draft.rating ? 'text-yellow-400' : 'text-gray-600'}`}
+                >
+                  ★
+                </button>
+              ))}
+            </div>
+            <textarea
+              value={draft.comment}
+              onChange={(e) => setDrafts({ ...drafts, [session.id]: { ...draft, comment: e.target.value } })}
+              disabled={!canEdit}
+              maxLength={2000}
+              rows={2}
+              className="input-field"
+              placeholder="Comments (optional)"
+            />
+            {canEdit ? (
+              <button
+                type="button"
+                onClick={() => handleSave(session.id)}
+                disabled={!draft.rating || saving === session.id}
+                className="btn-secondary text-sm"
+              >
+                {saving === session.id ? 'Saving...' : existing ? 'Update feedback' : 'Send feedback'}
+              </button>
+            ) : (
+              <p className="text-gray-500 text-xs">Thanks for your feedback. It can no longer be changed.</p>
+            )}
+            {existing?.editableUntil && canEdit && (
+              <p className="text-gray-500 text-xs">
+                You can change this until {new Date(existing.editableUntil).toLocaleString()}.
+              </p>
+            )}
+          </div>
+        );
+      })}
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm">
+          {error}
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default SessionFeedback;
@@ -1,21 +1,49 @@
 import { useEffect, useState } from 'react';
-import { getSessions, createSession, updateSession, deleteSession, getSpeakers } from '../services/api';
-import type { Session, Speaker } from '../types';
+import {
+  getSessions,
+  createSession,
+  updateSession,
+  deleteSession,
+  getSpeakers,
+  getTracks,
+  getVenues,
+} from '../services/api';
+import type { Session, Speaker, Track, Venue } from '../types';
+
+const emptyForm = {
+  title: '',
+  description: '',
+  time: '',
+  duration: '',
+  speakerIds: [] as string[],
+  startsAt: '',
+  endsAt: '',
+  trackId: '',
+  roomId: '',
+  tags: '',
+};
+
+// toLocalInput formats an ISO timestamp for a datetime-local input, which
+// works in the browser's time zone.
+const toLocalInput = (iso?: string): string => {
+  if (!iso) return '';
+  const d = new Date(iso);
+  return new Date(d.getTime() - d.getTimezoneOffset() * 60000).toISOString().slice(0, 16);
+};
+
+const fromLocalInput = (value: string): string | undefined =>
+  value ? new Date(value).toISOString() : undefined;
 
 const SessionManagement = () => {
   const [sessions, setSessions] = useState<Session[]>([]);
   const [speakers, setSpeakers] = useState<Speaker[]>([]);
+  const [tracks, setTracks] = useState<Track[]>([]);
+  const [venues, setVenues] = useState<Venue[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [showForm, setShowForm] = useState(false);
   const [editingSession, setEditingSession] = useState<Session | null>(null);
-  const [formData, setFormData] = useState({
-    title: '',
-    description: '',
-    time: '',
-    duration: '',
-    speakerIds: [] as string[],
-  });
+  const [formData, setFormData] = useState(emptyForm);
 
   useEffect(() => {
     fetchData();
@@ -24,12 +52,16 @@ const SessionManagement = () => {
   const fetchData = async () => {
     try {
       setLoading(true);
-      const [sessionsData, speakersData] = await Promise.all([
+      const [sessionsData, speakersData, tracksData, venuesData] = await Promise.all([
         getSessions(),
         getSpeakers(),
+        getTracks(),
+        getVenues(),
       ]);
       setSessions(sessionsData);
       setSpeakers(speakersData);
+      setTracks(tracksData);
+      setVenues(venuesData);
       setError(null);
     } catch (err) {
       setError('Failed to load data');
@@ -44,14 +76,20 @@ const SessionManagement = () => {
     setError(null);
 
     try {
+      const data = {
+        ...formData,
+        startsAt: fromLocalInput(formData.startsAt),
+        endsAt: fromLocalInput(formData.endsAt),
+        tags: formData.tags.split(',').map((t) => t.trim()).filter(Boolean),
+      };
       if (editingSession) {
-        await updateSession(editingSession.id, formData);
+        await updateSession(editingSession.id, data);
       } else {
-        await createSession(formData);
+        await createSession(data);
       }
       setShowForm(false);
       setEditingSession(null);
-      setFormData({ title: '', description: '', time: '', duration: '', speakerIds: [] });
+      setFormData(emptyForm);
       fetchData();
     } catch (err: any) {
       setError(err.response?.data?.error || 'Operation failed');
@@ -66,6 +104,11 @@ const SessionManagement = () => {
       time: session.time,
       duration: session.duration,
       speakerIds: session.speakerIds,
+      startsAt: toLocalInput(session.startsAt),
+      endsAt: toLocalInput(session.endsAt),
+      trackId: session.trackId ?? '',
+      roomId: session.roomId ?? '',
+      tags: (session.tags ?? []).join(', '),
     });
     setShowForm(true);
   };
@@ -84,7 +127,7 @@ const SessionManagement = () => {
   const handleCancel = () => {
     setShowForm(false);
     setEditingSession(null);
-    setFormData({ title: '', description: '', time: '', duration: '', speakerIds: [] });
+    setFormData(emptyForm);
   };
 
   if (loading) {
@@ -103,7 +146,7 @@ const SessionManagement = () => {
         <button
           onClick={() => {
             setEditingSession(null);
-            setFormData({ title: '', description: '', time: '', duration: '', speakerIds: [] });
+            setFormData(emptyForm);
             setShowForm(true);
           }}
           className="btn-primary"
@@ -173,6 +216,86 @@ const SessionManagement = () => {
                 />
               </div>
             </div>
+            <div className="grid grid-cols-2 gap-4">
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">
+                  Starts at
+                </label>
+                <input
+                  type="datetime-local"
+                  value={formData.startsAt}
+                  onChange={(e) => setFormData({ ...formData, startsAt: e.target.value })}
+                  className="input-field"
+                />
+              </div>
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">
+                  Ends at
+                </label>
+                <input
+                  type="datetime-local"
+                  value={formData.endsAt}
+                  onChange={(e) => setFormData({ ...formData, endsAt: e.target.value })}
+                  className="input-field"
+                />
+              </div>
+            </div>
+            <div className="grid grid-cols-2 gap-4">
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">
+                  Track
+                </label>
+                <select
+                  value={formData.trackId}
+                  onChange={(e) => setFormData({ ...formData, trackId: e.target.value })}
+                  className="input-field"
+                >
+                  <option value="" className="bg-slate-800">No track</option>
+                  {tracks.map((track) => (
+                    <option key={track.id} value={track.id} className="bg-slate-800">
+                      {track.name}
+                    </option>
+                  ))}
+                </select>
+              </div>
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">
+                  Room
+                </label>
+                <select
+                  value={formData.roomId}
+                  onChange={(e) => setFormData({ ...formData, roomId: e.target.value })}
+                  className="input-field"
+                >
+                  <option value="" className="bg-slate-800">No room</option>
+                  {venues.map((venue) => (
+                    <optgroup key={venue.id} label={venue.name} className="bg-slate-800">
+                      {(venue.rooms ?? []).map((room) => (
+                        <option key={room.id} value={room.id}>
+                          {room.name}
+                          {room.capacity > 0 ? ` (${room.capacity} seats)` : ''}
+                        </option>
+                      ))}
+                    </optgroup>
+                  ))}
+                </select>
+                {formData.roomId && (
+                  <p className="text-gray-400 text-xs mt-1">A session in a room needs a start and end time</p>
+                )}
+              </div>
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">
+                Tags
+              </label>
+              <input
+                type="text"
+                value={formData.tags}
+                onChange={(e) => setFormData({ ...formData, tags: e.target.value })}
+                className="input-field"
+                placeholder="Comma separated, e.g., LLM, hands-on"
+              />
+            </div>
             <div>
               <label className="block text-sm font-medium text-gray-300 mb-2">
                 Speakers
@@ -210,6 +333,8 @@ const SessionManagement = () => {
       <div className="space-y-4">
         {sessions.map((session) => {
           const sessionSpeakers = speakers.filter((s) => session.speakerIds.includes(s.id));
+          const track = tracks.find((t) => t.id === session.trackId);
+          const room = venues.flatMap((v) => v.rooms ?? []).find((r) => r.id === session.roomId);
           return (
             <div key={session.id} className="card">
               <div className="flex justify-between items-start mb-4">
@@ -220,6 +345,11 @@ const SessionManagement = () => {
                       {session.time} • {session.duration}
                     </p>
                   )}
+                  {(track || room) && (
+                    <p className="text-gray-400 text-sm mb-2">
+                      {[track?.name, room?.name].filter(Boolean).join(' • ')}
+                    </p>
+                  )}
                   {session.description && (
                     <p className="text-gray-300 text-sm">{session.description}</p>
                   )}
@@ -0,0 +1,195 @@
+import { useEffect, useState } from 'react';
+import {
+  getSessions,
+  getMyAttendance,
+  getQuestions,
+  askQuestion,
+  voteQuestion,
+  subscribeToEvents,
+} from '../services/api';
+import type { PublicQuestion, Session } from '../types';
+
+interface SessionQAProps {
+  // Self-service token of the attendee asking and voting
+  token: string;
+}
+
+// sortQuestions orders questions like the server's feed: pinned first, then
+// open questions before answered ones, most votes first and, among equals,
+// oldest first.
+export const sortQuestions = <T extends PublicQuestion>(questions: T[]): T[] =>
+  [...questions].sort(
+    (a, b) =>
+      Number(b.pinned) - Number(a.pinned) ||
+      Number(Boolean(a.answeredAt)) - Number(Boolean(b.answeredAt)) ||
+      b.votes - a.votes ||
+      a.createdAt.localeCompare(b.createdAt) ||
+      a.id.localeCompare(b.id)
+  );
+
+// SessionQA lets an attendee ask questions during a session and upvote
+// others'. The feed updates live; asking and voting are open to attendees
+// checked in to the session.
+const SessionQA = ({ token }: SessionQAProps) => {
+  const [sessions, setSessions] = useState<Session[]>([]);
+  const [sessionId, setSessionId] = useState('');
+  const [questions, setQuestions] = useState<PublicQuestion[]>([]);
+  const [attended, setAttended] = useState<Set<string>>(new Set());
+  const [text, setText] = useState('');
+  const [anonymous, setAnonymous] = useState(false);
+  const [working, setWorking] = useState(false);
+  const [notice, setNotice] = useState<string | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    getSessions()
+      .then((all) => {
+        setSessions(all);
+        if (all.length > 0) setSessionId(all[0].id);
+      })
+      .catch((err) => console.error('Failed to load sessions:', err));
+  }, []);
+
+  useEffect(() => {
+    getMyAttendance(token)
+      .then((records) => setAttended(new Set(records.map((a) => a.sessionId))))
+      .catch((err) => console.error('Failed to load check-ins:', err));
+  }, [token]);
+
+  useEffect(() => {
+    if (!sessionId) return;
+
+    const fetchQuestions = () =>
+      getQuestions(sessionId, token)
+        .then((data) => setQuestions(sortQuestions(data)))
+        .catch((err) => setError(err.response?.data?.error || 'Could not load the questions.'));
+
+    fetchQuestions();
+    setNotice(null);
+
+    // Live events carry no vote marks, so keep ours
+    return subscribeToEvents({
+      question: (change) => {
+        if (change.sessionId && change.sessionId !== sessionId) return;
+        setQuestions((prev) => {
+          const rest = prev.filter((q) => q.id !== change.id);
+          if (!change.data) return rest;
+          const voted = prev.find((q) => q.id === change.id)?.voted;
+          return sortQuestions([...rest, { ...change.data, voted }]);
+        });
+      },
+      reset: fetchQuestions,
+    });
+  }, [sessionId, token]);
+
+  const handleAsk = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!text.trim()) return;
+    setWorking(true);
+    setError(null);
+    setNotice(null);
+    try {
+      const question = await askQuestion(token, sessionId, { text, anonymous });
+      setText('');
+      if (question.status === 'pending') {
+        setNotice('Thanks! Your question will appear once a moderator approves it.');
+      }
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Could not send your question.');
+    } finally {
+      setWorking(false);
+    }
+  };
+
+  const handleVote = async (question: PublicQuestion) => {
+    setError(null);
+    try {
+      const updated = await voteQuestion(token, sessionId, question.id, !question.voted);
+      setQuestions((prev) => sortQuestions(prev.map((q) => (q.id === updated.id ? updated : q))));
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Could not record your vote.');
+    }
+  };
+
+  if (sessions.length === 0) return null;
+  const canTakePart = attended.has(sessionId);
+
+  return (
+    <div className="pt-4 border-t border-white/20 space-y-4">
+      <h3 className="text-lg font-semibold text-white">Live Q&amp;A</h3>
+      <select value={sessionId} onChange={(e) => setSessionId(e.target.value)} className="input-field">
+        {sessions.map((session) => (
+          <option key={session.id} value={session.id} className="bg-slate-800">
+            {session.title}
+          </option>
+        ))}
+      </select>
+
+      {canTakePart ? (
+        <form onSubmit={handleAsk} className="space-y-2">
+          <textarea
+            value={text}
+            onChange={(e) => setText(e.target.value)}
+            maxLength={2000}
+            rows={2}
+            className="input-field"
+            placeholder="Ask the speaker a question"
+          />
+          <div className="flex items-center justify-between gap-3">
+            <label className="flex items-center gap-2 text-gray-300 text-sm">
+              <input type="checkbox" checked={anonymous} onChange={(e) => setAnonymous(e.target.checked)} />
+              Ask anonymously
+            </label>
+            <button type="submit" disabled={working || !text.trim()} className="btn-secondary text-sm">
+              {working ? 'Sending...' : 'Ask'}
+            </button>
+          </div>
+        </form>
+      ) : (
+        <p className="text-gray-400 text-sm">Once you are checked in to this session, you can ask and vote here.</p>
+      )}
+
+      {notice && <p className="text-green-300 text-sm">{notice}</p>}
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm">
+          {error}
+        </div>
+      )}
+
+      {questions.length === 0 ? (
+        <p className="text-gray-400 text-sm">No questions yet.</p>
+      ) : (
+        <ul className="space-y-2">
+          {questions.map((question) => (
+            <li key={question.id} className="flex gap-3 items-start">
+              <button
+                type="button"
+                onClick={() => handleVote(question)}
+                disabled={!canTakePart}
+                aria-pressed={Boolean(question.voted)}
+                aria-label={question.voted ? 'Remove your upvote' : 'Upvote'}
+                className={`min-w-[3rem] rounded-lg px-2 py-1 text-sm ${
+                  question.voted ? 'bg-purple-500 text-white' : 'bg-white/10 text-gray-300'
+                }`}
+              >
+                ▲ {question.votes}
+              </button>
+              <div className="flex-1">
+                <p className={`text-sm ${question.answeredAt ? 'text-gray-500' : 'text-gray-200'}`}>
+                  {question.pinned && <span className="text-yellow-400 mr-1">📌</span>}
+                  {question.text}
+                </p>
+                <p className="text-gray-500 text-xs">
+                  {question.authorName || 'Anonymous'}
+                  {question.answeredAt && ' · Answered'}
+                </p>
+              </div>
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  );
+};
+
+export default SessionQA;
@@ -1,8 +1,12 @@
 import { useEffect, useState } from 'react';
 import { motion } from 'framer-motion';
-import { getSessions, getSpeakers } from '../services/api';
+import { getSessions, getSpeakers, subscribeToEvents } from '../services/api';
 import type { Session, Speaker } from '../types';
 
+// upsert replaces the item with the same id, or appends it.
+const upsert = <T extends { id: string }>(items: T[], item: T): T[] =>
+  items.some((i) => i.id === item.id) ? items.map((i) => (i.id === item.id ? item : i)) : [...items, item];
+
 const SessionsSpeakers = () => {
   const [sessions, setSessions] = useState<Session[]>([]);
   const [speakers, setSpeakers] = useState<Speaker[]>([]);
@@ -14,7 +18,7 @@ const SessionsSpeakers = () => {
       try {
         setLoading(true);
         const [sessionsData, speakersData] = await Promise.all([
-          getSessions(),
+          getSessions({ expand: 'speakers' }),
           getSpeakers(),
         ]);
         // Ensure we always have arrays, even if API returns null
@@ -33,11 +37,29 @@ const SessionsSpeakers = () => {
     };
 
     fetchData();
+
+    // Sessions embed their speakers and keep the server's order, so any
+    // agenda edit refreshes them
+    const refreshSessions = () =>
+      getSessions({ expand: 'speakers' })
+        .then(setSessions)
+        .catch((err) => console.error(err));
+
+    // Agenda edits are applied as they happen
+    return subscribeToEvents({
+      agenda: (change) => {
+        refreshSessions();
+        if (change.collection === 'speakers') {
+          setSpeakers((prev) =>
+            change.data ? upsert(prev, change.data) : prev.filter((s) => s.id !== change.id)
+          );
+        }
+      },
+      reset: fetchData,
+    });
   }, []);
 
-  const getSessionSpeakers = (session: Session): Speaker[] => {
-    return speakers.filter((speaker) => session.speakerIds.includes(speaker.id));
-  };
+  const getSessionSpeakers = (session: Session): Speaker[] => session.speakers ?? [];
 
   if (loading) {
     return (
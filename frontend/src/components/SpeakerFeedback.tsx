@@ -0,0 +1,73 @@
+import { useEffect, useState } from 'react';
+import { motion } from 'framer-motion';
+import { getSpeakerFeedback, exportSpeakerFeedback, downloadBlob } from '../services/api';
+import FeedbackSummaries from './FeedbackSummaries';
+import type { FeedbackSummary } from '../types';
+
+interface SpeakerFeedbackProps {
+  // Token from the #speaker= link fragment
+  token: string;
+  onClose: () => void;
+}
+
+const SpeakerFeedback = ({ token, onClose }: SpeakerFeedbackProps) => {
+  const [summaries, setSummaries] = useState<FeedbackSummary[] | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    getSpeakerFeedback(token)
+      .then(setSummaries)
+      .catch((err) => setError(err.response?.data?.error || 'Could not load your feedback.'));
+  }, [token]);
+
+  const handleExport = async () => {
+    setError(null);
+    try {
+      downloadBlob(await exportSpeakerFeedback(token), 'session-feedback.csv');
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Export failed. Please try again.');
+    }
+  };
+
+  return (
+    <div className="fixed inset-0 flex items-center justify-center z-50 p-4">
+      <div className="absolute inset-0 bg-black/70 backdrop-blur-sm" onClick={onClose} />
+      <motion.div
+        initial={{ opacity: 0, scale: 0.9 }}
+        animate={{ opacity: 1, scale: 1 }}
+        className="relative glass-strong rounded-2xl p-8 max-w-2xl w-full max-h-[90vh] overflow-y-auto"
+      >
+        <button
+          onClick={onClose}
+          className="absolute top-4 right-4 text-gray-400 hover:text-white transition-colors"
+        >
+          <svg className="w-6 h-6" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+            <path strokeLinecap="round" strokeLinejoin="round" strokeWidth={2} d="M6 18L18 6M6 6l12 12" />
+          </svg>
+        </button>
+
+        <h2 className="text-3xl font-bold text-white mb-2">Your Session Feedback</h2>
+        <p className="text-gray-400 mb-6">Ratings and comments are anonymous.</p>
+
+        {error && (
+          <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-4">
+            {error}
+          </div>
+        )}
+
+        {summaries === null && !error && <p className="text-gray-400 animate-pulse">Loading...</p>}
+
+        {summaries && (
+          <>
+            <FeedbackSummaries summaries={summaries} />
+            <button onClick={handleExport} className="btn-secondary mt-6">
+              Download CSV
+            </button>
+          </>
+        )}
+      </motion.div>
+    </div>
+  );
+};
+
+export default SpeakerFeedback;
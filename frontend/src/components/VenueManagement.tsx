@@ -0,0 +1,400 @@
+import { useEffect, useState } from 'react';
+import {
+  getVenues,
+  createVenue,
+  updateVenue,
+  deleteVenue,
+  createRoom,
+  updateRoom,
+  deleteRoom,
+  getTracks,
+  createTrack,
+  updateTrack,
+  deleteTrack,
+} from '../services/api';
+import type { Room, Track, Venue } from '../types';
+
+const emptyVenue = { name: '', address: '', directions: '', mapUrl: '' };
+const emptyRoom = { venueId: '', name: '', capacity: 0, accessibility: '' };
+const emptyTrack = { name: '', description: '', color: '#7c3aed', order: 0 };
+
+// Which form is open, and the record being edited, if any
+type Editing =
+  | { kind: 'venue'; id?: string }
+  | { kind: 'room'; id?: string }
+  | { kind: 'track'; id?: string }
+  | null;
+
+const VenueManagement = () => {
+  const [venues, setVenues] = useState<Venue[]>([]);
+  const [tracks, setTracks] = useState<Track[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [editing, setEditing] = useState<Editing>(null);
+  const [venueForm, setVenueForm] = useState(emptyVenue);
+  const [roomForm, setRoomForm] = useState(emptyRoom);
+  const [trackForm, setTrackForm] = useState(emptyTrack);
+
+  useEffect(() => {
+    fetchData();
+  }, []);
+
+  const fetchData = async () => {
+    try {
+      setLoading(true);
+      const [venuesData, tracksData] = await Promise.all([getVenues(), getTracks()]);
+      setVenues(venuesData);
+      setTracks(tracksData);
+      setError(null);
+    } catch (err) {
+      setError('Failed to load venues and tracks');
+      console.error(err);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  // run performs a change, then reloads everything so rooms stay under the
+  // right venue.
+  const run = async (action: () => Promise<unknown>, failure: string) => {
+    setError(null);
+    try {
+      await action();
+      setEditing(null);
+      fetchData();
+    } catch (err: any) {
+      setError(err.response?.data?.error || failure);
+    }
+  };
+
+  const editVenue = (venue?: Venue) => {
+    setVenueForm(
+      venue
+        ? { name: venue.name, address: venue.address, directions: venue.directions ?? '', mapUrl: venue.mapUrl ?? '' }
+        : emptyVenue
+    );
+    setEditing({ kind: 'venue', id: venue?.id });
+  };
+
+  const editRoom = (venueId: string, room?: Room) => {
+    setRoomForm(
+      room
+        ? { venueId: room.venueId, name: room.name, capacity: room.capacity, accessibility: room.accessibility ?? '' }
+        : { ...emptyRoom, venueId }
+    );
+    setEditing({ kind: 'room', id: room?.id });
+  };
+
+  const editTrack = (track?: Track) => {
+    setTrackForm(
+      track
+        ? { name: track.name, description: track.description ?? '', color: track.color ?? '', order: track.order }
+        : emptyTrack
+    );
+    setEditing({ kind: 'track', id: track?.id });
+  };
+
+  const submitVenue = (e: React.FormEvent) => {
+    e.preventDefault();
+    const id = editing?.id;
+    run(() => (id ? updateVenue(id, venueForm) : createVenue(venueForm)), 'Operation failed');
+  };
+
+  const submitRoom = (e: React.FormEvent) => {
+    e.preventDefault();
+    const id = editing?.id;
+    run(() => (id ? updateRoom(id, roomForm) : createRoom(roomForm)), 'Operation failed');
+  };
+
+  const submitTrack = (e: React.FormEvent) => {
+    e.preventDefault();
+    const id = editing?.id;
+    run(() => (id ? updateTrack(id, trackForm) : createTrack(trackForm)), 'Operation failed');
+  };
+
+  if (loading) {
+    return (
+      <div className="text-center py-12">
+        <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-white mx-auto"></div>
+        <p className="mt-4 text-gray-300">Loading...</p>
+      </div>
+    );
+  }
+
+  const formButtons = (
+    <div className="flex gap-3">
+      <button type="submit" className="btn-primary">
+        {editing?.id ? 'Update' : 'Create'}
+      </button>
+      <button type="button" onClick={() => setEditing(null)} className="btn-secondary">
+        Cancel
+      </button>
+    </div>
+  );
+
+  return (
+    <div>
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-4">
+          {error}
+        </div>
+      )}
+
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Venues ({venues.length})</h3>
+        <button onClick={() => editVenue()} className="btn-primary">
+          Add Venue
+        </button>
+      </div>
+
+      {editing?.kind === 'venue' && (
+        <div className="card mb-6">
+          <h4 className="text-xl font-bold text-white mb-4">{editing.id ? 'Edit Venue' : 'Add New Venue'}</h4>
+          <form onSubmit={submitVenue} className="space-y-4">
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">Name *</label>
+              <input
+                type="text"
+                value={venueForm.name}
+                onChange={(e) => setVenueForm({ ...venueForm, name: e.target.value })}
+                className="input-field"
+                required
+                maxLength={200}
+              />
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">Address</label>
+              <input
+                type="text"
+                value={venueForm.address}
+                onChange={(e) => setVenueForm({ ...venueForm, address: e.target.value })}
+                className="input-field"
+                maxLength={500}
+              />
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">Directions</label>
+              <textarea
+                value={venueForm.directions}
+                onChange={(e) => setVenueForm({ ...venueForm, directions: e.target.value })}
+                className="input-field"
+                rows={3}
+                maxLength={2000}
+              />
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">Map embed URL</label>
+              <input
+                type="url"
+                value={venueForm.mapUrl}
+                onChange={(e) => setVenueForm({ ...venueForm, mapUrl: e.target.value })}
+                className="input-field"
+                placeholder="https://www.google.com/maps/embed?..."
+              />
+            </div>
+            {formButtons}
+          </form>
+        </div>
+      )}
+
+      {editing?.kind === 'room' && (
+        <div className="card mb-6">
+          <h4 className="text-xl font-bold text-white mb-4">{editing.id ? 'Edit Room' : 'Add New Room'}</h4>
+          <form onSubmit={submitRoom} className="space-y-4">
+            <div className="grid grid-cols-2 gap-4">
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">Name *</label>
+                <input
+                  type="text"
+                  value={roomForm.name}
+                  onChange={(e) => setRoomForm({ ...roomForm, name: e.target.value })}
+                  className="input-field"
+                  required
+                  maxLength={100}
+                />
+              </div>
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">Capacity</label>
+                <input
+                  type="number"
+                  min={0}
+                  value={roomForm.capacity}
+                  onChange={(e) => setRoomForm({ ...roomForm, capacity: Number(e.target.value) })}
+                  className="input-field"
+                />
+                <p className="text-gray-400 text-xs mt-1">0 if not known</p>
+              </div>
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">Venue</label>
+              <select
+                value={roomForm.venueId}
+                onChange={(e) => setRoomForm({ ...roomForm, venueId: e.target.value })}
+                className="input-field"
+              >
+                {venues.map((venue) => (
+                  <option key={venue.id} value={venue.id} className="bg-slate-800">
+                    {venue.name}
+                  </option>
+                ))}
+              </select>
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">Accessibility notes</label>
+              <textarea
+                value={roomForm.accessibility}
+                onChange={(e) => setRoomForm({ ...roomForm, accessibility: e.target.value })}
+                className="input-field"
+                rows={2}
+                maxLength={1000}
+                placeholder="e.g., Step-free access, hearing loop"
+              />
+            </div>
+            {formButtons}
+          </form>
+        </div>
+      )}
+
+      <div className="space-y-4 mb-10">
+        {venues.map((venue) => (
+          <div key={venue.id} className="card">
+            <div className="flex justify-between items-start mb-4">
+              <div className="flex-1">
+                <h4 className="text-xl font-bold text-white mb-1">{venue.name}</h4>
+                {venue.address && <p className="text-gray-300 text-sm">{venue.address}</p>}
+              </div>
+              <div className="flex gap-2">
+                <button onClick={() => editRoom(venue.id)} className="btn-secondary text-sm">
+                  Add Room
+                </button>
+                <button onClick={() => editVenue(venue)} className="btn-secondary text-sm">
+                  Edit
+                </button>
+                <button
+                  onClick={() => confirm('Delete this venue?') && run(() => deleteVenue(venue.id), 'Delete failed')}
+                  className="bg-red-500/20 hover:bg-red-500/30 border border-red-500/50 rounded-lg px-4 py-2 text-red-200 text-sm transition-colors"
+                >
+                  Delete
+                </button>
+              </div>
+            </div>
+            {(venue.rooms ?? []).length === 0 ? (
+              <p className="text-gray-400 text-sm">No rooms yet.</p>
+            ) : (
+              <div className="space-y-2">
+                {(venue.rooms ?? []).map((room) => (
+                  <div key={room.id} className="flex justify-between items-start bg-white/5 rounded-lg p-3">
+                    <div>
+                      <p className="text-white font-medium">
+                        {room.name}
+                        {room.capacity > 0 && <span className="text-gray-400 text-sm"> · {room.capacity} seats</span>}
+                      </p>
+                      {room.accessibility && <p className="text-gray-400 text-xs mt-1">{room.accessibility}</p>}
+                    </div>
+                    <div className="flex gap-2">
+                      <button onClick={() => editRoom(venue.id, room)} className="text-purple-300 text-sm hover:underline">
+                        Edit
+                      </button>
+                      <button
+                        onClick={() => confirm('Delete this room?') && run(() => deleteRoom(room.id), 'Delete failed')}
+                        className="text-red-300 text-sm hover:underline"
+                      >
+                        Delete
+                      </button>
+                    </div>
+                  </div>
+                ))}
+              </div>
+            )}
+          </div>
+        ))}
+      </div>
+
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Tracks ({tracks.length})</h3>
+        <button onClick={() => editTrack()} className="btn-primary">
+          Add Track
+        </button>
+      </div>
+
+      {editing?.kind === 'track' && (
+        <div className="card mb-6">
+          <h4 className="text-xl font-bold text-white mb-4">{editing.id ? 'Edit Track' : 'Add New Track'}</h4>
+          <form onSubmit={submitTrack} className="space-y-4">
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">Name *</label>
+              <input
+                type="text"
+                value={trackForm.name}
+                onChange={(e) => setTrackForm({ ...trackForm, name: e.target.value })}
+                className="input-field"
+                required
+                maxLength={100}
+              />
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">Description</label>
+              <input
+                type="text"
+                value={trackForm.description}
+                onChange={(e) => setTrackForm({ ...trackForm, description: e.target.value })}
+                className="input-field"
+                maxLength={500}
+              />
+            </div>
+            <div className="grid grid-cols-2 gap-4">
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">Colour</label>
+                <input
+                  type="color"
+                  value={trackForm.color || '#7c3aed'}
+                  onChange={(e) => setTrackForm({ ...trackForm, color: e.target.value })}
+                  className="input-field h-10"
+                />
+              </div>
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-2">Order</label>
+                <input
+                  type="number"
+                  value={trackForm.order}
+                  onChange={(e) => setTrackForm({ ...trackForm, order: Number(e.target.value) })}
+                  className="input-field"
+                />
+              </div>
+            </div>
+            {formButtons}
+          </form>
+        </div>
+      )}
+
+      <div className="space-y-4">
+        {tracks.map((track) => (
+          <div key={track.id} className="card">
+            <div className="flex justify-between items-start">
+              <div className="flex-1 flex items-start gap-3">
+                <span className="w-3 h-3 rounded-full mt-2" style={{ backgroundColor: track.color || '#7c3aed' }} />
+                <div>
+                  <h4 className="text-lg font-bold text-white">{track.name}</h4>
+                  {track.description && <p className="text-gray-300 text-sm">{track.description}</p>}
+                </div>
+              </div>
+              <div className="flex gap-2">
+                <button onClick={() => editTrack(track)} className="btn-secondary text-sm">
+                  Edit
+                </button>
+                <button
+                  onClick={() => confirm('Delete this track?') && run(() => deleteTrack(track.id), 'Delete failed')}
+                  className="bg-red-500/20 hover:bg-red-500/30 border border-red-500/50 rounded-lg px-4 py-2 text-red-200 text-sm transition-colors"
+                >
+                  Delete
+                </button>
+              </div>
+            </div>
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+};
+
+export default VenueManagement;
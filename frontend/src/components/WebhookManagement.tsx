@@ -0,0 +1,318 @@
+import { useEffect, useState } from 'react';
+import {
+  getWebhooks,
+  createWebhook,
+  updateWebhook,
+  deleteWebhook,
+  testWebhook,
+  getWebhookDeliveries,
+  replayWebhookDelivery,
+} from '../services/api';
+import { WEBHOOK_EVENTS } from '../types';
+import type { Webhook, WebhookDelivery, WebhookEvent } from '../types';
+
+const emptyForm = { url: '', events: [] as WebhookEvent[], active: true };
+
+const statusColors: Record<WebhookDelivery['status'], string> = {
+  pending: 'bg-yellow-500/20 text-yellow-200',
+  succeeded: 'bg-green-500/20 text-green-200',
+  failed: 'bg-red-500/20 text-red-200',
+};
+
+const WebhookManagement = () => {
+  const [webhooks, setWebhooks] = useState<Webhook[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [showForm, setShowForm] = useState(false);
+  const [editingWebhook, setEditingWebhook] = useState<Webhook | null>(null);
+  const [formData, setFormData] = useState(emptyForm);
+  // Shown once, right after a webhook is created
+  const [newSecret, setNewSecret] = useState<string | null>(null);
+  const [logFor, setLogFor] = useState<Webhook | null>(null);
+  const [deliveries, setDeliveries] = useState<WebhookDelivery[]>([]);
+
+  useEffect(() => {
+    fetchWebhooks();
+  }, []);
+
+  const fetchWebhooks = async () => {
+    try {
+      setLoading(true);
+      setWebhooks(await getWebhooks());
+      setError(null);
+    } catch (err) {
+      setError('Failed to load webhooks');
+      console.error(err);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const fetchDeliveries = async (webhook: Webhook) => {
+    try {
+      setLogFor(webhook);
+      setDeliveries(await getWebhookDeliveries(webhook.id));
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load deliveries');
+    }
+  };
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError(null);
+
+    if (formData.events.length === 0) {
+      setError('Choose at least one event');
+      return;
+    }
+
+    try {
+      if (editingWebhook) {
+        await updateWebhook(editingWebhook.id, formData);
+      } else {
+        const created = await createWebhook(formData);
+        setNewSecret(created.secret ?? null);
+      }
+      handleCancel();
+      fetchWebhooks();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Operation failed');
+    }
+  };
+
+  const handleEdit = (webhook: Webhook) => {
+    setEditingWebhook(webhook);
+    setFormData({ url: webhook.url, events: webhook.events, active: webhook.active });
+    setShowForm(true);
+  };
+
+  const handleDelete = async (id: string) => {
+    if (!confirm('Delete this webhook? Queued deliveries to it will fail.')) return;
+
+    try {
+      await deleteWebhook(id);
+      if (logFor?.id === id) setLogFor(null);
+      fetchWebhooks();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Delete failed');
+    }
+  };
+
+  const handleTest = async (webhook: Webhook) => {
+    try {
+      setError(null);
+      const delivery = await testWebhook(webhook.id);
+      if (delivery.status !== 'succeeded') {
+        setError(`Test failed: ${delivery.error ?? 'no response'}`);
+      }
+      fetchDeliveries(webhook);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Test failed');
+    }
+  };
+
+  const handleReplay = async (delivery: WebhookDelivery) => {
+    try {
+      const replayed = await replayWebhookDelivery(delivery.webhookId, delivery.id);
+      setDeliveries(deliveries.map((d) => (d.id === replayed.id ? replayed : d)));
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Replay failed');
+    }
+  };
+
+  const handleCancel = () => {
+    setShowForm(false);
+    setEditingWebhook(null);
+    setFormData(emptyForm);
+  };
+
+  const toggleEvent = (event: WebhookEvent) => {
+    setFormData({
+      ...formData,
+      events: formData.events.includes(event)
+        ? formData.events.filter((e) => e !== event)
+        : [...formData.events, event],
+    });
+  };
+
+  if (loading) {
+    return (
+      <div className="text-center py-12">
+        <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-white mx-auto"></div>
+        <p className="mt-4 text-gray-300">Loading...</p>
+      </div>
+    );
+  }
+
+  return (
+    <div>
+      <div className="flex justify-between items-center mb-6">
+        <h3 className="text-2xl font-bold text-white">Webhooks ({webhooks.length})</h3>
+        <button
+          onClick={() => {
+            setEditingWebhook(null);
+            setFormData(emptyForm);
+            setShowForm(true);
+          }}
+          className="btn-primary"
+        >
+          Add Webhook
+        </button>
+      </div>
+
+      {error && (
+        <div className="bg-red-500/20 border border-red-500/50 rounded-lg p-3 text-red-200 text-sm mb-4">
+          {error}
+        </div>
+      )}
+
+      {newSecret && (
+        <div className="card mb-6 text-sm text-gray-300">
+          <p>Signing secret for the new webhook. Copy it now; it is not shown again:</p>
+          <code className="block mt-2 p-2 bg-black/30 rounded text-purple-200 break-all">{newSecret}</code>
+          <button onClick={() => setNewSecret(null)} className="btn-secondary text-sm mt-3">
+            Done
+          </button>
+        </div>
+      )}
+
+      {showForm && (
+        <div className="card mb-6">
+          <h4 className="text-xl font-bold text-white mb-4">
+            {editingWebhook ? 'Edit Webhook' : 'Add New Webhook'}
+          </h4>
+          <form onSubmit={handleSubmit} className="space-y-4">
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">
+                URL *
+              </label>
+              <input
+                type="url"
+                value={formData.url}
+                onChange={(e) => setFormData({ ...formData, url: e.target.value })}
+                className="input-field"
+                placeholder="https://hooks.example.com/workshop"
+                required
+              />
+            </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-300 mb-2">
+                Events *
+              </label>
+              <div className="grid grid-cols-1 sm:grid-cols-3 gap-2">
+                {WEBHOOK_EVENTS.map((event) => (
+                  <label key={event} className="flex items-center gap-2 text-gray-300 text-sm">
+                    <input
+                      type="checkbox"
+                      checked={formData.events.includes(event)}
+                      onChange={() => toggleEvent(event)}
+                    />
+                    {event}
+                  </label>
+                ))}
+              </div>
+            </div>
+            <label className="flex items-center gap-2 text-gray-300 text-sm">
+              <input
+                type="checkbox"
+                checked={formData.active}
+                onChange={(e) => setFormData({ ...formData, active: e.target.checked })}
+              />
+              Active
+            </label>
+            <div className="flex gap-3">
+              <button type="submit" className="btn-primary">
+                {editingWebhook ? 'Update' : 'Create'}
+              </button>
+              <button type="button" onClick={handleCancel} className="btn-secondary">
+                Cancel
+              </button>
+            </div>
+          </form>
+        </div>
+      )}
+
+      <div className="space-y-4">
+        {webhooks.map((webhook) => (
+          <div key={webhook.id} className="card">
+            <div className="flex justify-between items-start">
+              <div className="flex-1 min-w-0">
+                <h4 className="text-lg font-bold text-white mb-1 break-all">
+                  {webhook.url}
+                  {!webhook.active && <span className="ml-2 text-sm text-gray-400">(inactive)</span>}
+                </h4>
+                <div className="flex flex-wrap gap-2 mt-2">
+                  {webhook.events.map((event) => (
+                    <span key={event} className="px-2 py-1 bg-purple-500/20 text-purple-300 rounded text-xs">
+                      {event}
+                    </span>
+                  ))}
+                </div>
+              </div>
+              <div className="flex gap-2 ml-4">
+                <button onClick={() => handleTest(webhook)} className="btn-secondary text-sm">
+                  Test
+                </button>
+                <button onClick={() => fetchDeliveries(webhook)} className="btn-secondary text-sm">
+                  Deliveries
+                </button>
+                <button onClick={() => handleEdit(webhook)} className="btn-secondary text-sm">
+                  Edit
+                </button>
+                <button
+                  onClick={() => handleDelete(webhook.id)}
+                  className="bg-red-500/20 hover:bg-red-500/30 border border-red-500/50 rounded-lg px-4 py-2 text-red-200 text-sm transition-colors"
+                >
+                  Delete
+                </button>
+              </div>
+            </div>
+
+            {logFor?.id === webhook.id && (
+              <div className="mt-4 border-t border-white/10 pt-4">
+                <div className="flex justify-between items-center mb-2">
+                  <h5 className="text-white font-semibold">Recent deliveries</h5>
+                  <button onClick={() => fetchDeliveries(webhook)} className="btn-secondary text-xs">
+                    Refresh
+                  </button>
+                </div>
+                {deliveries.length === 0 && <p className="text-gray-400 text-sm">Nothing sent yet.</p>}
+                <div className="space-y-2">
+                  {deliveries.map((delivery) => (
+                    <div key={delivery.id} className="flex items-center justify-between gap-4 text-sm text-gray-300">
+                      <div className="min-w-0">
+                        <span className={`px-2 py-1 rounded text-xs mr-2 ${statusColors[delivery.status]}`}>
+                          {delivery.status}
+                        </span>
+                        <span className="text-white">{delivery.event}</span>
+                        <span className="ml-2 text-gray-400">
+                          {new Date(delivery.createdAt).toLocaleString()} · {delivery.attempts} attempt
+                          {delivery.attempts === 1 ? '' : 's'}
+                          {delivery.responseStatus ? ` · HTTP ${delivery.responseStatus}` : ''}
+                        </span>
+                        {delivery.error && <p className="text-red-300 text-xs mt-1 break-all">{delivery.error}</p>}
+                      </div>
+                      {delivery.status !== 'pending' && (
+                        <button onClick={() => handleReplay(delivery)} className="btn-secondary text-xs">
+                          Replay
+                        </button>
+                      )}
+                    </div>
+                  ))}
+                </div>
+              </div>
+            )}
+          </div>
+        ))}
+      </div>
+
+      {webhooks.length === 0 && !showForm && (
+        <div className="text-center py-12 text-gray-400">
+          <p>No webhooks yet. Add one to notify your CRM or Slack when people register or the agenda changes.</p>
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default WebhookManagement;
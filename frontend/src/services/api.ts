@@ -1,5 +1,38 @@
 import axios from 'axios';
-import type { Attendee, AttendeeCount, Speaker, Session, AdminStats, DesignationStats } from '../types';
+import type {
+  Attendee,
+  AttendeeCount,
+  AttendeeSearchResult,
+  PublicAttendee,
+  Speaker,
+  Session,
+  AdminStats,
+  DesignationStats,
+  StatsFilters,
+  PrivacyRequest,
+  DataExport,
+  Question,
+  Answers,
+  Designation,
+  DesignationBackfill,
+  AgendaChange,
+  AgendaGrid,
+  Venue,
+  Room,
+  Track,
+  Webhook,
+  WebhookDelivery,
+  WebhookEvent,
+  Feedback,
+  FeedbackSummary,
+  SpeakerLink,
+  LiveQuestion,
+  PublicQuestion,
+  QuestionChange,
+  RegistrationChallenge,
+  SessionAttendance,
+} from '../types';
+import { newTraceparent, traceId } from './tracing';
 
 // Use relative path for Vite proxy in development, or full URL for production
 const API_URL = import.meta.env.VITE_API_URL || '/api';
@@ -12,35 +45,140 @@ const api = axios.create({
   withCredentials: true,
 });
 
+// Every request starts a trace that the server continues
+api.interceptors.request.use((config) => {
+  config.headers.set('traceparent', newTraceparent());
+  return config;
+});
+
 // Add error interceptor for better error handling
 api.interceptors.response.use(
   (response) => response,
   (error) => {
-    console.error('API Error:', error.response?.data || error.message);
+    const traceparent = error.config?.headers?.get?.('traceparent');
+    console.error(
+      'API Error:',
+      error.response?.data || error.message,
+      ...(typeof traceparent === 'string' ? [`(trace ${traceId(traceparent)})`] : [])
+    );
     return Promise.reject(error);
   }
 );
 
 // Attendees
 export const getAttendees = async (): Promise<Attendee[]> => {
-  const response = await api.get<Attendee[]>('/attendees');
+  const response = await api.get<Attendee[]>('/admin/attendees');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const searchAttendees = async (q: string, designations: string[] = []): Promise<AttendeeSearchResult> => {
+  const response = await api.get<AttendeeSearchResult>('/admin/attendees/search', {
+    params: { q, designation: designations },
+    paramsSerializer: { indexes: null },
+  });
+  return response.data;
+};
+
+export const checkInAttendee = async (id: string): Promise<Attendee> => {
+  const response = await api.post<Attendee>(`/admin/attendees/${id}/check-in`);
   return response.data;
 };
 
+export const getPublicAttendees = async (): Promise<PublicAttendee[]> => {
+  const response = await api.get<PublicAttendee[]>('/attendees/public');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
 export const getAttendeeCount = async (): Promise<number> => {
   const response = await api.get<AttendeeCount>('/attendees/count');
   return response.data.count;
 };
 
+export const getRegistrationForm = async (): Promise<Question[]> => {
+  const response = await api.get<{ questions: Question[] }>('/attendees/form');
+  return response.data.questions ?? [];
+};
+
 export const createAttendee = async (data: {
   name: string;
   email: string;
   designation: string;
+  listPublicly?: boolean;
+  answers?: Answers;
+  challengeToken?: string;
+  challengeSolution?: string;
+  captchaToken?: string;
+  website?: string;
 }): Promise<Attendee> => {
   const response = await api.post<Attendee>('/attendees', data);
   return response.data;
 };
 
+// getChallenge returns the bot protection the registration form must pass,
+// or null when none is configured. Each challenge can be used once.
+export const getChallenge = async (): Promise<RegistrationChallenge | null> => {
+  const response = await api.get<{ challenge: RegistrationChallenge | null }>('/attendees/challenge');
+  return response.data.challenge ?? null;
+};
+
+// Attendee self-service. The token comes from an emailed magic link and is
+// sent as a bearer token instead of the admin cookie.
+const selfServiceHeaders = (token: string) => ({ Authorization: `Bearer ${token}` });
+
+export const requestMagicLink = async (email: string): Promise<void> => {
+  await api.post('/attendees/magic-link', { email });
+};
+
+export const getMyRegistration = async (token: string): Promise<Attendee> => {
+  const response = await api.get<Attendee>('/attendees/me', { headers: selfServiceHeaders(token) });
+  return response.data;
+};
+
+export const updateMyRegistration = async (
+  token: string,
+  data: { name: string; designation: string; listPublicly?: boolean; answers?: Answers }
+): Promise<Attendee> => {
+  const response = await api.put<Attendee>('/attendees/me', data, { headers: selfServiceHeaders(token) });
+  return response.data;
+};
+
+export const cancelMyRegistration = async (token: string): Promise<void> => {
+  await api.delete('/attendees/me', { headers: selfServiceHeaders(token) });
+};
+
+export const exportMyData = async (token: string): Promise<DataExport> => {
+  const response = await api.get<DataExport>('/attendees/me/export', { headers: selfServiceHeaders(token) });
+  return response.data;
+};
+
+export const requestMyErasure = async (token: string): Promise<PrivacyRequest> => {
+  const response = await api.post<PrivacyRequest>('/attendees/me/erasure', null, { headers: selfServiceHeaders(token) });
+  return response.data;
+};
+
+export const getMyFeedback = async (token: string): Promise<Feedback[]> => {
+  const response = await api.get<Feedback[]>('/attendees/me/feedback', { headers: selfServiceHeaders(token) });
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const getMyAttendance = async (token: string): Promise<SessionAttendance[]> => {
+  const response = await api.get<SessionAttendance[]>('/attendees/me/attendance', {
+    headers: selfServiceHeaders(token),
+  });
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const submitFeedback = async (
+  token: string,
+  sessionId: string,
+  data: { rating: number; comment?: string }
+): Promise<Feedback> => {
+  const response = await api.put<Feedback>(`/attendees/me/feedback/${sessionId}`, data, {
+    headers: selfServiceHeaders(token),
+  });
+  return response.data;
+};
+
 // Speakers
 export const getSpeakers = async (): Promise<Speaker[]> => {
   const response = await api.get<Speaker[]>('/speakers');
@@ -75,17 +213,50 @@ export const deleteSpeaker = async (id: string): Promise<void> => {
 };
 
 // Sessions
-export const getSessions = async (): Promise<Session[]> => {
-  const response = await api.get<Session[]>('/sessions');
+export interface SessionQuery {
+  day?: string;
+  track?: string[];
+  room?: string[];
+  tag?: string[];
+  speaker?: string[];
+  sort?: 'startsAt' | '-startsAt';
+  expand?: 'speakers';
+}
+
+export const getSessions = async (query: SessionQuery = {}): Promise<Session[]> => {
+  const response = await api.get<Session[]>('/sessions', {
+    params: query,
+    paramsSerializer: { indexes: null },
+  });
   return Array.isArray(response.data) ? response.data : [];
 };
 
+// Session check-in, which feedback requires
+export const getSessionAttendance = async (sessionId: string): Promise<SessionAttendance[]> => {
+  const response = await api.get<SessionAttendance[]>(`/admin/sessions/${sessionId}/attendance`);
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const checkInToSession = async (sessionId: string, attendeeId: string): Promise<SessionAttendance> => {
+  const response = await api.post<SessionAttendance>(`/admin/sessions/${sessionId}/attendance/${attendeeId}`);
+  return response.data;
+};
+
+export const removeSessionAttendance = async (sessionId: string, attendeeId: string): Promise<void> => {
+  await api.delete(`/admin/sessions/${sessionId}/attendance/${attendeeId}`);
+};
+
 export const createSession = async (data: {
   title: string;
   description: string;
   time: string;
   duration: string;
   speakerIds: string[];
+  startsAt?: string;
+  endsAt?: string;
+  trackId?: string;
+  roomId?: string;
+  tags?: string[];
 }): Promise<Session> => {
   const response = await api.post<Session>('/admin/sessions', data);
   return response.data;
@@ -99,6 +270,12 @@ export const updateSession = async (
     time: string;
     duration: string;
     speakerIds: string[];
+    startsAt?: string;
+    endsAt?: string;
+    // An empty string takes the session out of its track or room
+    trackId: string;
+    roomId: string;
+    tags?: string[];
   }>
 ): Promise<Session> => {
   const response = await api.put<Session>(`/admin/sessions/${id}`, data);
@@ -109,13 +286,342 @@ export const deleteSession = async (id: string): Promise<void> => {
   await api.delete(`/admin/sessions/${id}`);
 };
 
+export const getAgenda = async (query: Omit<SessionQuery, 'sort'> = {}): Promise<AgendaGrid> => {
+  const response = await api.get<AgendaGrid>('/agenda', {
+    params: query,
+    paramsSerializer: { indexes: null },
+  });
+  return response.data;
+};
+
+// Venues, rooms and tracks
+type VenueInput = Omit<Venue, 'id' | 'rooms'>;
+type RoomInput = Omit<Room, 'id'>;
+type TrackInput = Omit<Track, 'id'>;
+
+export const getVenues = async (): Promise<Venue[]> => {
+  const response = await api.get<Venue[]>('/venues');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const createVenue = async (data: VenueInput): Promise<Venue> => {
+  const response = await api.post<Venue>('/admin/venues', data);
+  return response.data;
+};
+
+export const updateVenue = async (id: string, data: VenueInput): Promise<Venue> => {
+  const response = await api.put<Venue>(`/admin/venues/${id}`, data);
+  return response.data;
+};
+
+export const deleteVenue = async (id: string): Promise<void> => {
+  await api.delete(`/admin/venues/${id}`);
+};
+
+export const createRoom = async (data: RoomInput): Promise<Room> => {
+  const response = await api.post<Room>('/admin/rooms', data);
+  return response.data;
+};
+
+export const updateRoom = async (id: string, data: RoomInput): Promise<Room> => {
+  const response = await api.put<Room>(`/admin/rooms/${id}`, data);
+  return response.data;
+};
+
+export const deleteRoom = async (id: string): Promise<void> => {
+  await api.delete(`/admin/rooms/${id}`);
+};
+
+export const getTracks = async (): Promise<Track[]> => {
+  const response = await api.get<Track[]>('/tracks');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const createTrack = async (data: TrackInput): Promise<Track> => {
+  const response = await api.post<Track>('/admin/tracks', data);
+  return response.data;
+};
+
+export const updateTrack = async (id: string, data: TrackInput): Promise<Track> => {
+  const response = await api.put<Track>(`/admin/tracks/${id}`, data);
+  return response.data;
+};
+
+export const deleteTrack = async (id: string): Promise<void> => {
+  await api.delete(`/admin/tracks/${id}`);
+};
+
 // Admin
 export const adminLogin = async (password: string): Promise<void> => {
   await api.post('/admin/login', { password });
 };
 
-export const getAdminStats = async (): Promise<DesignationStats[]> => {
-  const response = await api.get<AdminStats>('/admin/stats');
-  return response.data.stats;
+export const getAdminStats = async (
+  filters: StatsFilters = {}
+): Promise<Omit<AdminStats, 'stats'> & { designations: DesignationStats[] }> => {
+  const response = await api.get<AdminStats>('/admin/stats', {
+    params: filters,
+    // designation is repeated rather than sent as designation[]
+    paramsSerializer: { indexes: null },
+  });
+  const { stats, ...rest } = response.data;
+  return { ...rest, designations: stats ?? [], questions: rest.questions ?? [] };
+};
+
+// Designations
+export const getDesignationOptions = async (): Promise<string[]> => {
+  const response = await api.get<string[]>('/designations');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+type DesignationInput = Omit<Designation, 'id'>;
+
+export const getDesignations = async (): Promise<Designation[]> => {
+  const response = await api.get<Designation[]>('/admin/designations');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const createDesignation = async (data: DesignationInput): Promise<Designation> => {
+  const response = await api.post<Designation>('/admin/designations', data);
+  return response.data;
+};
+
+export const updateDesignation = async (id: string, data: DesignationInput): Promise<Designation> => {
+  const response = await api.put<Designation>(`/admin/designations/${id}`, data);
+  return response.data;
+};
+
+export const deleteDesignation = async (id: string): Promise<void> => {
+  await api.delete(`/admin/designations/${id}`);
+};
+
+export const backfillDesignations = async (): Promise<DesignationBackfill> => {
+  const response = await api.post<DesignationBackfill>('/admin/designations/backfill');
+  return response.data;
+};
+
+// Outbound webhooks
+interface WebhookInput {
+  url: string;
+  events: WebhookEvent[];
+  active: boolean;
+}
+
+export const getWebhooks = async (): Promise<Webhook[]> => {
+  const response = await api.get<Webhook[]>('/admin/webhooks');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const createWebhook = async (data: WebhookInput): Promise<Webhook> => {
+  const response = await api.post<Webhook>('/admin/webhooks', data);
+  return response.data;
+};
+
+export const updateWebhook = async (id: string, data: WebhookInput): Promise<Webhook> => {
+  const response = await api.put<Webhook>(`/admin/webhooks/${id}`, data);
+  return response.data;
+};
+
+export const deleteWebhook = async (id: string): Promise<void> => {
+  await api.delete(`/admin/webhooks/${id}`);
+};
+
+export const testWebhook = async (id: string): Promise<WebhookDelivery> => {
+  const response = await api.post<WebhookDelivery>(`/admin/webhooks/${id}/test`);
+  return response.data;
+};
+
+export const getWebhookDeliveries = async (id: string): Promise<WebhookDelivery[]> => {
+  const response = await api.get<WebhookDelivery[]>(`/admin/webhooks/${id}/deliveries`);
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const replayWebhookDelivery = async (id: string, deliveryId: string): Promise<WebhookDelivery> => {
+  const response = await api.post<WebhookDelivery>(`/admin/webhooks/${id}/deliveries/${deliveryId}/replay`);
+  return response.data;
+};
+
+// Custom registration questions
+type QuestionInput = Omit<Question, 'id'>;
+
+export const getQuestions = async (): Promise<Question[]> => {
+  const response = await api.get<Question[]>('/admin/questions');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const createQuestion = async (data: QuestionInput): Promise<Question> => {
+  const response = await api.post<Question>('/admin/questions', data);
+  return response.data;
+};
+
+// The key and type of a question cannot change once created.
+export const updateQuestion = async (
+  id: string,
+  data: Omit<QuestionInput, 'key' | 'type'>
+): Promise<Question> => {
+  const response = await api.put<Question>(`/admin/questions/${id}`, data);
+  return response.data;
+};
+
+export const deleteQuestion = async (id: string): Promise<void> => {
+  await api.delete(`/admin/questions/${id}`);
+};
+
+// Live updates over server-sent events. The browser reconnects and resumes
+// by itself; onUnavailable is called when it gives up, for example when the
+// server is at its stream limit, so callers can fall back to polling.
+export interface LiveEventHandlers {
+  count?: (count: number) => void;
+  registration?: (attendee: Attendee) => void; // admin streams only
+  agenda?: (change: AgendaChange) => void;
+  question?: (change: QuestionChange) => void;
+  moderation?: (change: QuestionChange<LiveQuestion>) => void; // admin streams only
+  reset?: () => void; // events were missed; reload
+  onUnavailable?: () => void;
+}
+
+export const subscribeToEvents = (handlers: LiveEventHandlers, admin = false): (() => void) => {
+  if (typeof EventSource === 'undefined') {
+    handlers.onUnavailable?.();
+    return () => {};
+  }
+
+  const source = new EventSource(`${API_URL}${admin ? '/admin/events' : '/events'}`, {
+    withCredentials: true,
+  });
+  const listen = <T>(type: string, fn?: (data: T) => void) => {
+    if (fn) {
+      source.addEventListener(type, (e) => fn(JSON.parse((e as MessageEvent).data)));
+    }
+  };
+  listen<AttendeeCount>('count', (data) => handlers.count?.(data.count));
+  listen('registration', handlers.registration);
+  listen('agenda', handlers.agenda);
+  listen('question', handlers.question);
+  listen('moderation', handlers.moderation);
+  listen('reset', () => handlers.reset?.());
+  source.onerror = () => {
+    if (source.readyState === EventSource.CLOSED) {
+      handlers.onUnavailable?.();
+    }
+  };
+
+  return () => source.close();
+};
+
+// Data export and erasure
+export const getPrivacyRequests = async (): Promise<PrivacyRequest[]> => {
+  const response = await api.get<PrivacyRequest[]>('/admin/privacy/requests');
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const exportPersonalData = async (email: string): Promise<DataExport> => {
+  const response = await api.post<DataExport>('/admin/privacy/export', { email });
+  return response.data;
+};
+
+export const erasePersonalData = async (
+  subject: { email: string } | { requestId: string }
+): Promise<PrivacyRequest> => {
+  const response = await api.post<PrivacyRequest>('/admin/privacy/erasure', subject);
+  return response.data;
+};
+
+// downloadJSON saves data as a file in the browser.
+export const downloadJSON = (data: unknown, filename: string): void => {
+  const blob = new Blob([JSON.stringify(data, null, 2)], { type: 'application/json' });
+  const url = URL.createObjectURL(blob);
+  const link = document.createElement('a');
+  link.href = url;
+  link.download = filename;
+  link.click();
+  URL.revokeObjectURL(url);
+};
+
+// Session feedback. Speakers read theirs with a token from a link an admin
+// created, sent like the self-service token.
+export const getFeedbackSummaries = async (session?: string): Promise<FeedbackSummary[]> => {
+  const response = await api.get<FeedbackSummary[]>('/admin/feedback', { params: { session } });
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const exportFeedback = async (session?: string): Promise<Blob> => {
+  const response = await api.get('/admin/feedback/export', { params: { session }, responseType: 'blob' });
+  return response.data;
+};
+
+export const createSpeakerFeedbackLink = async (speakerId: string): Promise<SpeakerLink> => {
+  const response = await api.post<SpeakerLink>(`/admin/speakers/${speakerId}/feedback-link`);
+  return response.data;
+};
+
+export const getSpeakerFeedback = async (token: string): Promise<FeedbackSummary[]> => {
+  const response = await api.get<FeedbackSummary[]>('/speakers/me/feedback', { headers: selfServiceHeaders(token) });
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const exportSpeakerFeedback = async (token: string): Promise<Blob> => {
+  const response = await api.get('/speakers/me/feedback/export', {
+    headers: selfServiceHeaders(token),
+    responseType: 'blob',
+  });
+  return response.data;
+};
+
+// Live Q&A. Attendees ask and vote with their self-service token; reading the
+// feed with it marks the questions they upvoted.
+export const getQuestions = async (sessionId: string, token?: string | null): Promise<PublicQuestion[]> => {
+  const response = await api.get<PublicQuestion[]>(`/sessions/${sessionId}/questions`, {
+    headers: token ? selfServiceHeaders(token) : undefined,
+  });
+  return Array.isArray(response.data) ? response.data : [];
 };
 
+export const askQuestion = async (
+  token: string,
+  sessionId: string,
+  data: { text: string; anonymous: boolean }
+): Promise<LiveQuestion> => {
+  const response = await api.post<LiveQuestion>(`/sessions/${sessionId}/questions`, data, {
+    headers: selfServiceHeaders(token),
+  });
+  return response.data;
+};
+
+export const voteQuestion = async (
+  token: string,
+  sessionId: string,
+  questionId: string,
+  up: boolean
+): Promise<PublicQuestion> => {
+  const url = `/sessions/${sessionId}/questions/${questionId}/vote`;
+  const config = { headers: selfServiceHeaders(token) };
+  const response = up ? await api.put<PublicQuestion>(url, null, config) : await api.delete<PublicQuestion>(url, config);
+  return response.data;
+};
+
+export const getModerationQueue = async (sessionId: string, status?: string): Promise<LiveQuestion[]> => {
+  const response = await api.get<LiveQuestion[]>(`/admin/sessions/${sessionId}/questions`, {
+    params: { status: status || undefined },
+  });
+  return Array.isArray(response.data) ? response.data : [];
+};
+
+export const moderateQuestion = async (
+  sessionId: string,
+  questionId: string,
+  data: { status?: 'approved' | 'hidden'; pinned?: boolean; answered?: boolean }
+): Promise<LiveQuestion> => {
+  const response = await api.put<LiveQuestion>(`/admin/sessions/${sessionId}/questions/${questionId}`, data);
+  return response.data;
+};
+
+// downloadBlob saves a downloaded file in the browser.
+export const downloadBlob = (blob: Blob, filename: string): void => {
+  const url = URL.createObjectURL(blob);
+  const link = document.createElement('a');
+  link.href = url;
+  link.download = filename;
+  link.click();
+  URL.revokeObjectURL(url);
+};
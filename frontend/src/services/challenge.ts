@@ -0,0 +1,36 @@
+// Solves the registration form's proof-of-work challenge: find a solution
+// such that SHA-256(token + ":" + solution) starts with `difficulty` zero
+// bits. At the default difficulty this takes a few seconds, so the form
+// starts solving as soon as it loads.
+
+const leadingZeroBits = (digest: ArrayBuffer): number => {
+  let zeros = 0;
+  for (const byte of new Uint8Array(digest)) {
+    if (byte === 0) {
+      zeros += 8;
+      continue;
+    }
+    return zeros + Math.clz32(byte) - 24;
+  }
+  return zeros;
+};
+
+// Hashes are computed in batches so the page stays responsive.
+const BATCH = 512;
+
+export const solveChallenge = async (
+  token: string,
+  difficulty: number,
+  signal?: AbortSignal
+): Promise<string> => {
+  const encoder = new TextEncoder();
+  for (let start = 0; ; start += BATCH) {
+    if (signal?.aborted) throw new DOMException('Challenge abandoned', 'AbortError');
+    const candidates = Array.from({ length: BATCH }, (_, i) => String(start + i));
+    const digests = await Promise.all(
+      candidates.map((solution) => crypto.subtle.digest('SHA-256', encoder.encode(`${token}:${solution}`)))
+    );
+    const found = digests.findIndex((digest) => leadingZeroBits(digest) >= difficulty);
+    if (found >= 0) return candidates[found];
+  }
+};
@@ -0,0 +1,16 @@
+// Trace context for API requests. Each request starts a W3C trace in the
+// browser and sends it as `traceparent`, so the server's spans for it,
+// Firestore calls included, join one trace that begins on this page. The
+// browser's own span is not exported; collectors show it as the remote
+// parent of the server span.
+
+const hex = (bytes: number): string =>
+  Array.from(crypto.getRandomValues(new Uint8Array(bytes)), (b) => b.toString(16).padStart(2, '0')).join('');
+
+// newTraceparent returns a sampled `traceparent` header value with a fresh
+// trace and span ID.
+export const newTraceparent = (): string => `00-${hex(16)}-${hex(8)}-01`;
+
+// traceId extracts the trace ID from a `traceparent` value, e.g. to quote
+// it in a bug report.
+export const traceId = (traceparent: string): string => traceparent.split('-')[1] ?? '';
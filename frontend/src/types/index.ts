@@ -3,7 +3,61 @@ export interface Attendee {
   name: string;
   email: string;
   designation: string;
+  listPublicly: boolean;
+  status: '' | 'pending' | 'confirmed' | 'cancelled';
   registeredAt: string;
+  updatedAt?: string;
+  checkedInAt?: string;
+  answers?: Answers;
+  erasedAt?: string;
+}
+
+// An attendee search result; higher scores are better matches.
+export interface AttendeeMatch extends Attendee {
+  score: number;
+}
+
+export interface AttendeeSearchResult {
+  results: AttendeeMatch[];
+  total: number;
+}
+
+// Answers to custom questions keyed by question key: a string for text and
+// single-choice questions, a list for multi-choice and a boolean for boolean.
+export type AnswerValue = string | string[] | boolean;
+export type Answers = Record<string, AnswerValue>;
+
+export type QuestionType = 'text' | 'single_choice' | 'multi_choice' | 'boolean';
+
+// A custom registration question defined by an admin.
+export interface Question {
+  id: string;
+  key: string;
+  label: string;
+  helpText?: string;
+  type: QuestionType;
+  required: boolean;
+  options?: string[];
+  order: number;
+  minLength?: number;
+  maxLength?: number;
+  pattern?: string;
+  minSelections?: number;
+  maxSelections?: number;
+}
+
+export interface QuestionStats {
+  key: string;
+  label: string;
+  type: QuestionType;
+  answered: number;
+  counts?: { value: string; count: number }[];
+}
+
+// What anyone may see about an attendee who opted in to the public list.
+export interface PublicAttendee {
+  firstName: string;
+  designation: string;
 }
 
 export interface AttendeeCount {
@@ -25,6 +79,68 @@ export interface Session {
   time: string;
   duration: string;
   speakerIds: string[];
+  startsAt?: string;
+  endsAt?: string;
+  trackId?: string;
+  roomId?: string;
+  tags?: string[];
+  // Present when requested with expand=speakers
+  speakers?: Speaker[];
+}
+
+// A room at a venue. A capacity of 0 means it is not known.
+export interface Room {
+  id: string;
+  venueId: string;
+  name: string;
+  capacity: number;
+  accessibility?: string;
+}
+
+export interface Venue {
+  id: string;
+  name: string;
+  address: string;
+  directions?: string;
+  mapUrl?: string;
+  // Listed by GET /venues
+  rooms?: Room[];
+}
+
+export interface Track {
+  id: string;
+  name: string;
+  description?: string;
+  color?: string;
+  order: number;
+}
+
+// The agenda grouped by day, then track, then room. track and room are null
+// for sessions without one.
+export interface AgendaGrid {
+  days: {
+    date: string;
+    tracks: {
+      track: Track | null;
+      rooms: { room: Room | null; sessions: Session[] }[];
+    }[];
+  }[];
+  unscheduled: Session[];
+}
+
+// A session or speaker change pushed on the live event stream. data is
+// absent when it was removed.
+export type AgendaChange =
+  | { collection: 'sessions'; change: 'added' | 'modified' | 'removed'; id: string; data?: Session }
+  | { collection: 'speakers'; change: 'added' | 'modified' | 'removed'; id: string; data?: Speaker };
+
+// Bot protection for the registration form: a proof-of-work challenge, when
+// token is set, and an external CAPTCHA widget, when captchaSiteKey is set.
+export interface RegistrationChallenge {
+  token?: string;
+  difficulty: number;
+  expiresAt: string;
+  captchaSiteKey?: string;
 }
 
 export interface DesignationStats {
@@ -32,7 +148,201 @@ export interface DesignationStats {
   count: number;
 }
 
+// A canonical designation; values matching an alias are stored as the name.
+export interface Designation {
+  id: string;
+  name: string;
+  aliases?: string[];
+  order: number;
+}
+
+export const WEBHOOK_EVENTS = [
+  'attendee.created',
+  'attendee.updated',
+  'attendee.cancelled',
+  'session.created',
+  'session.updated',
+  'session.deleted',
+  'speaker.created',
+  'speaker.updated',
+  'speaker.deleted',
+] as const;
+
+export type WebhookEvent = (typeof WEBHOOK_EVENTS)[number];
+
+// An outbound webhook. secret is only returned when it is created.
+export interface Webhook {
+  id: string;
+  url: string;
+  events: WebhookEvent[];
+  active: boolean;
+  secret?: string;
+  createdAt: string;
+}
+
+export interface WebhookDelivery {
+  id: string;
+  webhookId: string;
+  eventId: string;
+  event: string;
+  payload: string;
+  status: 'pending' | 'succeeded' | 'failed';
+  attempts: number;
+  nextAttemptAt?: string;
+  createdAt: string;
+  leasedUntil?: string;
+  lastAttemptAt?: string;
+  responseStatus?: number;
+  error?: string;
+  durationMs?: number;
+}
+
+export interface DesignationBackfill {
+  scanned: number;
+  updated: number;
+  // Designations matching no name or alias, with how many registrations use each
+  unmatched: Record<string, number>;
+}
+
+// Registrations from sign-up to attendance. checkInRate is checkedIn over
+// confirmed, from 0 to 1.
+export interface Funnel {
+  registered: number;
+  pending: number;
+  confirmed: number;
+  cancelled: number;
+  checkedIn: number;
+  checkInRate: number;
+}
+
+export type StatsInterval = 'hour' | 'day';
+
+export interface TimelineBucket {
+  start: string;
+  registrations: number;
+  cancelled: number;
+  cumulative: number;
+}
+
+export interface Timeline {
+  interval: StatsInterval;
+  timeZone: string;
+  buckets: TimelineBucket[];
+}
+
+// Narrows admin stats; from and to are inclusive YYYY-MM-DD dates in the
+// event time zone.
+export interface StatsFilters {
+  from?: string;
+  to?: string;
+  designation?: string[];
+  interval?: StatsInterval;
+}
+
 export interface AdminStats {
-  stats: DesignationStats[];
+  stats: DesignationStats[] | null;
+  questions: QuestionStats[];
+  funnel: Funnel;
+  timeline: Timeline;
+  generatedAt: string;
+}
+
+// Compliance record of a data export or erasure request.
+export interface PrivacyRequest {
+  id: string;
+  type: 'export' | 'erasure';
+  status: 'pending' | 'completed';
+  source: 'self-service' | 'admin';
+  email?: string;
+  emailHash: string;
+  requestedAt: string;
+  completedAt?: string;
+  records?: Record<string, number>;
 }
 
+export interface DataExport {
+  email: string;
+  generatedAt: string;
+  collections: Record<string, Record<string, unknown>[]>;
+}
+
+// A record that an attendee was at a session. Only attendees checked in to a
+// session can rate it. name is only set in the admin list.
+export interface SessionAttendance {
+  id: string;
+  sessionId: string;
+  attendeeId: string;
+  checkedInAt: string;
+  name?: string;
+}
+
+// An attendee's rating of a session. It can be changed until editableUntil.
+export interface Feedback {
+  id: string;
+  sessionId: string;
+  attendeeId: string;
+  rating: number;
+  comment?: string;
+  submittedAt: string;
+  updatedAt?: string;
+  editableUntil?: string;
+}
+
+export interface FeedbackComment {
+  rating: number;
+  comment: string;
+  submittedAt: string;
+}
+
+export interface FeedbackSummary {
+  sessionId: string;
+  title: string;
+  count: number;
+  average: number;
+  // Number of ratings of 1 to 5
+  distribution: number[];
+  comments: FeedbackComment[];
+}
+
+export interface SpeakerLink {
+  url: string;
+  expiresAt: string;
+}
+
+// A live Q&A question as moderators see it. authorName is kept for anonymous
+// questions too; only the audience view drops it.
+export interface LiveQuestion {
+  id: string;
+  sessionId: string;
+  attendeeId: string;
+  authorName?: string;
+  anonymous: boolean;
+  text: string;
+  status: 'pending' | 'approved' | 'hidden';
+  pinned: boolean;
+  answeredAt?: string;
+  votes: number;
+  createdAt: string;
+}
+
+// An approved question as the audience sees it.
+export interface PublicQuestion {
+  id: string;
+  sessionId: string;
+  authorName?: string;
+  text: string;
+  votes: number;
+  pinned: boolean;
+  answeredAt?: string;
+  createdAt: string;
+  voted?: boolean;
+}
+
+// data is a PublicQuestion in question events and a LiveQuestion in
+// moderation events; it is absent when the question was removed.
+export interface QuestionChange<T = PublicQuestion> {
+  change: 'added' | 'modified' | 'removed';
+  id: string;
+  sessionId?: string;
+  data?: T;
+}